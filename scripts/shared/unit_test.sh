@@ -32,8 +32,8 @@ for module in "${modules[@]}"; do
     (
         cd "$module"
 
-        # Exclude any directories containing e2e tests
-        for dir in $(git grep -w -l e2e | grep _test.go | sed 's#\(.*/.*\)/.*$#\1#' | sort -u); do
+        # Exclude any directories containing e2e tests, i.e. running the e2e suite; unit tests of the e2e framework are kept
+        for dir in $(git grep -w -l RunE2ETests -- '*_test.go' | sed 's#\(.*/.*\)/.*$#\1#' | sort -u); do
             exclude_args+=(-path "./${dir}" -prune -o)
         done
 
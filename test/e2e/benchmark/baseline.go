@@ -0,0 +1,318 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+)
+
+// BaselineVersion is the version of the baseline file format written by this package. Files with a later version are
+// rejected rather than misread.
+const BaselineVersion = 1
+
+// ClusterTopology describes the parts of a cluster's environment which affect the dataplane performance. Component
+// versions and images are deliberately left out, so that releases can be compared with each other.
+type ClusterTopology struct {
+	ID               string `json:"id"`
+	NumNodes         int    `json:"numNodes"`
+	Provider         string `json:"provider"`
+	CableDriver      string `json:"cableDriver"`
+	GlobalnetEnabled bool   `json:"globalnetEnabled"`
+	FIPSEnabled      bool   `json:"fipsEnabled"`
+}
+
+type Topology struct {
+	Clusters []ClusterTopology `json:"clusters"`
+}
+
+// BaselineFile holds the benchmark baselines, one per topology. It's meant to be checked into the repositories running
+// the benchmark, and refreshed from a run with the --benchmark-refresh-baseline flag or the refresh-baseline command.
+type BaselineFile struct {
+	Version   int        `json:"version"`
+	Baselines []Baseline `json:"baselines"`
+}
+
+// Baseline holds the reference results of the benchmark cells for a topology, keyed by CellResult.Key.
+type Baseline struct {
+	TopologyFingerprint string                  `json:"topologyFingerprint"`
+	Topology            Topology                `json:"topology"`
+	Cells               map[string]BaselineCell `json:"cells"`
+}
+
+type BaselineCell struct {
+	Unit     string  `json:"unit"`
+	Mean     float64 `json:"mean"`
+	Variance float64 `json:"variance"`
+	Samples  int     `json:"samples"`
+}
+
+// Tolerances are the relative changes from the baseline considered acceptable, e.g. a Throughput tolerance of 0.1
+// accepts a throughput up to 10% below the baseline.
+type Tolerances struct {
+	Throughput float64
+	Latency    float64
+}
+
+// Regression is a benchmark cell whose mean is worse than its baseline beyond the tolerance, or which wasn't measured.
+type Regression struct {
+	Key          string
+	Unit         string
+	BaselineMean float64
+	Mean         float64
+	// Change is the relative change from the baseline mean, negative for a drop.
+	Change float64
+	// NotMeasured is why the cell has no mean in the report, in which case Mean and Change are zero.
+	NotMeasured string
+}
+
+// CurrentTopology returns the topology of the clusters under test. Collection is best-effort, as for
+// framework.FingerprintEnvironment.
+func CurrentTopology(ctx context.Context) Topology {
+	fingerprint := framework.FingerprintEnvironment(ctx)
+	topology := Topology{Clusters: make([]ClusterTopology, len(fingerprint.Clusters))}
+
+	for i := range fingerprint.Clusters {
+		c := &fingerprint.Clusters[i]
+		topology.Clusters[i] = ClusterTopology{
+			ID:               c.ID,
+			NumNodes:         c.NumNodes,
+			Provider:         c.Provider,
+			CableDriver:      c.CableDriver,
+			GlobalnetEnabled: c.GlobalnetEnabled,
+			FIPSEnabled:      c.FIPSEnabled,
+		}
+	}
+
+	return topology
+}
+
+// Fingerprint returns a short hash identifying the topology.
+func (t *Topology) Fingerprint() string {
+	data, err := json.Marshal(t)
+	if err != nil {
+		// This can't happen with the plain types the topology is made of.
+		panic(err)
+	}
+
+	hash := sha256.Sum256(data)
+
+	return hex.EncodeToString(hash[:6])
+}
+
+// LoadBaselineFile reads the baseline file at the given path. A missing file yields an empty BaselineFile, so that it
+// can be created by refreshing it.
+func LoadBaselineFile(path string) (*BaselineFile, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &BaselineFile{Version: BaselineVersion, Baselines: []Baseline{}}, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading the baseline file %q", path)
+	}
+
+	file := &BaselineFile{}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, errors.Wrapf(err, "error parsing the baseline file %q", path)
+	}
+
+	if file.Version < 1 || file.Version > BaselineVersion {
+		return nil, fmt.Errorf("the baseline file %q has version %d, only versions up to %d are supported", path, file.Version,
+			BaselineVersion)
+	}
+
+	return file, nil
+}
+
+// Save writes the baseline file to the given path, in the current format version.
+func (b *BaselineFile) Save(path string) error {
+	b.Version = BaselineVersion
+
+	sort.Slice(b.Baselines, func(i, j int) bool {
+		return b.Baselines[i].TopologyFingerprint < b.Baselines[j].TopologyFingerprint
+	})
+
+	// The cell keys contain "->", which is kept readable rather than HTML-escaped.
+	data := &bytes.Buffer{}
+	encoder := json.NewEncoder(data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(b); err != nil {
+		return errors.Wrap(err, "error marshaling the baseline file")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return errors.Wrapf(err, "error creating directory %q", filepath.Dir(path))
+	}
+
+	return errors.Wrapf(os.WriteFile(path, data.Bytes(), 0o600), "error writing the baseline file %q", path)
+}
+
+// ForTopology returns the baseline for the topology with the given fingerprint, or nil if there's none.
+func (b *BaselineFile) ForTopology(fingerprint string) *Baseline {
+	for i := range b.Baselines {
+		if b.Baselines[i].TopologyFingerprint == fingerprint {
+			return &b.Baselines[i]
+		}
+	}
+
+	return nil
+}
+
+// Update merges the report's results into the baseline for the report's topology, creating it if needed. Cells which
+// were skipped keep their existing baseline, if any. Reports with failed cells are refused, as a partial or broken run
+// isn't a meaningful reference.
+func (b *BaselineFile) Update(report *Report) error {
+	if failures := report.Failures(); len(failures) > 0 {
+		return fmt.Errorf("the baseline can't be refreshed from a report with %d failed cell(s):\n%s", len(failures),
+			strings.Join(failures, "\n"))
+	}
+
+	fingerprint := report.Topology.Fingerprint()
+
+	baseline := b.ForTopology(fingerprint)
+	if baseline == nil {
+		b.Baselines = append(b.Baselines, Baseline{TopologyFingerprint: fingerprint})
+		baseline = &b.Baselines[len(b.Baselines)-1]
+	}
+
+	baseline.Topology = report.Topology
+
+	if baseline.Cells == nil {
+		baseline.Cells = map[string]BaselineCell{}
+	}
+
+	for i := range report.Results {
+		result := &report.Results[i]
+		if result.Skipped != "" {
+			continue
+		}
+
+		baseline.Cells[result.Key()] = BaselineCell{
+			Unit:     result.Unit,
+			Mean:     result.Mean,
+			Variance: result.Variance,
+			Samples:  len(result.Samples),
+		}
+	}
+
+	return nil
+}
+
+// Compare returns the cells of the report which regressed from the baseline beyond the tolerances: throughput below, or
+// latency above, the baseline mean. Cells of the baseline which weren't measured in the report, because they failed,
+// were skipped or are missing, are regressions too. Cells which aren't in the baseline aren't compared.
+func (b *Baseline) Compare(report *Report, tolerances Tolerances) []Regression {
+	regressions := []Regression{}
+	compared := map[string]bool{}
+
+	for i := range report.Results {
+		result := &report.Results[i]
+
+		cell, found := b.Cells[result.Key()]
+		if !found {
+			continue
+		}
+
+		compared[result.Key()] = true
+
+		if notMeasured := notMeasuredReason(result); notMeasured != "" {
+			regressions = append(regressions, Regression{
+				Key: result.Key(), Unit: cell.Unit, BaselineMean: cell.Mean, NotMeasured: notMeasured,
+			})
+
+			continue
+		}
+
+		if cell.Mean == 0 {
+			continue
+		}
+
+		change := (result.Mean - cell.Mean) / cell.Mean
+
+		regressed := change < -tolerances.Throughput
+		if result.Metric == Latency {
+			regressed = change > tolerances.Latency
+		}
+
+		if regressed {
+			regressions = append(regressions, Regression{
+				Key:          result.Key(),
+				Unit:         result.Unit,
+				BaselineMean: cell.Mean,
+				Mean:         result.Mean,
+				Change:       change,
+			})
+		}
+	}
+
+	missing := []string{}
+
+	for key := range b.Cells {
+		if !compared[key] {
+			missing = append(missing, key)
+		}
+	}
+
+	sort.Strings(missing)
+
+	for _, key := range missing {
+		regressions = append(regressions, Regression{
+			Key: key, Unit: b.Cells[key].Unit, BaselineMean: b.Cells[key].Mean, NotMeasured: "missing from the report",
+		})
+	}
+
+	return regressions
+}
+
+// notMeasuredReason returns why the result has no mean to compare, or an empty string if it has one.
+func notMeasuredReason(result *CellResult) string {
+	if result.Skipped != "" {
+		return "skipped: " + result.Skipped
+	}
+
+	if len(result.Samples) == 0 {
+		return "failed: " + result.failure()
+	}
+
+	return ""
+}
+
+// String formats the regression for logging, e.g. "throughput/east->west/gateway/pod/pod-ip: 8.5e+08 bits/sec is -15.0%
+// from the baseline 1e+09 bits/sec".
+func (r *Regression) String() string {
+	if r.NotMeasured != "" {
+		return fmt.Sprintf("%s: not measured (%s), the baseline is %.4g %s", r.Key, r.NotMeasured, r.BaselineMean, r.Unit)
+	}
+
+	return fmt.Sprintf("%s: %.4g %s is %+.1f%% from the baseline %.4g %s", r.Key, r.Mean, r.Unit, r.Change*100, r.BaselineMean, r.Unit)
+}
@@ -0,0 +1,267 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/benchmark"
+)
+
+var _ = Describe("Topology", func() {
+	topology := func() benchmark.Topology {
+		return benchmark.Topology{Clusters: []benchmark.ClusterTopology{
+			{ID: "east", NumNodes: 3, Provider: "kind", CableDriver: "libreswan"},
+			{ID: "west", NumNodes: 3, Provider: "kind", CableDriver: "libreswan"},
+		}}
+	}
+
+	It("should have a stable fingerprint", func() {
+		t := topology()
+		fingerprint := t.Fingerprint()
+
+		Expect(fingerprint).To(MatchRegexp("^[0-9a-f]{12}$"))
+		Expect(t.Fingerprint()).To(Equal(fingerprint))
+
+		other := topology()
+		Expect(other.Fingerprint()).To(Equal(fingerprint))
+	})
+
+	It("should have a different fingerprint if the topology differs", func() {
+		t := topology()
+		other := topology()
+		other.Clusters[1].CableDriver = "wireguard"
+
+		Expect(other.Fingerprint()).NotTo(Equal(t.Fingerprint()))
+	})
+})
+
+var _ = Describe("LoadBaselineFile", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "baseline.json")
+	})
+
+	writeFile := func(contents string) {
+		Expect(os.WriteFile(path, []byte(contents), 0o600)).To(Succeed())
+	}
+
+	It("should return an empty baseline file if it doesn't exist", func() {
+		Expect(benchmark.LoadBaselineFile(path)).To(Equal(&benchmark.BaselineFile{
+			Version: benchmark.BaselineVersion, Baselines: []benchmark.Baseline{},
+		}))
+	})
+
+	It("should read a saved baseline file", func() {
+		file := &benchmark.BaselineFile{Baselines: []benchmark.Baseline{{
+			TopologyFingerprint: "abc",
+			Cells:               map[string]benchmark.BaselineCell{"throughput/east->west/gateway/pod/pod-ip": {Mean: 1e9}},
+		}}}
+		Expect(file.Save(path)).To(Succeed())
+
+		Expect(benchmark.LoadBaselineFile(path)).To(Equal(file))
+	})
+
+	DescribeTable("should reject an unsupported version",
+		func(contents string) {
+			writeFile(contents)
+
+			_, err := benchmark.LoadBaselineFile(path)
+			Expect(err).To(MatchError(ContainSubstring("only versions up to 1 are supported")))
+		},
+		Entry("a later version", `{"version": 2, "baselines": []}`),
+		Entry("no version", `{"baselines": []}`),
+	)
+
+	It("should return an error if the file isn't JSON", func() {
+		writeFile("version: 1")
+
+		_, err := benchmark.LoadBaselineFile(path)
+		Expect(err).To(MatchError(ContainSubstring("error parsing the baseline file")))
+	})
+})
+
+var _ = Describe("Baseline", func() {
+	const (
+		throughputKey = "throughput/east->west/gateway/pod/pod-ip"
+		latencyKey    = "latency/east->west/gateway/pod/pod-ip"
+	)
+
+	tolerances := benchmark.Tolerances{Throughput: 0.1, Latency: 0.2}
+
+	result := func(metric benchmark.Metric, mean float64) benchmark.CellResult {
+		unit := "bits/sec"
+		if metric == benchmark.Latency {
+			unit = "microseconds"
+		}
+
+		return benchmark.CellResult{
+			Metric:      metric,
+			FromCluster: "east",
+			ToCluster:   "west",
+			Scheduling:  "gateway",
+			Networking:  "pod",
+			Endpoint:    benchmark.PodIP,
+			Unit:        unit,
+			Samples:     []float64{mean},
+			Mean:        mean,
+		}
+	}
+
+	failed := func(metric benchmark.Metric) benchmark.CellResult {
+		r := result(metric, 0)
+		r.Samples = []float64{}
+		r.Errors = []string{"connection refused"}
+
+		return r
+	}
+
+	var baseline *benchmark.Baseline
+
+	BeforeEach(func() {
+		baseline = &benchmark.Baseline{Cells: map[string]benchmark.BaselineCell{
+			throughputKey: {Unit: "bits/sec", Mean: 1000, Samples: 3},
+			latencyKey:    {Unit: "microseconds", Mean: 100, Samples: 3},
+		}}
+	})
+
+	Describe("Compare", func() {
+		DescribeTable("the tolerances",
+			func(throughput, latency float64, expected []string) {
+				regressions := baseline.Compare(&benchmark.Report{Results: []benchmark.CellResult{
+					result(benchmark.Throughput, throughput), result(benchmark.Latency, latency),
+				}}, tolerances)
+
+				keys := []string{}
+				for i := range regressions {
+					keys = append(keys, regressions[i].Key)
+				}
+
+				Expect(keys).To(Equal(expected))
+			},
+			Entry("unchanged", 1000.0, 100.0, []string{}),
+			Entry("improved", 2000.0, 50.0, []string{}),
+			Entry("within the tolerances", 900.0, 120.0, []string{}),
+			Entry("throughput below the tolerance", 899.0, 100.0, []string{throughputKey}),
+			Entry("latency above the tolerance", 1000.0, 121.0, []string{latencyKey}),
+			Entry("both beyond the tolerances", 500.0, 200.0, []string{throughputKey, latencyKey}),
+		)
+
+		It("should describe the regression", func() {
+			regressions := baseline.Compare(&benchmark.Report{Results: []benchmark.CellResult{
+				result(benchmark.Throughput, 850), result(benchmark.Latency, 100),
+			}}, tolerances)
+
+			Expect(regressions).To(HaveLen(1))
+			Expect(regressions[0].Change).To(BeNumerically("~", -0.15, 1e-9))
+			Expect(regressions[0].String()).To(Equal(throughputKey + ": 850 bits/sec is -15.0% from the baseline 1000 bits/sec"))
+		})
+
+		It("should ignore cells which aren't in the baseline", func() {
+			other := result(benchmark.Throughput, 1)
+			other.Endpoint = benchmark.ServiceIP
+
+			Expect(baseline.Compare(&benchmark.Report{Results: []benchmark.CellResult{
+				result(benchmark.Throughput, 1000), result(benchmark.Latency, 100), other,
+			}}, tolerances)).To(BeEmpty())
+		})
+
+		It("should report the baseline cells which weren't measured", func() {
+			skipped := result(benchmark.Latency, 0)
+			skipped.Samples = []float64{}
+			skipped.Skipped = "Globalnet isn't enabled"
+
+			regressions := baseline.Compare(&benchmark.Report{Results: []benchmark.CellResult{skipped}}, tolerances)
+
+			Expect(regressions).To(HaveLen(2))
+			Expect(regressions[0].String()).To(Equal(latencyKey +
+				": not measured (skipped: Globalnet isn't enabled), the baseline is 100 microseconds"))
+			Expect(regressions[1].String()).To(Equal(throughputKey + ": not measured (missing from the report), the baseline is 1000 bits/sec"))
+		})
+
+		It("should report every baseline cell if all the cells failed", func() {
+			regressions := baseline.Compare(&benchmark.Report{Results: []benchmark.CellResult{
+				failed(benchmark.Throughput), failed(benchmark.Latency),
+			}}, tolerances)
+
+			Expect(regressions).To(HaveLen(2))
+			Expect(regressions[0].NotMeasured).To(Equal("failed: connection refused"))
+			Expect(regressions[1].NotMeasured).To(Equal("failed: connection refused"))
+		})
+
+		It("should report every baseline cell if the report is empty", func() {
+			Expect(baseline.Compare(&benchmark.Report{}, tolerances)).To(HaveLen(2))
+		})
+	})
+
+	Describe("Update", func() {
+		var file *benchmark.BaselineFile
+
+		BeforeEach(func() {
+			file = &benchmark.BaselineFile{Version: benchmark.BaselineVersion, Baselines: []benchmark.Baseline{}}
+		})
+
+		It("should add a baseline for a new topology", func() {
+			report := &benchmark.Report{Results: []benchmark.CellResult{result(benchmark.Throughput, 1000)}}
+			Expect(file.Update(report)).To(Succeed())
+
+			Expect(file.ForTopology(report.Topology.Fingerprint())).To(Equal(&benchmark.Baseline{
+				TopologyFingerprint: report.Topology.Fingerprint(),
+				Cells: map[string]benchmark.BaselineCell{
+					throughputKey: {Unit: "bits/sec", Mean: 1000, Samples: 1},
+				},
+			}))
+		})
+
+		It("should merge the results into the existing baseline", func() {
+			report := &benchmark.Report{}
+			baseline.TopologyFingerprint = report.Topology.Fingerprint()
+			file.Baselines = append(file.Baselines, *baseline)
+
+			skipped := result(benchmark.Latency, 0)
+			skipped.Skipped = "Globalnet isn't enabled"
+			report.Results = []benchmark.CellResult{result(benchmark.Throughput, 2000), skipped}
+
+			Expect(file.Update(report)).To(Succeed())
+			Expect(file.Baselines).To(HaveLen(1))
+			Expect(file.Baselines[0].Cells).To(Equal(map[string]benchmark.BaselineCell{
+				throughputKey: {Unit: "bits/sec", Mean: 2000, Samples: 1},
+				latencyKey:    {Unit: "microseconds", Mean: 100, Samples: 3},
+			}))
+		})
+
+		It("should refuse a report with failed cells", func() {
+			report := &benchmark.Report{Results: []benchmark.CellResult{result(benchmark.Throughput, 1000), failed(benchmark.Latency)}}
+
+			Expect(file.Update(report)).To(MatchError(ContainSubstring("1 failed cell(s)")))
+			Expect(file.Baselines).To(BeEmpty())
+		})
+
+		It("should refuse a report if all the cells failed", func() {
+			report := &benchmark.Report{Results: []benchmark.CellResult{failed(benchmark.Throughput), failed(benchmark.Latency)}}
+
+			Expect(file.Update(report)).To(MatchError(ContainSubstring("2 failed cell(s)")))
+			Expect(file.Baselines).To(BeEmpty())
+		})
+	})
+})
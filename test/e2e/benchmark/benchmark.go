@@ -0,0 +1,255 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package benchmark implements a dataplane performance benchmark, measuring throughput and latency over a matrix of
+// cluster pairs, pod scheduling, pod networking and endpoint types.
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+)
+
+type Metric string
+
+const (
+	// Throughput is the iperf3 receiver bandwidth, in bits per second.
+	Throughput Metric = "throughput"
+	// Latency is the netperf TCP_RR mean latency, in microseconds.
+	Latency Metric = "latency"
+)
+
+type EndpointType string
+
+const (
+	PodIP     EndpointType = "pod-ip"
+	ServiceIP EndpointType = "service-ip"
+	// GlobalIP is the Globalnet IP allocated to the server pod, as a backend of an exported headless service.
+	GlobalIP EndpointType = "global-ip"
+)
+
+type ClusterPair struct {
+	From framework.ClusterIndex
+	To   framework.ClusterIndex
+}
+
+// Matrix describes the benchmark cells: each combination of its dimensions is measured Repetitions times.
+type Matrix struct {
+	Metrics      []Metric
+	ClusterPairs []ClusterPair
+	Schedulings  []framework.NetworkPodScheduling
+	Networkings  []framework.NetworkingType
+	Endpoints    []EndpointType
+	Repetitions  int
+}
+
+// Cell is a combination of the matrix dimensions. The scheduling applies to both the client and the server pods, the
+// networking to the client pod.
+type Cell struct {
+	Metric     Metric
+	Pair       ClusterPair
+	Scheduling framework.NetworkPodScheduling
+	Networking framework.NetworkingType
+	Endpoint   EndpointType
+}
+
+// DefaultMatrix returns a matrix covering every dimension, from the first cluster to each other cluster, or within the
+// first cluster if there's only one.
+func DefaultMatrix(repetitions int) Matrix {
+	pairs := []ClusterPair{}
+
+	for i := 1; i < len(framework.TestContext.ClusterIDs); i++ {
+		pairs = append(pairs, ClusterPair{From: framework.ClusterA, To: framework.ClusterIndex(i)})
+	}
+
+	if len(pairs) == 0 {
+		pairs = append(pairs, ClusterPair{From: framework.ClusterA, To: framework.ClusterA})
+	}
+
+	return Matrix{
+		Metrics:      []Metric{Throughput, Latency},
+		ClusterPairs: pairs,
+		Schedulings:  []framework.NetworkPodScheduling{framework.GatewayNode, framework.NonGatewayNode},
+		Networkings:  []framework.NetworkingType{framework.PodNetworking, framework.HostNetworking},
+		Endpoints:    []EndpointType{PodIP, ServiceIP, GlobalIP},
+		Repetitions:  repetitions,
+	}
+}
+
+// Cells returns the cells of the matrix, in a stable order.
+func (m *Matrix) Cells() []Cell {
+	cells := []Cell{}
+
+	for _, metric := range m.Metrics {
+		for _, pair := range m.ClusterPairs {
+			for _, scheduling := range m.Schedulings {
+				for _, networking := range m.Networkings {
+					for _, endpoint := range m.Endpoints {
+						cells = append(cells, Cell{
+							Metric:     metric,
+							Pair:       pair,
+							Scheduling: scheduling,
+							Networking: networking,
+							Endpoint:   endpoint,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return cells
+}
+
+// unsupported returns why the cell can't be measured in the current environment, or an empty string if it can.
+func (c *Cell) unsupported() string {
+	switch {
+	case c.Endpoint == GlobalIP && !framework.TestContext.GlobalnetEnabled:
+		return "Globalnet isn't enabled"
+	case c.Endpoint != GlobalIP && framework.TestContext.GlobalnetEnabled && c.Pair.From != c.Pair.To:
+		return "pod and service IPs aren't reachable from other clusters with Globalnet"
+	case c.Metric == Latency && c.Endpoint == ServiceIP:
+		return "netperf uses a separate data connection which isn't exposed by the service"
+	}
+
+	return ""
+}
+
+// Run measures each cell of the matrix and returns the report. Failed measurements are recorded in the report rather
+// than aborting the run, so a single broken cell doesn't waste the others.
+func Run(ctx context.Context, f *framework.Framework, m Matrix) *Report {
+	report := &Report{Topology: CurrentTopology(ctx), Repetitions: m.Repetitions, Results: []CellResult{}}
+
+	for _, cell := range m.Cells() {
+		result := newCellResult(&cell)
+
+		if result.Skipped = cell.unsupported(); result.Skipped != "" {
+			framework.Logf("Skipping benchmark cell %s: %s", &result, result.Skipped)
+		} else {
+			framework.By(fmt.Sprintf("Running benchmark cell %s", &result))
+			runCell(ctx, f, &cell, m.Repetitions, &result)
+			framework.Logf("Benchmark cell %s: mean %.2f %s, variance %.2f", &result, result.Mean, result.Unit, result.Variance)
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+func runCell(ctx context.Context, f *framework.Framework, cell *Cell, repetitions int, result *CellResult) {
+	serverType := framework.ThroughputServerPod
+	clientType := framework.ThroughputClientPod
+
+	if cell.Metric == Latency {
+		serverType = framework.LatencyServerPod
+		clientType = framework.LatencyClientPod
+	}
+
+	serverPod, err := f.NewNetworkPodOrError(ctx, &framework.NetworkPodConfig{
+		Type:       serverType,
+		Cluster:    cell.Pair.To,
+		Scheduling: cell.Scheduling,
+	})
+	if err != nil {
+		result.addError(err)
+		return
+	}
+
+	// The server pods of all the cells share their labels, so the service and export of the next cells would also select
+	// this one if it were left running.
+	defer func() {
+		if err := serverPod.DeleteOrError(ctx); err != nil {
+			result.addError(err)
+		}
+	}()
+
+	remoteIP, err := remoteIPFor(ctx, cell, serverPod)
+	if err != nil {
+		result.addError(err)
+		return
+	}
+
+	for i := 0; i < repetitions; i++ {
+		sample, err := measure(ctx, f, cell, clientType, remoteIP)
+		if err != nil {
+			result.addError(err)
+			continue
+		}
+
+		result.Samples = append(result.Samples, sample)
+	}
+
+	result.Mean, result.Variance = meanAndVariance(result.Samples)
+}
+
+var endpointTypes = map[EndpointType]framework.EndpointType{
+	PodIP:     framework.PodIPEndpoint,
+	ServiceIP: framework.ServiceIPEndpoint,
+	GlobalIP:  framework.GlobalPodIPEndpoint,
+}
+
+func remoteIPFor(ctx context.Context, cell *Cell, serverPod *framework.NetworkPod) (string, error) {
+	endpointType, found := endpointTypes[cell.Endpoint]
+	if !found {
+		return "", fmt.Errorf("unknown endpoint type %q", cell.Endpoint)
+	}
+
+	return serverPod.RemoteAddressOrError(ctx, endpointType)
+}
+
+func measure(ctx context.Context, f *framework.Framework, cell *Cell, clientType framework.NetworkPodType, remoteIP string,
+) (float64, error) {
+	clientPod, err := f.NewNetworkPodOrError(ctx, &framework.NetworkPodConfig{
+		Type:               clientType,
+		Cluster:            cell.Pair.From,
+		Scheduling:         cell.Scheduling,
+		Networking:         cell.Networking,
+		RemoteIP:           remoteIP,
+		ConnectionTimeout:  framework.TestContext.ConnectionTimeout,
+		ConnectionAttempts: framework.TestContext.ConnectionAttempts,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	clientPod.AwaitFinish(ctx)
+
+	if err := clientPod.CheckSuccessfulFinishOrError(); err != nil {
+		return 0, err
+	}
+
+	if cell.Metric == Latency {
+		result, err := clientPod.LatencyResultOrError()
+		if err != nil {
+			return 0, err
+		}
+
+		return float64(result.Mean) / float64(time.Microsecond), nil
+	}
+
+	result, err := clientPod.ThroughputResultOrError(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ReceiverBitsPerSecond, nil
+}
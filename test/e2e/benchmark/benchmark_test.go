@@ -0,0 +1,99 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/benchmark"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+)
+
+var _ = Describe("Matrix", func() {
+	It("should return every combination of the dimensions, in a stable order", func() {
+		m := benchmark.Matrix{
+			Metrics:      []benchmark.Metric{benchmark.Throughput, benchmark.Latency},
+			ClusterPairs: []benchmark.ClusterPair{{From: framework.ClusterA, To: framework.ClusterB}},
+			Schedulings:  []framework.NetworkPodScheduling{framework.GatewayNode},
+			Networkings:  []framework.NetworkingType{framework.PodNetworking, framework.HostNetworking},
+			Endpoints:    []benchmark.EndpointType{benchmark.PodIP},
+			Repetitions:  3,
+		}
+
+		cell := func(metric benchmark.Metric, networking framework.NetworkingType) benchmark.Cell {
+			return benchmark.Cell{
+				Metric:     metric,
+				Pair:       benchmark.ClusterPair{From: framework.ClusterA, To: framework.ClusterB},
+				Scheduling: framework.GatewayNode,
+				Networking: networking,
+				Endpoint:   benchmark.PodIP,
+			}
+		}
+
+		Expect(m.Cells()).To(Equal([]benchmark.Cell{
+			cell(benchmark.Throughput, framework.PodNetworking),
+			cell(benchmark.Throughput, framework.HostNetworking),
+			cell(benchmark.Latency, framework.PodNetworking),
+			cell(benchmark.Latency, framework.HostNetworking),
+		}))
+	})
+
+	It("should have no cells if a dimension is empty", func() {
+		m := benchmark.Matrix{
+			Metrics:      []benchmark.Metric{benchmark.Throughput},
+			ClusterPairs: []benchmark.ClusterPair{},
+			Schedulings:  []framework.NetworkPodScheduling{framework.GatewayNode},
+			Networkings:  []framework.NetworkingType{framework.PodNetworking},
+			Endpoints:    []benchmark.EndpointType{benchmark.PodIP},
+		}
+
+		Expect(m.Cells()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Cell", func() {
+	crossCluster := benchmark.ClusterPair{From: framework.ClusterA, To: framework.ClusterB}
+	sameCluster := benchmark.ClusterPair{From: framework.ClusterA, To: framework.ClusterA}
+
+	DescribeTable("unsupported",
+		func(globalnet bool, cell benchmark.Cell, expected string) {
+			framework.TestContext.GlobalnetEnabled = globalnet
+
+			if expected == "" {
+				Expect(benchmark.Unsupported(cell)).To(BeEmpty())
+			} else {
+				Expect(benchmark.Unsupported(cell)).To(ContainSubstring(expected))
+			}
+		},
+		Entry("pod IP throughput without Globalnet", false,
+			benchmark.Cell{Metric: benchmark.Throughput, Pair: crossCluster, Endpoint: benchmark.PodIP}, ""),
+		Entry("service IP throughput without Globalnet", false,
+			benchmark.Cell{Metric: benchmark.Throughput, Pair: crossCluster, Endpoint: benchmark.ServiceIP}, ""),
+		Entry("global IP without Globalnet", false,
+			benchmark.Cell{Metric: benchmark.Throughput, Pair: crossCluster, Endpoint: benchmark.GlobalIP}, "Globalnet isn't enabled"),
+		Entry("global IP with Globalnet", true,
+			benchmark.Cell{Metric: benchmark.Latency, Pair: crossCluster, Endpoint: benchmark.GlobalIP}, ""),
+		Entry("pod IP across clusters with Globalnet", true,
+			benchmark.Cell{Metric: benchmark.Throughput, Pair: crossCluster, Endpoint: benchmark.PodIP}, "aren't reachable"),
+		Entry("pod IP within a cluster with Globalnet", true,
+			benchmark.Cell{Metric: benchmark.Throughput, Pair: sameCluster, Endpoint: benchmark.PodIP}, ""),
+		Entry("service IP latency", false,
+			benchmark.Cell{Metric: benchmark.Latency, Pair: crossCluster, Endpoint: benchmark.ServiceIP}, "separate data connection"),
+	)
+})
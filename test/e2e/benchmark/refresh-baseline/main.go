@@ -0,0 +1,80 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command refresh-baseline merges a benchmark run's results into the baseline for its topology, e.g.
+//
+//	go run github.com/submariner-io/shipyard/test/e2e/benchmark/refresh-baseline \
+//	    -report artifacts/benchmark.json -baseline test/e2e/benchmark-baseline.json
+//
+// The baseline file is created if it doesn't exist. Runs with failed cells are refused.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/shipyard/test/e2e/benchmark"
+)
+
+func main() {
+	reportPath := flag.String("report", "benchmark.json", "The benchmark.json report written by the benchmark run.")
+	baselinePath := flag.String("baseline", "", "The baseline file to update.")
+	flag.Parse()
+
+	if *baselinePath == "" {
+		fmt.Fprintln(os.Stderr, "-baseline must be specified")
+		os.Exit(2)
+	}
+
+	if err := refresh(*reportPath, *baselinePath); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func refresh(reportPath, baselinePath string) error {
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		return errors.Wrap(err, "error reading the benchmark report")
+	}
+
+	report := &benchmark.Report{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return errors.Wrapf(err, "error parsing the benchmark report %q", reportPath)
+	}
+
+	baselines, err := benchmark.LoadBaselineFile(baselinePath)
+	if err != nil {
+		return err
+	}
+
+	if err := baselines.Update(report); err != nil {
+		return err
+	}
+
+	if err := baselines.Save(baselinePath); err != nil {
+		return err
+	}
+
+	fmt.Printf("Refreshed the baseline for topology %q in %q\n", report.Topology.Fingerprint(), baselinePath)
+
+	return nil
+}
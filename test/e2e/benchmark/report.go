@@ -0,0 +1,233 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+)
+
+// Report holds the results of a benchmark run, and the topology they were measured on.
+type Report struct {
+	Topology    Topology     `json:"topology"`
+	Repetitions int          `json:"repetitions"`
+	Results     []CellResult `json:"results"`
+}
+
+// CellResult holds the samples measured for a matrix cell, and their mean and sample variance. Cells which can't be
+// measured in the environment have no samples, and the reason they were skipped.
+type CellResult struct {
+	Metric      Metric       `json:"metric"`
+	FromCluster string       `json:"fromCluster"`
+	ToCluster   string       `json:"toCluster"`
+	Scheduling  string       `json:"scheduling"`
+	Networking  string       `json:"networking"`
+	Endpoint    EndpointType `json:"endpoint"`
+	Unit        string       `json:"unit"`
+	Samples     []float64    `json:"samples"`
+	Mean        float64      `json:"mean"`
+	Variance    float64      `json:"variance"`
+	Skipped     string       `json:"skipped,omitempty"`
+	Errors      []string     `json:"errors,omitempty"`
+}
+
+var csvHeader = []string{
+	"metric", "fromCluster", "toCluster", "scheduling", "networking", "endpoint", "unit", "samples", "mean", "variance", "skipped",
+	"errors",
+}
+
+func newCellResult(cell *Cell) CellResult {
+	result := CellResult{
+		Metric:      cell.Metric,
+		FromCluster: framework.TestContext.ClusterIDs[cell.Pair.From],
+		ToCluster:   framework.TestContext.ClusterIDs[cell.Pair.To],
+		Scheduling:  "non-gateway",
+		Networking:  "pod",
+		Endpoint:    cell.Endpoint,
+		Unit:        "bits/sec",
+		Samples:     []float64{},
+	}
+
+	if cell.Scheduling == framework.GatewayNode {
+		result.Scheduling = "gateway"
+	}
+
+	if cell.Networking == framework.HostNetworking {
+		result.Networking = "host"
+	}
+
+	if cell.Metric == Latency {
+		result.Unit = "microseconds"
+	}
+
+	return result
+}
+
+// Key identifies the cell the result was measured for, e.g. "throughput/east->west/gateway/pod/pod-ip".
+func (r *CellResult) Key() string {
+	return strings.Join([]string{
+		string(r.Metric), r.FromCluster + "->" + r.ToCluster, r.Scheduling, r.Networking, string(r.Endpoint),
+	}, "/")
+}
+
+// String formats the cell for logging.
+func (r *CellResult) String() string {
+	return r.Key()
+}
+
+func (r *CellResult) addError(err error) {
+	framework.Errorf("Benchmark cell %s: %v", r, err)
+	r.Errors = append(r.Errors, err.Error())
+}
+
+// failure returns why a measured cell failed, i.e. the errors it had or the lack of samples, or an empty string if it
+// didn't fail or was skipped.
+func (r *CellResult) failure() string {
+	switch {
+	case r.Skipped != "":
+		return ""
+	case len(r.Errors) > 0:
+		return strings.Join(r.Errors, "; ")
+	case len(r.Samples) == 0:
+		return "no samples were measured"
+	}
+
+	return ""
+}
+
+// Failures returns a description of each cell which should have been measured but had errors or no samples, e.g.
+// "latency/east->west/gateway/pod/pod-ip: error creating the pod".
+func (r *Report) Failures() []string {
+	failures := []string{}
+
+	for i := range r.Results {
+		if failure := r.Results[i].failure(); failure != "" {
+			failures = append(failures, r.Results[i].Key()+": "+failure)
+		}
+	}
+
+	return failures
+}
+
+// meanAndVariance returns the mean and the sample variance of the given samples.
+func meanAndVariance(samples []float64) (float64, float64) {
+	if len(samples) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+
+	for _, sample := range samples {
+		sum += sample
+	}
+
+	mean := sum / float64(len(samples))
+
+	if len(samples) == 1 {
+		return mean, 0
+	}
+
+	var squares float64
+
+	for _, sample := range samples {
+		squares += (sample - mean) * (sample - mean)
+	}
+
+	return mean, squares / float64(len(samples)-1)
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return errors.Wrap(encoder.Encode(r), "error writing the benchmark report")
+}
+
+// WriteCSV writes the report as CSV, with a header row and a row per cell. The samples and errors are separated by
+// semicolons.
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return errors.Wrap(err, "error writing the benchmark report")
+	}
+
+	for i := range r.Results {
+		result := &r.Results[i]
+
+		samples := make([]string, len(result.Samples))
+		for j, sample := range result.Samples {
+			samples[j] = formatFloat(sample)
+		}
+
+		err := writer.Write([]string{
+			string(result.Metric), result.FromCluster, result.ToCluster, result.Scheduling, result.Networking, string(result.Endpoint),
+			result.Unit, strings.Join(samples, ";"), formatFloat(result.Mean), formatFloat(result.Variance), result.Skipped,
+			strings.Join(result.Errors, ";"),
+		})
+		if err != nil {
+			return errors.Wrap(err, "error writing the benchmark report")
+		}
+	}
+
+	writer.Flush()
+
+	return errors.Wrap(writer.Error(), "error writing the benchmark report")
+}
+
+// WriteFiles writes the report to "benchmark.csv" and "benchmark.json" in the given directory, creating it if needed.
+func (r *Report) WriteFiles(dir string) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return errors.Wrapf(err, "error creating directory %q", dir)
+	}
+
+	writers := map[string]func(io.Writer) error{
+		"benchmark.csv":  r.WriteCSV,
+		"benchmark.json": r.WriteJSON,
+	}
+
+	for name, write := range writers {
+		data := &bytes.Buffer{}
+
+		if err := write(data); err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data.Bytes(), 0o600); err != nil {
+			return errors.Wrapf(err, "error writing %q", path)
+		}
+	}
+
+	return nil
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
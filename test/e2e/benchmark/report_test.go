@@ -0,0 +1,104 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/benchmark"
+)
+
+var _ = DescribeTable("meanAndVariance",
+	func(samples []float64, expectedMean, expectedVariance float64) {
+		mean, variance := benchmark.MeanAndVariance(samples)
+		Expect(mean).To(BeNumerically("~", expectedMean, 1e-9))
+		Expect(variance).To(BeNumerically("~", expectedVariance, 1e-9))
+	},
+	Entry("no samples", []float64{}, 0.0, 0.0),
+	Entry("a single sample", []float64{42}, 42.0, 0.0),
+	Entry("identical samples", []float64{5, 5, 5}, 5.0, 0.0),
+	Entry("several samples", []float64{2, 4, 4, 4, 5, 5, 7, 9}, 5.0, 32.0/7),
+)
+
+var _ = Describe("Report", func() {
+	measured := func(endpoint benchmark.EndpointType, samples ...float64) benchmark.CellResult {
+		return benchmark.CellResult{
+			Metric:      benchmark.Throughput,
+			FromCluster: "east",
+			ToCluster:   "west",
+			Scheduling:  "gateway",
+			Networking:  "pod",
+			Endpoint:    endpoint,
+			Samples:     samples,
+		}
+	}
+
+	failed := func(endpoint benchmark.EndpointType, errs ...string) benchmark.CellResult {
+		result := measured(endpoint)
+		result.Errors = errs
+
+		return result
+	}
+
+	skipped := func(endpoint benchmark.EndpointType) benchmark.CellResult {
+		result := measured(endpoint)
+		result.Skipped = "Globalnet isn't enabled"
+
+		return result
+	}
+
+	Describe("Failures", func() {
+		It("should return nothing if every supported cell was measured", func() {
+			report := &benchmark.Report{Results: []benchmark.CellResult{
+				measured(benchmark.PodIP, 1, 2), measured(benchmark.ServiceIP, 3), skipped(benchmark.GlobalIP),
+			}}
+
+			Expect(report.Failures()).To(BeEmpty())
+		})
+
+		It("should return the cells with errors, even with some samples", func() {
+			partial := measured(benchmark.ServiceIP, 3)
+			partial.Errors = []string{"timed out"}
+
+			report := &benchmark.Report{Results: []benchmark.CellResult{
+				measured(benchmark.PodIP, 1, 2), partial, skipped(benchmark.GlobalIP),
+			}}
+
+			Expect(report.Failures()).To(Equal([]string{"throughput/east->west/gateway/pod/service-ip: timed out"}))
+		})
+
+		It("should return the cells without samples", func() {
+			report := &benchmark.Report{Results: []benchmark.CellResult{measured(benchmark.PodIP)}}
+
+			Expect(report.Failures()).To(Equal([]string{"throughput/east->west/gateway/pod/pod-ip: no samples were measured"}))
+		})
+
+		It("should return every cell if all the cells failed", func() {
+			report := &benchmark.Report{Results: []benchmark.CellResult{
+				failed(benchmark.PodIP, "error creating the pod", "timed out"), failed(benchmark.ServiceIP, "connection refused"),
+				skipped(benchmark.GlobalIP),
+			}}
+
+			Expect(report.Failures()).To(Equal([]string{
+				"throughput/east->west/gateway/pod/pod-ip: error creating the pod; timed out",
+				"throughput/east->west/gateway/pod/service-ip: connection refused",
+			}))
+		})
+	})
+})
@@ -0,0 +1,108 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dataplane
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/benchmark"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+)
+
+var _ = Describe("[benchmark] Dataplane performance benchmark", Label("benchmark"), Serial, func() {
+	f := framework.NewFramework("dataplane-benchmark")
+
+	It("should measure the throughput and latency of every matrix cell", func(ctx SpecContext) {
+		if framework.TestContext.BenchmarkRepetitions == 0 {
+			framework.Skipf("The benchmark is only run when --benchmark-repetitions is set")
+		}
+
+		report := benchmark.Run(ctx, f, benchmark.DefaultMatrix(int(framework.TestContext.BenchmarkRepetitions)))
+
+		csv := &bytes.Buffer{}
+		Expect(report.WriteCSV(csv)).To(Succeed())
+		AddReportEntry("Benchmark", csv.String())
+
+		if framework.TestContext.ArtifactsDir != "" {
+			Expect(report.WriteFiles(framework.TestContext.ArtifactsDir)).To(Succeed())
+		}
+
+		// The cells which were measured are still compared with the baseline, and reported, before a failed cell
+		// invalidates the run.
+		if framework.TestContext.BenchmarkBaseline != "" {
+			checkBaseline(report)
+		}
+
+		failures := report.Failures()
+		Expect(failures).To(BeEmpty(), "%d benchmark cell(s) failed", len(failures))
+	})
+})
+
+// checkBaseline refreshes the baseline for the report's topology if requested, otherwise reports the regressions from it,
+// failing if configured to.
+func checkBaseline(report *benchmark.Report) {
+	path := framework.TestContext.BenchmarkBaseline
+
+	baselines, err := benchmark.LoadBaselineFile(path)
+	Expect(err).NotTo(HaveOccurred())
+
+	fingerprint := report.Topology.Fingerprint()
+
+	if framework.TestContext.BenchmarkRefreshBaseline {
+		framework.By(fmt.Sprintf("Refreshing the baseline for topology %q in %q", fingerprint, path))
+		Expect(baselines.Update(report)).To(Succeed())
+		Expect(baselines.Save(path)).To(Succeed())
+
+		return
+	}
+
+	baseline := baselines.ForTopology(fingerprint)
+	if baseline == nil {
+		framework.Logf("WARNING: %q has no baseline for topology %q, the results aren't compared", path, fingerprint)
+		return
+	}
+
+	regressions := baseline.Compare(report, benchmark.Tolerances{
+		Throughput: framework.TestContext.BenchmarkThroughputTolerance,
+		Latency:    framework.TestContext.BenchmarkLatencyTolerance,
+	})
+	if len(regressions) == 0 {
+		framework.Logf("No regressions from the baseline for topology %q", fingerprint)
+		return
+	}
+
+	descriptions := make([]string, len(regressions))
+	for i := range regressions {
+		descriptions[i] = regressions[i].String()
+	}
+
+	message := fmt.Sprintf("%d benchmark cell(s) regressed from the baseline for topology %q:\n%s", len(regressions), fingerprint,
+		strings.Join(descriptions, "\n"))
+	AddReportEntry("Benchmark regressions", message)
+
+	if framework.TestContext.BenchmarkFailOnRegression {
+		framework.Failf("%s", message)
+	}
+
+	framework.Logf("WARNING: %s", message)
+}
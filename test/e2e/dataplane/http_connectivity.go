@@ -0,0 +1,100 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dataplane
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/tcp"
+)
+
+var _ = Describe("[dataplane] Basic HTTP connectivity test", func() {
+	f := framework.NewFramework("dataplane-http")
+
+	When("a pod sends an HTTP request to another pod in the same cluster", func() {
+		It("should get a successful response with the expected body", func(ctx SpecContext) {
+			tcp.RunHTTPConnectivityTest(ctx, tcp.ConnectivityTestParams{
+				Framework:             f,
+				ToEndpointType:        tcp.PodIP,
+				Networking:            framework.PodNetworking,
+				FromCluster:           framework.ClusterA,
+				FromClusterScheduling: framework.NonGatewayNode,
+				ToCluster:             framework.ClusterA,
+				ToClusterScheduling:   framework.NonGatewayNode,
+			})
+		})
+	})
+
+	When("a pod sends an HTTP request to a service in the same cluster", func() {
+		It("should get a successful response with the expected body", func(ctx SpecContext) {
+			tcp.RunHTTPConnectivityTest(ctx, tcp.ConnectivityTestParams{
+				Framework:             f,
+				ToEndpointType:        tcp.ServiceIP,
+				Networking:            framework.PodNetworking,
+				FromCluster:           framework.ClusterA,
+				FromClusterScheduling: framework.NonGatewayNode,
+				ToCluster:             framework.ClusterA,
+				ToClusterScheduling:   framework.NonGatewayNode,
+			})
+		})
+	})
+
+	// The clusterset name is resolved by Lighthouse, the "service-discovery" label allows filtering the spec out without it.
+	When("a pod sends an HTTP request to the clusterset name of a service in a remote cluster", Label("service-discovery"), framework.Requires(
+		framework.RequireClusters(2)), func() {
+		It("should get a successful response with the expected body", func(ctx SpecContext) {
+			tcp.RunHTTPConnectivityTest(ctx, tcp.ConnectivityTestParams{
+				Framework:             f,
+				ToEndpointType:        tcp.ClustersetServiceName,
+				Networking:            framework.PodNetworking,
+				FromCluster:           framework.ClusterA,
+				FromClusterScheduling: framework.NonGatewayNode,
+				ToCluster:             framework.ClusterB,
+				ToClusterScheduling:   framework.NonGatewayNode,
+			})
+		})
+	})
+
+	When("a pod sends an HTTP request to the global IPs of a pod in a remote cluster", framework.Requires(
+		framework.RequireClusters(2), framework.RequireGlobalnet()), func() {
+		It("should get a successful response via the exported service's global IP", func(ctx SpecContext) {
+			tcp.RunHTTPConnectivityTest(ctx, tcp.ConnectivityTestParams{
+				Framework:             f,
+				ToEndpointType:        tcp.GlobalServiceIP,
+				Networking:            framework.PodNetworking,
+				FromCluster:           framework.ClusterA,
+				FromClusterScheduling: framework.NonGatewayNode,
+				ToCluster:             framework.ClusterB,
+				ToClusterScheduling:   framework.NonGatewayNode,
+			})
+		})
+
+		It("should get a successful response via the pod's global IP", func(ctx SpecContext) {
+			tcp.RunHTTPConnectivityTest(ctx, tcp.ConnectivityTestParams{
+				Framework:             f,
+				ToEndpointType:        tcp.GlobalPodIP,
+				Networking:            framework.PodNetworking,
+				FromCluster:           framework.ClusterA,
+				FromClusterScheduling: framework.NonGatewayNode,
+				ToCluster:             framework.ClusterB,
+				ToClusterScheduling:   framework.NonGatewayNode,
+			})
+		})
+	})
+})
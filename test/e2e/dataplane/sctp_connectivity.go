@@ -0,0 +1,93 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dataplane
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/sctp"
+)
+
+var _ = Describe("[dataplane] Basic SCTP connectivity test", func() {
+	f := framework.NewFramework("dataplane-sctp")
+
+	When("a pod sends data to another pod via SCTP in the same cluster", func() {
+		It("should exchange the expected data with the other pod", func(ctx SpecContext) {
+			sctp.RunConnectivityTest(ctx, sctp.ConnectivityTestParams{
+				Framework:             f,
+				ToEndpointType:        sctp.PodIP,
+				Networking:            framework.PodNetworking,
+				FromCluster:           framework.ClusterA,
+				FromClusterScheduling: framework.NonGatewayNode,
+				ToCluster:             framework.ClusterA,
+				ToClusterScheduling:   framework.NonGatewayNode,
+			})
+		})
+	})
+
+	When("a pod sends data to a pod in a remote cluster via SCTP", framework.Requires(framework.RequireClusters(2)), func() {
+		remoteEndpointType := func() sctp.EndpointType {
+			// Without Globalnet, the pod IPs are routable across the clusters.
+			if framework.TestContext.GlobalnetEnabled {
+				return sctp.GlobalPodIP
+			}
+
+			return sctp.PodIP
+		}
+
+		It("should exchange the expected data between pods on the gateway nodes", func(ctx SpecContext) {
+			sctp.RunConnectivityTest(ctx, sctp.ConnectivityTestParams{
+				Framework:             f,
+				ToEndpointType:        remoteEndpointType(),
+				Networking:            framework.PodNetworking,
+				FromCluster:           framework.ClusterA,
+				FromClusterScheduling: framework.GatewayNode,
+				ToCluster:             framework.ClusterB,
+				ToClusterScheduling:   framework.GatewayNode,
+			})
+		})
+
+		It("should exchange the expected data between pods on non-gateway nodes, through the gateways", func(ctx SpecContext) {
+			sctp.RunConnectivityTest(ctx, sctp.ConnectivityTestParams{
+				Framework:             f,
+				ToEndpointType:        remoteEndpointType(),
+				Networking:            framework.PodNetworking,
+				FromCluster:           framework.ClusterA,
+				FromClusterScheduling: framework.NonGatewayNode,
+				ToCluster:             framework.ClusterB,
+				ToClusterScheduling:   framework.NonGatewayNode,
+			})
+		})
+	})
+
+	When("a pod sends data via SCTP to the global IP of a service in a remote cluster", framework.Requires(
+		framework.RequireClusters(2), framework.RequireGlobalnet()), func() {
+		It("should exchange the expected data with the pod backing the service", func(ctx SpecContext) {
+			sctp.RunConnectivityTest(ctx, sctp.ConnectivityTestParams{
+				Framework:             f,
+				ToEndpointType:        sctp.GlobalServiceIP,
+				Networking:            framework.PodNetworking,
+				FromCluster:           framework.ClusterA,
+				FromClusterScheduling: framework.NonGatewayNode,
+				ToCluster:             framework.ClusterB,
+				ToClusterScheduling:   framework.NonGatewayNode,
+			})
+		})
+	})
+})
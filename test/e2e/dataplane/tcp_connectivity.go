@@ -23,14 +23,15 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/submariner-io/shipyard/test/e2e/framework"
 	"github.com/submariner-io/shipyard/test/e2e/tcp"
+	corev1 "k8s.io/api/core/v1"
 )
 
 var _ = Describe("[dataplane] Basic TCP connectivity test", func() {
 	f := framework.NewFramework("dataplane")
 
 	When("a pod connects to another pod via TCP in the same cluster", func() {
-		It("should send the expected data to the other pod", func() {
-			tcp.RunConnectivityTest(tcp.ConnectivityTestParams{
+		It("should send the expected data to the other pod", func(ctx SpecContext) {
+			tcp.RunConnectivityTest(ctx, tcp.ConnectivityTestParams{
 				Framework:             f,
 				ToEndpointType:        tcp.PodIP,
 				Networking:            framework.HostNetworking,
@@ -41,4 +42,20 @@ var _ = Describe("[dataplane] Basic TCP connectivity test", func() {
 			})
 		})
 	})
+
+	When("a pod connects to another pod via TCP over IPv6 in the same cluster", framework.Requires(
+		framework.RequireIPFamily(corev1.IPv6Protocol)), func() {
+		It("should send the expected data to the other pod", func(ctx SpecContext) {
+			tcp.RunConnectivityTest(ctx, tcp.ConnectivityTestParams{
+				Framework:             f,
+				ToEndpointType:        tcp.PodIP,
+				Networking:            framework.PodNetworking,
+				FromCluster:           framework.ClusterA,
+				FromClusterScheduling: framework.NonGatewayNode,
+				ToCluster:             framework.ClusterA,
+				ToClusterScheduling:   framework.NonGatewayNode,
+				IPFamily:              corev1.IPv6Protocol,
+			})
+		})
+	})
 })
@@ -0,0 +1,84 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dataplane
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/udp"
+)
+
+var _ = Describe("[dataplane] Basic UDP connectivity test", func() {
+	f := framework.NewFramework("dataplane-udp")
+
+	When("a pod sends data to another pod via UDP in the same cluster", func() {
+		It("should exchange the expected data with the other pod", func(ctx SpecContext) {
+			udp.RunConnectivityTest(ctx, udp.ConnectivityTestParams{
+				Framework:             f,
+				ToEndpointType:        udp.PodIP,
+				Networking:            framework.PodNetworking,
+				FromCluster:           framework.ClusterA,
+				FromClusterScheduling: framework.NonGatewayNode,
+				ToCluster:             framework.ClusterA,
+				ToClusterScheduling:   framework.NonGatewayNode,
+			})
+		})
+	})
+
+	When("a pod sends data to a service via UDP in the same cluster", func() {
+		It("should exchange the expected data with the pod backing the service", func(ctx SpecContext) {
+			udp.RunConnectivityTest(ctx, udp.ConnectivityTestParams{
+				Framework:             f,
+				ToEndpointType:        udp.ServiceIP,
+				Networking:            framework.PodNetworking,
+				FromCluster:           framework.ClusterA,
+				FromClusterScheduling: framework.NonGatewayNode,
+				ToCluster:             framework.ClusterA,
+				ToClusterScheduling:   framework.NonGatewayNode,
+			})
+		})
+	})
+
+	When("a pod sends data via UDP to the global IPs of a pod in a remote cluster", framework.Requires(
+		framework.RequireClusters(2), framework.RequireGlobalnet()), func() {
+		It("should exchange the expected data with the pod via its exported service's global IP", func(ctx SpecContext) {
+			udp.RunConnectivityTest(ctx, udp.ConnectivityTestParams{
+				Framework:             f,
+				ToEndpointType:        udp.GlobalServiceIP,
+				Networking:            framework.PodNetworking,
+				FromCluster:           framework.ClusterA,
+				FromClusterScheduling: framework.NonGatewayNode,
+				ToCluster:             framework.ClusterB,
+				ToClusterScheduling:   framework.NonGatewayNode,
+			})
+		})
+
+		It("should exchange the expected data with the pod via its global IP", func(ctx SpecContext) {
+			udp.RunConnectivityTest(ctx, udp.ConnectivityTestParams{
+				Framework:             f,
+				ToEndpointType:        udp.GlobalPodIP,
+				Networking:            framework.PodNetworking,
+				FromCluster:           framework.ClusterA,
+				FromClusterScheduling: framework.NonGatewayNode,
+				ToCluster:             framework.ClusterB,
+				ToClusterScheduling:   framework.NonGatewayNode,
+			})
+		})
+	})
+})
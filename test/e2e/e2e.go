@@ -37,28 +37,41 @@ import (
 // This function takes two parameters: one function which runs on only the first Ginkgo node,
 // returning an opaque byte array, and then a second function which runs on all Ginkgo nodes,
 // accepting the byte array.
-var _ = SynchronizedBeforeSuite(func() []byte {
+var _ = SynchronizedBeforeSuite(func(ctx SpecContext) []byte {
 	// Run only on Ginkgo node 1
 
-	framework.BeforeSuite()
-	return nil
-}, func(_ []byte) {
+	framework.BeforeSuite(ctx)
+	framework.FingerprintEnvironment(ctx)
+
+	return framework.SaveSuiteState()
+}, func(data []byte) {
 	// Run on all Ginkgo nodes
+
+	framework.RestoreSuiteState(data)
 })
 
 // Similar to SynchornizedBeforeSuite, we want to run some operations only once (such as collecting cluster logs).
 // Here, the order of functions is reversed; first, the function which runs everywhere,
 // and then the function that only runs on the first Ginkgo node.
 
-var _ = SynchronizedAfterSuite(func() {
+var _ = SynchronizedAfterSuite(func(ctx SpecContext) {
 	// Run on all Ginkgo nodes
 
 	// framework.Logf("Running AfterSuite actions on all node")
-	framework.RunCleanupActions()
+	framework.RunCleanupActions(ctx)
 }, func() {
 	// Run only Ginkgo on node 1
 })
 
+// The JUnit report is generated here, rather than by Ginkgo, to include the environment fingerprint. This only runs on
+// the first Ginkgo node, with the report aggregated from all nodes.
+var _ = ReportAfterSuite("Environment report", func(report Report) {
+	framework.ReportEnvironment(report, junitReport)
+})
+
+// junitReport is the JUnit report path requested in the Ginkgo reporter configuration.
+var junitReport string
+
 func init() {
 	klog.InitFlags(nil)
 }
@@ -68,6 +81,14 @@ func RunE2ETests(t *testing.T) bool {
 
 	framework.SetFailFunction(Fail)
 
+	framework.SetSpecFailedFunction(func() bool {
+		return CurrentSpecReport().Failed()
+	})
+
+	framework.SetSpecNameFunction(func() string {
+		return CurrentSpecReport().FullText()
+	})
+
 	framework.SetUserAgentFunction(func() string {
 		return fmt.Sprintf("%v -- %v", rest.DefaultKubernetesUserAgent(), CurrentSpecReport().FullText())
 	})
@@ -85,5 +106,8 @@ func RunE2ETests(t *testing.T) bool {
 		reporterConfig = *framework.TestContext.ReporterConfig
 	}
 
+	junitReport = reporterConfig.JUnitReport
+	reporterConfig.JUnitReport = ""
+
 	return RunSpecs(t, "Submariner E2E suite", suiteConfig, reporterConfig)
 }
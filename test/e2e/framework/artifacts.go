@@ -0,0 +1,206 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8serrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const maxArtifactsDirNameLen = 100
+
+var (
+	endpointGVR = &schema.GroupVersionResource{
+		Group:    "submariner.io",
+		Version:  "v1",
+		Resource: "endpoints",
+	}
+
+	unsafeFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+)
+
+// SpecArtifactsDir returns the directory in which the artifacts of the current spec are collected, under
+// TestContext.ArtifactsDir.
+func (f *Framework) SpecArtifactsDir() string {
+	name := unsafeFileNameChars.ReplaceAllString(specNameFunction(), "_")
+	if len(name) > maxArtifactsDirNameLen {
+		name = name[:maxArtifactsDirNameLen]
+	}
+
+	if f.Namespace != "" {
+		name += "-" + f.Namespace
+	}
+
+	return filepath.Join(TestContext.ArtifactsDir, name)
+}
+
+// CollectArtifacts writes, for every cluster, a bundle of diagnostics to a subdirectory of the given directory named
+// after the cluster ID:
+//   - the pods in the test namespaces, with their logs, and the namespaces' events,
+//   - the GlobalIngressIPs and GlobalEgressIPs in the test namespaces, and the ClusterGlobalEgressIPs,
+//   - the Submariner pods, with their logs, and the Gateway, Endpoint and Cluster resources.
+//
+// Collection continues past errors, which are returned together.
+func (f *Framework) CollectArtifacts(ctx context.Context, dir string) error {
+	var errs []error
+
+	collect := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	namespaces := []string{}
+	for ns := range f.namespacesToDelete {
+		namespaces = append(namespaces, ns)
+	}
+
+	if len(namespaces) == 0 && f.Namespace != "" {
+		namespaces = append(namespaces, f.Namespace)
+	}
+
+	for i := range KubeClients {
+		cluster := ClusterIndex(i)
+		clusterDir := filepath.Join(dir, TestContext.ClusterIDs[i])
+
+		for _, ns := range namespaces {
+			nsDir := filepath.Join(clusterDir, ns)
+
+			collect(writePodsWithLogs(ctx, cluster, ns, nsDir))
+			collect(writeEvents(ctx, cluster, ns, nsDir))
+			collect(writeResources(ctx, cluster, globalIngressIPGVR, ns, nsDir))
+			collect(writeResources(ctx, cluster, globalEgressIPGVR, ns, nsDir))
+		}
+
+		submarinerDir := filepath.Join(clusterDir, TestContext.SubmarinerNamespace)
+
+		collect(writePodsWithLogs(ctx, cluster, TestContext.SubmarinerNamespace, submarinerDir))
+		collect(writeResources(ctx, cluster, gatewayGVR, TestContext.SubmarinerNamespace, submarinerDir))
+		collect(writeResources(ctx, cluster, endpointGVR, TestContext.SubmarinerNamespace, submarinerDir))
+		collect(writeResources(ctx, cluster, clusterGVR, TestContext.SubmarinerNamespace, submarinerDir))
+		collect(writeResources(ctx, cluster, clusterGlobalEgressIPGVR, "", clusterDir))
+	}
+
+	return k8serrors.NewAggregate(errs)
+}
+
+// collectArtifactsOnFailure collects the artifacts of the current spec if it failed and TestContext.ArtifactsDir is set.
+func (f *Framework) collectArtifactsOnFailure(ctx context.Context) {
+	if TestContext.ArtifactsDir == "" || !specFailedFunction() {
+		return
+	}
+
+	dir := f.SpecArtifactsDir()
+	By(fmt.Sprintf("Collecting artifacts of the failed test in %q", dir))
+
+	if err := f.CollectArtifacts(ctx, dir); err != nil {
+		Errorf("Error collecting artifacts: %v", err)
+	}
+}
+
+func writePodsWithLogs(ctx context.Context, cluster ClusterIndex, namespace, dir string) error {
+	pods, err := KubeClients[cluster].CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "error listing pods in namespace %q on cluster %q", namespace, TestContext.ClusterIDs[cluster])
+	}
+
+	if err := writeYAML(filepath.Join(dir, "pods.yaml"), pods); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+
+		for j := range pod.Spec.Containers {
+			container := pod.Spec.Containers[j].Name
+
+			logs, err := KubeClients[cluster].CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+				Container: container,
+			}).DoRaw(ctx)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "error retrieving the logs of container %q in pod %s/%s on cluster %q",
+					container, namespace, pod.Name, TestContext.ClusterIDs[cluster]))
+
+				continue
+			}
+
+			errs = append(errs, writeFile(filepath.Join(dir, "logs", fmt.Sprintf("%s_%s.log", pod.Name, container)), logs))
+		}
+	}
+
+	return k8serrors.NewAggregate(errs)
+}
+
+func writeEvents(ctx context.Context, cluster ClusterIndex, namespace, dir string) error {
+	events, err := KubeClients[cluster].CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "error listing events in namespace %q on cluster %q", namespace, TestContext.ClusterIDs[cluster])
+	}
+
+	return writeYAML(filepath.Join(dir, "events.yaml"), events)
+}
+
+func writeResources(ctx context.Context, cluster ClusterIndex, gvr *schema.GroupVersionResource, namespace, dir string) error {
+	list, err := DynClients[cluster].Resource(*gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+
+	// List will return "NotFound" if the CRD is not registered in the specific cluster (e.g. broker-only or non-Globalnet)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "error listing %s on cluster %q", gvr.Resource, TestContext.ClusterIDs[cluster])
+	}
+
+	if len(list.Items) == 0 {
+		return nil
+	}
+
+	return writeYAML(filepath.Join(dir, gvr.Resource+".yaml"), list)
+}
+
+func writeYAML(path string, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return errors.Wrapf(err, "error marshaling %q", path)
+	}
+
+	return writeFile(path, data)
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return errors.Wrapf(err, "error creating directory %q", filepath.Dir(path))
+	}
+
+	return errors.Wrapf(os.WriteFile(path, data, 0o600), "error writing %q", path)
+}
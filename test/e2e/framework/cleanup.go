@@ -18,55 +18,161 @@ limitations under the License.
 
 package framework
 
-import "sync"
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/util/errors"
+)
 
 // CleanupActionHandle is an integer pointer type for handling cleanup action.
 type CleanupActionHandle *int
 
+// CleanupScope determines when a cleanup action is run.
+type CleanupScope string
+
+const (
+	// SpecScope cleanup actions are run at the end of the spec that registered them. Under Ginkgo, this is done via
+	// DeferCleanup by the frameworks created with NewFramework; otherwise RunCleanupActions must be called with SpecScope
+	// after each spec.
+	SpecScope CleanupScope = "spec"
+
+	// SuiteScope cleanup actions are run at the end of the suite, or when it's aborted.
+	SuiteScope CleanupScope = "suite"
+)
+
+// DefaultCleanupTimeout is the time a cleanup action is given to complete if it doesn't specify a timeout.
+const DefaultCleanupTimeout = 5 * time.Minute
+
+// CleanupAction is a scoped, time-bounded, cleanup action.
+type CleanupAction struct {
+	// Name identifies the action in the logs and errors.
+	Name  string
+	Scope CleanupScope
+	// Timeout bounds the execution of the action, DefaultCleanupTimeout if zero. The context passed to Run is canceled
+	// when the timeout expires, after which the action is reported as failed even if Run doesn't return. Such an action
+	// is abandoned rather than awaited: it keeps running concurrently with the following actions, so the reverse order
+	// of teardown is only guaranteed for the actions completing within their timeout.
+	Timeout time.Duration
+	Run     func(context.Context) error
+
+	handle CleanupActionHandle
+}
+
 var (
 	cleanupActionsLock sync.Mutex
-	cleanupActions     = map[CleanupActionHandle]func(){}
+	// cleanupActions are kept in registration order, to be run in reverse order.
+	cleanupActions = []*CleanupAction{}
 )
 
 // AddCleanupAction installs a function that will be called in the event of the
 // whole test being terminated.  This allows arbitrary pieces of the overall
 // test to hook into SynchronizedAfterSuite().
-func AddCleanupAction(fn func()) CleanupActionHandle {
-	p := CleanupActionHandle(new(int))
+func AddCleanupAction(fn func(context.Context)) CleanupActionHandle {
+	return AddScopedCleanupAction(CleanupAction{
+		Name:  "cleanup",
+		Scope: SuiteScope,
+		Run: func(ctx context.Context) error {
+			fn(ctx)
+			return nil
+		},
+	})
+}
+
+// AddScopedCleanupAction installs the given cleanup action, to be run by RunCleanupActions for its scope.
+func AddScopedCleanupAction(action CleanupAction) CleanupActionHandle {
+	action.handle = CleanupActionHandle(new(int))
+
+	if action.Timeout == 0 {
+		action.Timeout = DefaultCleanupTimeout
+	}
 
 	cleanupActionsLock.Lock()
 	defer cleanupActionsLock.Unlock()
 
-	cleanupActions[p] = fn
+	cleanupActions = append(cleanupActions, &action)
 
-	return p
+	return action.handle
 }
 
 // RemoveCleanupAction removes a function that was installed by
-// AddCleanupAction.
+// AddCleanupAction or AddScopedCleanupAction.
 func RemoveCleanupAction(p CleanupActionHandle) {
 	cleanupActionsLock.Lock()
 	defer cleanupActionsLock.Unlock()
-	delete(cleanupActions, p)
+
+	cleanupActions = slices.DeleteFunc(cleanupActions, func(action *CleanupAction) bool {
+		return action.handle == p
+	})
+}
+
+// RunCleanupActions runs the cleanup actions of the given scopes, or of all scopes if none is specified, in the reverse
+// order of their installation, and removes them. Each action is run unlocked, so it may install or remove actions, and
+// the remaining actions are run even if it fails, panics or times out. An action which times out is leaked, see
+// CleanupAction.Timeout.
+func RunCleanupActions(ctx context.Context, scopes ...CleanupScope) {
+	Expect(RunCleanupActionsOrError(ctx, scopes...)).To(Succeed())
+}
+
+// RunCleanupActionsOrError is like RunCleanupActions but returns an error instead of failing via Gomega. The errors of
+// all the failed actions are returned together.
+func RunCleanupActionsOrError(ctx context.Context, scopes ...CleanupScope) error {
+	var errs []error
+
+	for {
+		action := popCleanupAction(scopes)
+		if action == nil {
+			return k8serrors.NewAggregate(errs)
+		}
+
+		if err := action.run(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
 }
 
-// RunCleanupActions runs all functions installed by AddCleanupAction.  It does
-// not remove them (see RemoveCleanupAction) but it does run unlocked, so they
-// may remove themselves.
-func RunCleanupActions() {
-	list := []func(){}
+// popCleanupAction removes and returns the last installed cleanup action of the given scopes, or nil if there are none.
+func popCleanupAction(scopes []CleanupScope) *CleanupAction {
+	cleanupActionsLock.Lock()
+	defer cleanupActionsLock.Unlock()
 
-	func() {
-		cleanupActionsLock.Lock()
-		defer cleanupActionsLock.Unlock()
+	for i := len(cleanupActions) - 1; i >= 0; i-- {
+		action := cleanupActions[i]
 
-		for _, fn := range cleanupActions {
-			list = append(list, fn)
+		if len(scopes) == 0 || slices.Contains(scopes, action.Scope) {
+			cleanupActions = slices.Delete(cleanupActions, i, i+1)
+			return action
 		}
+	}
+
+	return nil
+}
+
+func (a *CleanupAction) run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, a.Timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("%s cleanup action %q panicked: %v", a.Scope, a.Name, r)
+			}
+		}()
+
+		done <- errors.Wrapf(a.Run(ctx), "%s cleanup action %q failed", a.Scope, a.Name)
 	}()
 
-	// Run unlocked.
-	for _, fn := range list {
-		fn()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.Wrapf(ctx.Err(), "%s cleanup action %q did not complete within %v", a.Scope, a.Name, a.Timeout)
 	}
 }
@@ -0,0 +1,175 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+)
+
+var _ = Describe("Cleanup actions", func() {
+	var ran []string
+
+	BeforeEach(func() {
+		ran = []string{}
+	})
+
+	// record returns a cleanup action recording its name when it's run.
+	record := func(name string, scope framework.CleanupScope) framework.CleanupAction {
+		return framework.CleanupAction{
+			Name:  name,
+			Scope: scope,
+			Run: func(_ context.Context) error {
+				ran = append(ran, name)
+				return nil
+			},
+		}
+	}
+
+	It("should run the actions in the reverse order of their installation", func(ctx context.Context) {
+		framework.AddScopedCleanupAction(record("first", framework.SpecScope))
+		framework.AddScopedCleanupAction(record("second", framework.SpecScope))
+		framework.AddScopedCleanupAction(record("third", framework.SpecScope))
+
+		Expect(framework.RunCleanupActionsOrError(ctx, framework.SpecScope)).To(Succeed())
+		Expect(ran).To(Equal([]string{"third", "second", "first"}))
+
+		// The actions are removed once run.
+		Expect(framework.RunCleanupActionsOrError(ctx, framework.SpecScope)).To(Succeed())
+		Expect(ran).To(HaveLen(3))
+	})
+
+	It("should not run the removed actions", func(ctx context.Context) {
+		framework.AddScopedCleanupAction(record("kept", framework.SpecScope))
+		handle := framework.AddScopedCleanupAction(record("removed", framework.SpecScope))
+
+		framework.RemoveCleanupAction(handle)
+
+		Expect(framework.RunCleanupActionsOrError(ctx, framework.SpecScope)).To(Succeed())
+		Expect(ran).To(Equal([]string{"kept"}))
+	})
+
+	It("should only run the actions of the given scopes", func(ctx context.Context) {
+		framework.AddScopedCleanupAction(record("spec1", framework.SpecScope))
+		framework.AddScopedCleanupAction(record("suite1", framework.SuiteScope))
+		framework.AddScopedCleanupAction(record("spec2", framework.SpecScope))
+		framework.AddCleanupAction(func(_ context.Context) {
+			ran = append(ran, "suite2")
+		})
+
+		Expect(framework.RunCleanupActionsOrError(ctx, framework.SpecScope)).To(Succeed())
+		Expect(ran).To(Equal([]string{"spec2", "spec1"}))
+
+		Expect(framework.RunCleanupActionsOrError(ctx, framework.SuiteScope)).To(Succeed())
+		Expect(ran).To(Equal([]string{"spec2", "spec1", "suite2", "suite1"}))
+	})
+
+	It("should run the actions of all scopes if none is given", func(ctx context.Context) {
+		framework.AddScopedCleanupAction(record("suite", framework.SuiteScope))
+		framework.AddScopedCleanupAction(record("spec", framework.SpecScope))
+
+		Expect(framework.RunCleanupActionsOrError(ctx)).To(Succeed())
+		Expect(ran).To(Equal([]string{"spec", "suite"}))
+	})
+
+	It("should run the actions installed by a running action", func(ctx context.Context) {
+		framework.AddScopedCleanupAction(framework.CleanupAction{
+			Name:  "outer",
+			Scope: framework.SpecScope,
+			Run: func(_ context.Context) error {
+				ran = append(ran, "outer")
+				framework.AddScopedCleanupAction(record("inner", framework.SpecScope))
+
+				return nil
+			},
+		})
+
+		Expect(framework.RunCleanupActionsOrError(ctx, framework.SpecScope)).To(Succeed())
+		Expect(ran).To(Equal([]string{"outer", "inner"}))
+	})
+
+	It("should run all the actions and return the errors of the failed ones together", func(ctx context.Context) {
+		for _, name := range []string{"first", "second"} {
+			framework.AddScopedCleanupAction(framework.CleanupAction{
+				Name:  name,
+				Scope: framework.SpecScope,
+				Run: func(_ context.Context) error {
+					return errors.New(name + " error")
+				},
+			})
+		}
+
+		framework.AddScopedCleanupAction(record("succeeding", framework.SpecScope))
+
+		err := framework.RunCleanupActionsOrError(ctx, framework.SpecScope)
+		Expect(err).To(MatchError(ContainSubstring(`spec cleanup action "second" failed: second error`)))
+		Expect(err).To(MatchError(ContainSubstring(`spec cleanup action "first" failed: first error`)))
+		Expect(ran).To(Equal([]string{"succeeding"}))
+	})
+
+	It("should recover from a panicking action and run the others", func(ctx context.Context) {
+		framework.AddScopedCleanupAction(record("after", framework.SuiteScope))
+		framework.AddScopedCleanupAction(framework.CleanupAction{
+			Name:  "panicking",
+			Scope: framework.SuiteScope,
+			Run: func(_ context.Context) error {
+				panic("oops")
+			},
+		})
+
+		Expect(framework.RunCleanupActionsOrError(ctx, framework.SuiteScope)).To(
+			MatchError(`suite cleanup action "panicking" panicked: oops`))
+		Expect(ran).To(Equal([]string{"after"}))
+	})
+
+	It("should abandon an action which doesn't complete within its timeout", func(ctx context.Context) {
+		release := make(chan struct{})
+		DeferCleanup(func() {
+			close(release)
+		})
+
+		canceled := make(chan error, 1)
+
+		framework.AddScopedCleanupAction(record("after", framework.SpecScope))
+		framework.AddScopedCleanupAction(framework.CleanupAction{
+			Name:    "stuck",
+			Scope:   framework.SpecScope,
+			Timeout: 50 * time.Millisecond,
+			Run: func(ctx context.Context) error {
+				<-ctx.Done()
+				canceled <- ctx.Err()
+
+				// The action ignores the cancellation and keeps running.
+				<-release
+
+				return nil
+			},
+		})
+
+		Expect(framework.RunCleanupActionsOrError(ctx, framework.SpecScope)).To(
+			MatchError(`spec cleanup action "stuck" did not complete within 50ms: context deadline exceeded`))
+		Eventually(canceled).Should(Receive(MatchError(context.DeadlineExceeded)))
+		Expect(ran).To(Equal([]string{"after"}))
+	})
+})
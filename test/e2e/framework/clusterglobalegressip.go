@@ -22,6 +22,7 @@ import (
 	"context"
 	"fmt"
 
+	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,16 +37,31 @@ var clusterGlobalEgressIPGVR = &schema.GroupVersionResource{
 	Resource: "clusterglobalegressips",
 }
 
-func (f *Framework) AwaitClusterGlobalEgressIPs(cluster ClusterIndex, name string) []string {
+func (f *Framework) AwaitClusterGlobalEgressIPs(ctx context.Context, cluster ClusterIndex, name string) []string {
 	gipClient := clusterGlobalEgressIPClient(cluster)
 
-	return AwaitAllocatedEgressIPs(gipClient, name)
+	return AwaitAllocatedEgressIPs(ctx, gipClient, name)
 }
 
-func AwaitAllocatedEgressIPs(client dynamic.ResourceInterface, name string) []string {
-	obj := AwaitUntil(fmt.Sprintf("await allocated egress IPs for %s", name),
+// AwaitClusterGlobalEgressIPsOrError is like AwaitClusterGlobalEgressIPs but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitClusterGlobalEgressIPsOrError(ctx context.Context, cluster ClusterIndex, name string) ([]string, error) {
+	gipClient := clusterGlobalEgressIPClient(cluster)
+
+	return AwaitAllocatedEgressIPsOrError(ctx, gipClient, name)
+}
+
+func AwaitAllocatedEgressIPs(ctx context.Context, client dynamic.ResourceInterface, name string) []string {
+	globalIPs, err := AwaitAllocatedEgressIPsOrError(ctx, client, name)
+	Expect(err).NotTo(HaveOccurred())
+
+	return globalIPs
+}
+
+// AwaitAllocatedEgressIPsOrError is like AwaitAllocatedEgressIPs but returns an error instead of failing via Gomega.
+func AwaitAllocatedEgressIPsOrError(ctx context.Context, client dynamic.ResourceInterface, name string) ([]string, error) {
+	obj, err := awaitUntilOrError(ctx, fmt.Sprintf("await allocated egress IPs for %s", name),
 		func() (interface{}, error) {
-			resGip, err := client.Get(context.TODO(), name, metav1.GetOptions{})
+			resGip, err := client.Get(ctx, name, metav1.GetOptions{})
 			if apierrors.IsNotFound(err) {
 				return nil, nil //nolint:nilnil // We want to repeat but let the checker known that nothing was found.
 			}
@@ -64,8 +80,11 @@ func AwaitAllocatedEgressIPs(client dynamic.ResourceInterface, name string) []st
 
 			return true, "", nil
 		})
+	if err != nil {
+		return nil, err
+	}
 
-	return getGlobalIPs(obj.(*unstructured.Unstructured))
+	return getGlobalIPs(obj.(*unstructured.Unstructured)), nil
 }
 
 func clusterGlobalEgressIPClient(cluster ClusterIndex) dynamic.ResourceInterface {
@@ -0,0 +1,300 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"slices"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ClusterRole is a role fulfilled by a cluster under test. A cluster may have several roles.
+type ClusterRole string
+
+const (
+	// BrokerRole is the role of the cluster(s) hosting the broker namespace.
+	BrokerRole ClusterRole = "broker"
+
+	// DataplaneRole is the role of the clusters with at least one gateway node, i.e. that run the Submariner dataplane.
+	DataplaneRole ClusterRole = "dataplane"
+
+	// MultiGatewayRole is the role of the clusters with more than one gateway node.
+	MultiGatewayRole ClusterRole = "multi-gateway"
+
+	// GlobalnetRole is the role of the clusters with a Globalnet CIDR.
+	GlobalnetRole ClusterRole = "globalnet"
+)
+
+// ClusterInfo describes a cluster under test, as discovered during BeforeSuite.
+type ClusterInfo struct {
+	Index           ClusterIndex  `json:"index"`
+	ID              string        `json:"id"`
+	NumGatewayNodes int           `json:"numGatewayNodes"`
+	Roles           []ClusterRole `json:"roles"`
+	// Provider is the provider of the cluster nodes, e.g. "kind" or "aws", as reported by DetectProvider.
+	Provider    string            `json:"provider"`
+	FIPSEnabled bool              `json:"fipsEnabled"`
+	IPFamilies  []corev1.IPFamily `json:"ipFamilies"`
+}
+
+// HasRoles returns true if the cluster has all the given roles.
+func (c *ClusterInfo) HasRoles(roles ...ClusterRole) bool {
+	for _, role := range roles {
+		if !slices.Contains(c.Roles, role) {
+			return false
+		}
+	}
+
+	return true
+}
+
+var (
+	clusterGVR = &schema.GroupVersionResource{
+		Group:    "submariner.io",
+		Version:  "v1",
+		Resource: "clusters",
+	}
+
+	clusterInfos []ClusterInfo
+)
+
+// Clusters returns the information about all the clusters under test, in ClusterIndex order.
+func (f *Framework) Clusters() []ClusterInfo {
+	if len(clusterInfos) != len(TestContext.ClusterIDs) {
+		// The clusters haven't been discovered (yet) so only their IDs are known.
+		infos := make([]ClusterInfo, len(TestContext.ClusterIDs))
+		for i, id := range TestContext.ClusterIDs {
+			infos[i] = ClusterInfo{Index: ClusterIndex(i), ID: id}
+		}
+
+		return infos
+	}
+
+	return clusterInfos
+}
+
+// ClusterInfo returns the information about the given cluster.
+func (f *Framework) ClusterInfo(cluster ClusterIndex) ClusterInfo {
+	return f.Clusters()[cluster]
+}
+
+// ClusterByID returns the index of the cluster with the given ID and whether it was found.
+func (f *Framework) ClusterByID(id string) (ClusterIndex, bool) {
+	for _, info := range f.Clusters() {
+		if info.ID == id {
+			return info.Index, true
+		}
+	}
+
+	return -1, false
+}
+
+// ClustersWithRoles returns the indexes of the clusters that have all the given roles, in ClusterIndex order.
+func (f *Framework) ClustersWithRoles(roles ...ClusterRole) []ClusterIndex {
+	clusters := []ClusterIndex{}
+	infos := f.Clusters()
+
+	for i := range infos {
+		info := &infos[i]
+		if info.HasRoles(roles...) {
+			clusters = append(clusters, info.Index)
+		}
+	}
+
+	return clusters
+}
+
+// ClusterHasRoles returns true if the given cluster has all the given roles.
+func (f *Framework) ClusterHasRoles(cluster ClusterIndex, roles ...ClusterRole) bool {
+	info := f.ClusterInfo(cluster)
+	return info.HasRoles(roles...)
+}
+
+// ForEachCluster invokes the given function with each cluster that has all the given roles, or every cluster if no
+// role is given.
+func (f *Framework) ForEachCluster(fn func(cluster ClusterIndex), roles ...ClusterRole) {
+	for _, cluster := range f.ClustersWithRoles(roles...) {
+		fn(cluster)
+	}
+}
+
+// ForEachClusterPair invokes the given function with each ordered pair of distinct clusters that have all the given
+// roles, or every cluster if no role is given. Both (A, B) and (B, A) are visited so connectivity can be verified in
+// both directions.
+func (f *Framework) ForEachClusterPair(fn func(from, to ClusterIndex), roles ...ClusterRole) {
+	clusters := f.ClustersWithRoles(roles...)
+
+	for _, from := range clusters {
+		for _, to := range clusters {
+			if from != to {
+				fn(from, to)
+			}
+		}
+	}
+}
+
+func initClusterInfos(ctx context.Context) error {
+	clusterInfos = make([]ClusterInfo, len(TestContext.ClusterIDs))
+
+	for i, id := range TestContext.ClusterIDs {
+		info := &clusterInfos[i]
+		info.Index = ClusterIndex(i)
+		info.ID = id
+		info.Roles = []ClusterRole{}
+
+		gatewayNodes, err := listGatewayNodes(ctx, info.Index)
+		if err != nil {
+			return err
+		}
+
+		info.NumGatewayNodes = len(gatewayNodes)
+
+		if info.NumGatewayNodes > 0 {
+			info.Roles = append(info.Roles, DataplaneRole)
+		}
+
+		if info.NumGatewayNodes > 1 {
+			info.Roles = append(info.Roles, MultiGatewayRole)
+		}
+
+		isBroker, err := hasBrokerNamespace(ctx, info.Index)
+		if isInaccessible(err) {
+			Logf("Unable to detect whether cluster %q hosts the broker, it's assumed not to: %v", id, err)
+		} else if err != nil {
+			return err
+		}
+
+		if isBroker {
+			info.Roles = append(info.Roles, BrokerRole)
+		}
+
+		isGlobalnet, err := hasGlobalCIDR(ctx, info.Index)
+		if isInaccessible(err) {
+			Logf("Unable to detect whether cluster %q has a global CIDR, it's assumed not to: %v", id, err)
+		} else if err != nil {
+			return err
+		}
+
+		if isGlobalnet {
+			info.Roles = append(info.Roles, GlobalnetRole)
+		}
+
+		info.Provider, err = detectClusterProvider(ctx, info.Index)
+		if isInaccessible(err) {
+			Logf("Unable to detect the provider of cluster %q, it's left unknown: %v", id, err)
+		} else if err != nil {
+			return err
+		}
+
+		info.FIPSEnabled, err = DetectFIPSConfig(ctx, info.Index)
+		if isInaccessible(err) {
+			Logf("Unable to detect the FIPS configuration of cluster %q, it's assumed to be disabled: %v", id, err)
+		} else if err != nil {
+			return errors.Wrapf(err, "error detecting the FIPS configuration of cluster %q", id)
+		}
+
+		info.IPFamilies, err = detectIPFamilies(ctx, info.Index)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isInaccessible returns whether the error means that optional cluster information can't be accessed, e.g. due to RBAC
+// restrictions, which shouldn't prevent the specs that don't need it from running.
+func isInaccessible(err error) bool {
+	return apierrors.IsForbidden(err) || apierrors.IsNotFound(err)
+}
+
+func hasBrokerNamespace(ctx context.Context, cluster ClusterIndex) (bool, error) {
+	_, err := KubeClients[cluster].CoreV1().Namespaces().Get(ctx, TestContext.BrokerNamespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	return err == nil, errors.Wrapf(err, "error retrieving the broker namespace on cluster %q", TestContext.ClusterIDs[cluster])
+}
+
+func hasGlobalCIDR(ctx context.Context, cluster ClusterIndex) (bool, error) {
+	// The Cluster resource isn't present on broker-only clusters, in which case NotFound is returned.
+	obj, err := DynClients[cluster].Resource(*clusterGVR).Namespace(TestContext.SubmarinerNamespace).Get(ctx,
+		TestContext.ClusterIDs[cluster], metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, errors.Wrapf(err, "error retrieving the Cluster resource on cluster %q", TestContext.ClusterIDs[cluster])
+	}
+
+	cidrs, _, err := unstructured.NestedStringSlice(obj.Object, "spec", "global_cidr")
+
+	return len(cidrs) > 0, errors.Wrapf(err, "error reading the global CIDR of cluster %q", TestContext.ClusterIDs[cluster])
+}
+
+// detectClusterProvider detects the provider of the cluster from a gateway node or, failing that, any node. It returns an
+// empty string if the cluster has no nodes.
+func detectClusterProvider(ctx context.Context, cluster ClusterIndex) (string, error) {
+	nodes, err := listGatewayNodes(ctx, cluster)
+	if err != nil {
+		return "", err
+	}
+
+	if len(nodes) == 0 {
+		nodeList, err := KubeClients[cluster].CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: 1})
+		if err != nil {
+			return "", errors.Wrapf(err, "error listing nodes on cluster %q", TestContext.ClusterIDs[cluster])
+		}
+
+		nodes = nodeList.Items
+	}
+
+	if len(nodes) == 0 {
+		return "", nil
+	}
+
+	return DetectProviderOrError(ctx, cluster, nodes[0].Name)
+}
+
+// detectIPFamilies returns the IP families of the cluster's services, as configured on the "kubernetes" service, the first
+// being the primary family. Clusters where it can't be found are assumed to be single-stack IPv4.
+func detectIPFamilies(ctx context.Context, cluster ClusterIndex) ([]corev1.IPFamily, error) {
+	service, err := KubeClients[cluster].CoreV1().Services(metav1.NamespaceDefault).Get(ctx, "kubernetes", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []corev1.IPFamily{corev1.IPv4Protocol}, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving the kubernetes service on cluster %q", TestContext.ClusterIDs[cluster])
+	}
+
+	if len(service.Spec.IPFamilies) == 0 {
+		return []corev1.IPFamily{corev1.IPv4Protocol}, nil
+	}
+
+	return service.Spec.IPFamilies, nil
+}
@@ -0,0 +1,201 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/framework/fake"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/testing"
+)
+
+var _ = Describe("Cluster info detection", func() {
+	var (
+		env         *fake.Environment
+		reactionErr error
+	)
+
+	BeforeEach(func() {
+		env = fake.NewEnvironment(fake.ClusterConfig{ID: "east", GatewayNodes: []string{"east-gw"}, Provider: "kind"})
+		DeferCleanup(env.Install())
+
+		reactionErr = nil
+	})
+
+	JustBeforeEach(func() {
+		env.Cluster(framework.ClusterA).KubeClient.PrependReactor("get", "configmaps",
+			func(_ testing.Action) (bool, runtime.Object, error) {
+				return reactionErr != nil, nil, reactionErr
+			})
+	})
+
+	When("the FIPS configuration isn't accessible", func() {
+		BeforeEach(func() {
+			reactionErr = apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "cluster-config-v1", nil)
+		})
+
+		It("should assume FIPS is disabled and not fail the suite", func(ctx context.Context) {
+			Expect(framework.BeforeSuiteOrError(ctx)).To(Succeed())
+
+			f := framework.NewBareFramework("test")
+			Expect(f.ClusterInfo(framework.ClusterA).FIPSEnabled).To(BeFalse())
+			Expect(f.ClusterInfo(framework.ClusterA).Provider).To(Equal("kind"))
+		})
+	})
+
+	When("retrieving the FIPS configuration fails otherwise", func() {
+		BeforeEach(func() {
+			reactionErr = apierrors.NewInternalError(context.DeadlineExceeded)
+		})
+
+		It("should fail the suite", func(ctx context.Context) {
+			Expect(framework.BeforeSuiteOrError(ctx)).To(MatchError(ContainSubstring("FIPS configuration")))
+		})
+	})
+
+	When("the nodes aren't accessible to detect the provider", func() {
+		JustBeforeEach(func() {
+			env.Cluster(framework.ClusterA).KubeClient.PrependReactor("get", "nodes", func(_ testing.Action) (bool, runtime.Object, error) {
+				return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "east-gw", nil)
+			})
+		})
+
+		It("should leave the provider unknown and not fail the suite", func(ctx context.Context) {
+			Expect(framework.BeforeSuiteOrError(ctx)).To(Succeed())
+			Expect(framework.NewBareFramework("test").ClusterInfo(framework.ClusterA).Provider).To(BeEmpty())
+		})
+	})
+
+	When("the broker namespace isn't accessible", func() {
+		JustBeforeEach(func() {
+			env.Cluster(framework.ClusterA).KubeClient.PrependReactor("get", "namespaces",
+				func(_ testing.Action) (bool, runtime.Object, error) {
+					return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "submariner-k8s-broker", nil)
+				})
+		})
+
+		It("should assume the cluster isn't a broker and not fail the suite", func(ctx context.Context) {
+			Expect(framework.BeforeSuiteOrError(ctx)).To(Succeed())
+			Expect(framework.NewBareFramework("test").ClusterHasRoles(framework.ClusterA, framework.BrokerRole)).To(BeFalse())
+		})
+	})
+
+	When("the Cluster resource isn't accessible", func() {
+		JustBeforeEach(func() {
+			env.Cluster(framework.ClusterA).DynClient.PrependReactor("get", "clusters",
+				func(_ testing.Action) (bool, runtime.Object, error) {
+					return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "submariner.io", Resource: "clusters"}, "east", nil)
+				})
+		})
+
+		It("should assume the cluster has no global CIDR and not fail the suite", func(ctx context.Context) {
+			Expect(framework.BeforeSuiteOrError(ctx)).To(Succeed())
+			Expect(framework.NewBareFramework("test").ClusterHasRoles(framework.ClusterA, framework.GlobalnetRole)).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("Cluster registry", func() {
+	var f *framework.Framework
+
+	BeforeEach(func(ctx context.Context) {
+		DeferCleanup(fake.NewEnvironment(
+			fake.ClusterConfig{ID: "east", GatewayNodes: []string{"east-gw1", "east-gw2"}, Broker: true, GlobalCIDR: "242.0.0.0/16"},
+			fake.ClusterConfig{ID: "west", GatewayNodes: []string{"west-gw"}, GlobalCIDR: "242.1.0.0/16"},
+			fake.ClusterConfig{ID: "north", GatewayNodes: []string{"north-gw"}},
+			fake.ClusterConfig{ID: "broker"},
+		).Install())
+		DeferCleanup(framework.SetClusterInfos, []framework.ClusterInfo(nil))
+
+		Expect(framework.BeforeSuiteOrError(ctx)).To(Succeed())
+
+		f = framework.NewBareFramework("test")
+	})
+
+	DescribeTable("ClusterByID",
+		func(id string, expectedCluster framework.ClusterIndex, expectedFound bool) {
+			cluster, found := f.ClusterByID(id)
+			Expect(found).To(Equal(expectedFound))
+			Expect(cluster).To(Equal(expectedCluster))
+		},
+		Entry("the first cluster", "east", framework.ClusterA, true),
+		Entry("another cluster", "north", framework.ClusterC, true),
+		Entry("an unknown cluster", "south", framework.ClusterIndex(-1), false),
+	)
+
+	DescribeTable("ClustersWithRoles",
+		func(roles []framework.ClusterRole, expected []framework.ClusterIndex) {
+			Expect(f.ClustersWithRoles(roles...)).To(Equal(expected))
+		},
+		Entry("no role", nil, []framework.ClusterIndex{framework.ClusterA, framework.ClusterB, framework.ClusterC, 3}),
+		Entry("the dataplane role", []framework.ClusterRole{framework.DataplaneRole},
+			[]framework.ClusterIndex{framework.ClusterA, framework.ClusterB, framework.ClusterC}),
+		Entry("the broker role", []framework.ClusterRole{framework.BrokerRole}, []framework.ClusterIndex{framework.ClusterA}),
+		Entry("several roles", []framework.ClusterRole{framework.DataplaneRole, framework.GlobalnetRole},
+			[]framework.ClusterIndex{framework.ClusterA, framework.ClusterB}),
+		Entry("every role", []framework.ClusterRole{framework.MultiGatewayRole, framework.BrokerRole,
+			framework.GlobalnetRole, framework.DataplaneRole}, []framework.ClusterIndex{framework.ClusterA}),
+		Entry("a role no cluster has", []framework.ClusterRole{"unknown"}, []framework.ClusterIndex{}),
+	)
+
+	It("should visit each cluster with the given roles", func() {
+		visited := []framework.ClusterIndex{}
+		f.ForEachCluster(func(cluster framework.ClusterIndex) {
+			visited = append(visited, cluster)
+		}, framework.GlobalnetRole)
+
+		Expect(visited).To(Equal([]framework.ClusterIndex{framework.ClusterA, framework.ClusterB}))
+	})
+
+	It("should visit every cluster if no role is given", func() {
+		visited := []framework.ClusterIndex{}
+		f.ForEachCluster(func(cluster framework.ClusterIndex) {
+			visited = append(visited, cluster)
+		})
+
+		Expect(visited).To(HaveLen(4))
+	})
+
+	It("should visit each ordered pair of distinct clusters with the given roles", func() {
+		type pair struct{ from, to framework.ClusterIndex }
+
+		visited := []pair{}
+		f.ForEachClusterPair(func(from, to framework.ClusterIndex) {
+			visited = append(visited, pair{from, to})
+		}, framework.DataplaneRole)
+
+		Expect(visited).To(Equal([]pair{
+			{framework.ClusterA, framework.ClusterB}, {framework.ClusterA, framework.ClusterC},
+			{framework.ClusterB, framework.ClusterA}, {framework.ClusterB, framework.ClusterC},
+			{framework.ClusterC, framework.ClusterA}, {framework.ClusterC, framework.ClusterB},
+		}))
+	})
+
+	It("should visit no pair if a single cluster has the given roles", func() {
+		f.ForEachClusterPair(func(_, _ framework.ClusterIndex) {
+			Fail("no pair should be visited")
+		}, framework.BrokerRole)
+	})
+})
@@ -0,0 +1,233 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	configFileEnvVar = "E2E_CONFIG"
+	envVarPrefix     = "E2E_"
+)
+
+// flagFields maps the command-line flags to the TestContext fields they set, so that explicitly set flags take
+// precedence over the configuration file and the environment.
+var flagFields = map[string]string{
+	"kubeconfig":           "KubeConfig",
+	"dp-context":           "KubeContexts",
+	"submariner-namespace": "SubmarinerNamespace",
+	"broker-namespace":     "BrokerNamespace",
+	"connection-timeout":   "ConnectionTimeout",
+	"connection-attempts":  "ConnectionAttempts",
+	"operation-timeout":    "OperationTimeout",
+
+	"delete-namespace":               "DeleteNamespace",
+	"delete-namespace-on-failure":    "DeleteNamespaceOnFailure",
+	"wait-for-namespace-deletion":    "WaitForNamespaceDeletion",
+	"stale-namespace-age":            "StaleNamespaceAge",
+	"stale-namespace-dry-run":        "StaleNamespaceDryRun",
+	"artifacts-dir":                  "ArtifactsDir",
+	"watch-events":                   "WatchEvents",
+	"benchmark-repetitions":          "BenchmarkRepetitions",
+	"benchmark-baseline":             "BenchmarkBaseline",
+	"benchmark-refresh-baseline":     "BenchmarkRefreshBaseline",
+	"benchmark-throughput-tolerance": "BenchmarkThroughputTolerance",
+	"benchmark-latency-tolerance":    "BenchmarkLatencyTolerance",
+	"benchmark-fail-on-regression":   "BenchmarkFailOnRegression",
+}
+
+// loadTestContextConfig populates the TestContext from, in increasing order of precedence, the given configuration file,
+// the environment variables and the command-line flags that were explicitly set. Each configurable field can be set via
+// an environment variable named after it with the E2E_ prefix, e.g. E2E_OPERATION_TIMEOUT for OperationTimeout. Lists
+// are comma-separated and structured fields, like GroupVersion, are specified in YAML or JSON.
+func loadTestContextConfig(t *TestContextType, path string) error {
+	fromFlags := *t
+
+	if path != "" {
+		if err := loadConfigFile(t, path); err != nil {
+			return err
+		}
+	}
+
+	if err := applyEnvOverrides(t); err != nil {
+		return err
+	}
+
+	src := reflect.ValueOf(&fromFlags).Elem()
+	dst := reflect.ValueOf(t).Elem()
+
+	flag.Visit(func(f *flag.Flag) {
+		if field, found := flagFields[f.Name]; found {
+			dst.FieldByName(field).Set(src.FieldByName(field))
+		}
+	})
+
+	return nil
+}
+
+func loadConfigFile(t *TestContextType, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "error reading the configuration file")
+	}
+
+	fromFile := &TestContextType{}
+	if err := yaml.UnmarshalStrict(data, fromFile); err != nil {
+		return errors.Wrapf(err, "error parsing configuration file %q", path)
+	}
+
+	// KUBECONFIG only provides a default for KubeConfig, which mustn't conflict with KubeConfigs set in the file.
+	if len(fromFile.KubeConfigs) > 0 && fromFile.KubeConfig == "" {
+		t.KubeConfig = ""
+	}
+
+	// Partial Ginkgo configurations override the defaults rather than replacing them.
+	suiteConfig, reporterConfig := ginkgo.GinkgoConfiguration()
+
+	if fromFile.SuiteConfig != nil && t.SuiteConfig == nil {
+		t.SuiteConfig = &suiteConfig
+	}
+
+	if fromFile.ReporterConfig != nil && t.ReporterConfig == nil {
+		t.ReporterConfig = &reporterConfig
+	}
+
+	return errors.Wrapf(yaml.UnmarshalStrict(data, t), "error parsing configuration file %q", path)
+}
+
+func applyEnvOverrides(t *TestContextType) error {
+	value := reflect.ValueOf(t).Elem()
+
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		if field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		name := envVarName(field.Name)
+
+		envValue, found := os.LookupEnv(name)
+		if !found {
+			continue
+		}
+
+		if err := setFromString(value.Field(i), envValue); err != nil {
+			return errors.WithMessagef(err, "invalid value %q for environment variable %s", envValue, name)
+		}
+	}
+
+	return nil
+}
+
+// envVarName converts a field name to the corresponding environment variable name, e.g. NettestImageURL to
+// E2E_NETTEST_IMAGE_URL.
+func envVarName(fieldName string) string {
+	var name strings.Builder
+
+	name.WriteString(envVarPrefix)
+
+	for i, r := range fieldName {
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(rune(fieldName[i-1])) {
+			name.WriteByte('_')
+		}
+
+		name.WriteRune(unicode.ToUpper(r))
+	}
+
+	return name.String()
+}
+
+func setFromString(field reflect.Value, value string) error {
+	switch {
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		items := reflect.MakeSlice(field.Type(), 0, 0)
+
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = reflect.Append(items, reflect.ValueOf(item).Convert(field.Type().Elem()))
+			}
+		}
+
+		field.Set(items)
+	default:
+		return errors.Wrap(yaml.UnmarshalStrict([]byte(value), field.Addr().Interface()), "error parsing the value")
+	}
+
+	return nil
+}
+
+func validateTestContext(t *TestContextType) error {
+	if t.KubeConfig != "" && len(t.KubeConfigs) > 0 {
+		return errors.New("either kubeConfig or kubeConfigs must be specified but not both")
+	}
+
+	if len(t.KubeConfigs) > 0 && len(t.ClusterIDs) != len(t.KubeConfigs) {
+		return fmt.Errorf("one cluster ID must be provided for each of the %d kubeConfigs, got %d", len(t.KubeConfigs),
+			len(t.ClusterIDs))
+	}
+
+	if len(t.KubeConfigs) == 0 && len(t.ClusterIDs) > 0 && len(t.ClusterIDs) != len(t.KubeContexts) {
+		return fmt.Errorf("one cluster ID must be provided for each of the %d kubeContexts, got %d", len(t.KubeContexts),
+			len(t.ClusterIDs))
+	}
+
+	seen := map[string]bool{}
+
+	for _, id := range t.ClusterIDs {
+		if seen[id] {
+			return fmt.Errorf("duplicate cluster ID %q", id)
+		}
+
+		seen[id] = true
+	}
+
+	if t.SubmarinerNamespace == "" {
+		return errors.New("submarinerNamespace must be specified")
+	}
+
+	if t.NettestImageURL == "" {
+		return errors.New("nettestImageURL must be specified")
+	}
+
+	if t.OperationTimeout == 0 || t.ConnectionTimeout == 0 || t.ConnectionAttempts == 0 {
+		return errors.New("operationTimeout, connectionTimeout and connectionAttempts must be greater than zero")
+	}
+
+	if t.ClientQPS <= 0 || t.ClientBurst <= 0 {
+		return errors.New("clientQPS and clientBurst must be greater than zero")
+	}
+
+	if t.BenchmarkThroughputTolerance < 0 || t.BenchmarkLatencyTolerance < 0 {
+		return errors.New("benchmarkThroughputTolerance and benchmarkLatencyTolerance must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = DescribeTable("envVarName",
+	func(field, expected string) {
+		Expect(framework.EnvVarName(field)).To(Equal(expected))
+	},
+	Entry("single word", "KubeConfig", "E2E_KUBE_CONFIG"),
+	Entry("trailing acronym", "NettestImageURL", "E2E_NETTEST_IMAGE_URL"),
+	Entry("acronym after a word", "ClientQPS", "E2E_CLIENT_QPS"),
+	Entry("plural", "ClusterIDs", "E2E_CLUSTER_IDS"),
+)
+
+var _ = DescribeTable("setFromString",
+	func(field, value string, expected interface{}) {
+		t := &framework.TestContextType{}
+		Expect(framework.SetFieldFromString(t, field, value)).To(Succeed())
+		Expect(t).To(HaveField(field, expected))
+	},
+	Entry("string", "SubmarinerNamespace", "submariner-operator", "submariner-operator"),
+	Entry("comma-separated list", "ClusterIDs", "east, west,,north", []string{"east", "west", "north"}),
+	Entry("list of a named string type", "KubeContexts", "east,west", ContainElements("east", "west")),
+	Entry("unsigned integer", "OperationTimeout", "42", uint(42)),
+	Entry("float", "ClientQPS", "12.5", float32(12.5)),
+	Entry("boolean", "DeleteNamespace", "true", true),
+	Entry("duration", "StaleNamespaceAge", "1h30m", metav1.Duration{Duration: 90 * time.Minute}),
+	Entry("YAML structure", "GroupVersion", "{group: submariner.io, version: v1}",
+		&schema.GroupVersion{Group: "submariner.io", Version: "v1"}),
+	Entry("JSON structure", "GroupVersion", `{"group": "submariner.io", "version": "v1"}`,
+		&schema.GroupVersion{Group: "submariner.io", Version: "v1"}),
+)
+
+var _ = Describe("setFromString", func() {
+	It("should return an error for an invalid value", func() {
+		t := &framework.TestContextType{}
+		Expect(framework.SetFieldFromString(t, "OperationTimeout", "soon")).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("loadTestContextConfig", func() {
+	var (
+		t          *framework.TestContextType
+		configPath string
+	)
+
+	setEnv := func(name, value string) {
+		Expect(os.Setenv(name, value)).To(Succeed())
+		DeferCleanup(os.Unsetenv, name)
+	}
+
+	BeforeEach(func() {
+		// The defaults set by the flags.
+		t = &framework.TestContextType{
+			SubmarinerNamespace: "submariner-operator",
+			OperationTimeout:    190,
+			ConnectionTimeout:   18,
+		}
+
+		configPath = filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(configPath, []byte(`
+submarinerNamespace: from-file
+operationTimeout: 60
+clusterIDs: [east, west]
+artifactsDir: /from/file
+`), 0o600)).To(Succeed())
+	})
+
+	It("should use the defaults without a file or environment variables", func() {
+		Expect(framework.LoadTestContextConfig(t, "")).To(Succeed())
+		Expect(t.SubmarinerNamespace).To(Equal("submariner-operator"))
+		Expect(t.OperationTimeout).To(Equal(uint(190)))
+	})
+
+	It("should override the defaults with the file", func() {
+		Expect(framework.LoadTestContextConfig(t, configPath)).To(Succeed())
+		Expect(t.SubmarinerNamespace).To(Equal("from-file"))
+		Expect(t.OperationTimeout).To(Equal(uint(60)))
+		Expect(t.ClusterIDs).To(Equal([]string{"east", "west"}))
+		Expect(t.ConnectionTimeout).To(Equal(uint(18)))
+	})
+
+	It("should override the file with the environment variables", func() {
+		setEnv("E2E_OPERATION_TIMEOUT", "30")
+		setEnv("E2E_CLUSTER_IDS", "north,south")
+
+		Expect(framework.LoadTestContextConfig(t, configPath)).To(Succeed())
+		Expect(t.OperationTimeout).To(Equal(uint(30)))
+		Expect(t.ClusterIDs).To(Equal([]string{"north", "south"}))
+		Expect(t.SubmarinerNamespace).To(Equal("from-file"))
+	})
+
+	It("should override the environment variables with the explicitly set flags", func() {
+		// A flag can't be unset once set, so this uses one which no other spec relies on.
+		Expect(flag.Set("artifacts-dir", "/from/flag")).To(Succeed())
+		setEnv("E2E_ARTIFACTS_DIR", "/from/env")
+
+		t.ArtifactsDir = "/from/flag"
+
+		Expect(framework.LoadTestContextConfig(t, configPath)).To(Succeed())
+		Expect(t.ArtifactsDir).To(Equal("/from/flag"))
+	})
+
+	It("should ignore the fields discovered from the clusters", func() {
+		setEnv("E2E_GLOBALNET_ENABLED", "true")
+
+		Expect(framework.LoadTestContextConfig(t, "")).To(Succeed())
+		Expect(t.GlobalnetEnabled).To(BeFalse())
+	})
+
+	It("should return an error for an unknown field in the file", func() {
+		Expect(os.WriteFile(configPath, []byte("operationTimeouts: 60\n"), 0o600)).To(Succeed())
+		Expect(framework.LoadTestContextConfig(t, configPath)).To(MatchError(ContainSubstring("error parsing configuration file")))
+	})
+
+	It("should return an error for an invalid environment variable", func() {
+		setEnv("E2E_CONNECTION_ATTEMPTS", "many")
+		Expect(framework.LoadTestContextConfig(t, "")).To(MatchError(ContainSubstring("E2E_CONNECTION_ATTEMPTS")))
+	})
+})
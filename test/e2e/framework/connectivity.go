@@ -0,0 +1,163 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ConnectivityTestParams describes a connectivity test between a connector pod in FromCluster and a listener pod in
+// ToCluster.
+type ConnectivityTestParams struct {
+	Framework             *Framework
+	Networking            NetworkingType
+	ConnectionTimeout     uint
+	ConnectionAttempts    uint
+	FromCluster           ClusterIndex
+	FromClusterScheduling NetworkPodScheduling
+	ToCluster             ClusterIndex
+	ToClusterScheduling   NetworkPodScheduling
+	ToEndpointType        EndpointType
+	// IPFamily is the IP family used to connect, the primary family of the clusters if empty.
+	IPFamily corev1.IPFamily
+}
+
+// ConnectivityProtocol describes the listener and connector pods exchanging data over a protocol.
+type ConnectivityProtocol struct {
+	// Name is the protocol name, as shown in the test steps.
+	Name             string
+	ListenerPodType  NetworkPodType
+	ConnectorPodType NetworkPodType
+}
+
+var (
+	TCPConnectivity = ConnectivityProtocol{Name: "TCP", ListenerPodType: ListenerPod, ConnectorPodType: ConnectorPod}
+	UDPConnectivity = ConnectivityProtocol{Name: "UDP", ListenerPodType: UDPListenerPod, ConnectorPodType: UDPConnectorPod}
+	// SCTPConnectivity tests fail with ErrSCTPNotSupported if the listener's node doesn't support SCTP.
+	SCTPConnectivity = ConnectivityProtocol{Name: "SCTP", ListenerPodType: SCTPListenerPod, ConnectorPodType: SCTPConnectorPod}
+)
+
+// RunConnectivityTestOrError creates a listener pod and a connector pod exchanging data over the given protocol and
+// verifies that they received each other's data. The connection timeout and attempts default to the TestContext's.
+// The pods are returned whenever they were created, even if the verification failed, so they can be inspected further.
+func RunConnectivityTestOrError(ctx context.Context, protocol ConnectivityProtocol, p ConnectivityTestParams) (*NetworkPod,
+	*NetworkPod, error,
+) {
+	if p.ConnectionTimeout == 0 {
+		p.ConnectionTimeout = TestContext.ConnectionTimeout
+	}
+
+	if p.ConnectionAttempts == 0 {
+		p.ConnectionAttempts = TestContext.ConnectionAttempts
+	}
+
+	listenerPod, connectorPod, err := createConnectivityTestPods(ctx, protocol, &p)
+	if err != nil {
+		return listenerPod, connectorPod, err
+	}
+
+	// With Globalnet, the source IP seen by the listener is the connector's global egress IP.
+	verifySourceIP := p.Networking == PodNetworking && (p.ToEndpointType == PodIPEndpoint || p.ToEndpointType == ServiceIPEndpoint)
+
+	return listenerPod, connectorPod, VerifyDataExchangeOrError(listenerPod, connectorPod, verifySourceIP)
+}
+
+// RunNoConnectivityTestOrError creates a listener pod and a connector pod attempting to exchange data over the given
+// protocol and calls verify to check, from their outcome, that they couldn't. The connection timeout and attempts
+// default to 5 seconds and a single attempt.
+func RunNoConnectivityTestOrError(ctx context.Context, protocol ConnectivityProtocol, p ConnectivityTestParams,
+	verify func(listenerPod, connectorPod *NetworkPod) error,
+) (*NetworkPod, *NetworkPod, error) {
+	if p.ConnectionTimeout == 0 {
+		p.ConnectionTimeout = 5
+	}
+
+	if p.ConnectionAttempts == 0 {
+		p.ConnectionAttempts = 1
+	}
+
+	listenerPod, connectorPod, err := createConnectivityTestPods(ctx, protocol, &p)
+	if err != nil {
+		return listenerPod, connectorPod, err
+	}
+
+	return listenerPod, connectorPod, verify(listenerPod, connectorPod)
+}
+
+func createConnectivityTestPods(ctx context.Context, protocol ConnectivityProtocol, p *ConnectivityTestParams) (*NetworkPod,
+	*NetworkPod, error,
+) {
+	By(fmt.Sprintf("Creating a listener pod in cluster %q, which will wait for a handshake over %s",
+		TestContext.ClusterIDs[p.ToCluster], protocol.Name))
+
+	listenerPod, err := p.Framework.NewNetworkPodOrError(ctx, &NetworkPodConfig{
+		Type:               protocol.ListenerPodType,
+		Cluster:            p.ToCluster,
+		Scheduling:         p.ToClusterScheduling,
+		ConnectionTimeout:  p.ConnectionTimeout,
+		ConnectionAttempts: p.ConnectionAttempts,
+		IPFamily:           p.IPFamily,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	remoteIP, err := listenerPod.RemoteAddressOrError(ctx, p.ToEndpointType)
+	if err != nil {
+		return listenerPod, nil, err
+	}
+
+	Logf("Will send traffic to IP: %v", remoteIP)
+
+	By(fmt.Sprintf("Creating a connector pod in cluster %q, which will attempt the specific UUID handshake over %s",
+		TestContext.ClusterIDs[p.FromCluster], protocol.Name))
+
+	connectorPod, err := p.Framework.NewNetworkPodOrError(ctx, &NetworkPodConfig{
+		Type:               protocol.ConnectorPodType,
+		Cluster:            p.FromCluster,
+		Scheduling:         p.FromClusterScheduling,
+		RemoteIP:           remoteIP,
+		ConnectionTimeout:  p.ConnectionTimeout,
+		ConnectionAttempts: p.ConnectionAttempts,
+		Networking:         p.Networking,
+		IPFamily:           p.IPFamily,
+	})
+	if err != nil {
+		return listenerPod, nil, err
+	}
+
+	By(fmt.Sprintf("Waiting for the connector pod %q to exit, returning what connector sent", connectorPod.Pod.Name))
+	connectorPod.AwaitFinish(ctx)
+
+	By(fmt.Sprintf("Waiting for the listener pod %q to exit, returning what listener sent", listenerPod.Pod.Name))
+	listenerPod.AwaitFinish(ctx)
+
+	Logf("Connector pod has IP: %s", connectorPod.IP())
+
+	if protocol.ListenerPodType == SCTPListenerPod {
+		if err := listenerPod.SCTPNotSupportedError(); err != nil {
+			return listenerPod, connectorPod, err
+		}
+	}
+
+	return listenerPod, connectorPod, nil
+}
@@ -0,0 +1,331 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+)
+
+// ClustersetDomain is the domain under which Lighthouse serves the exported services.
+const ClustersetDomain = "clusterset.local"
+
+type DNSRecordType string
+
+const (
+	ARecord    DNSRecordType = "A"
+	AAAARecord DNSRecordType = "AAAA"
+	SRVRecord  DNSRecordType = "SRV"
+)
+
+// DNSAnswer is a resource record from the answer section of a DNS response.
+type DNSAnswer struct {
+	Name string
+	Type DNSRecordType
+	TTL  uint32
+	// IP is set for A and AAAA records.
+	IP string
+	// Priority, Weight, Port and Target are set for SRV records.
+	Priority uint16
+	Weight   uint16
+	Port     int32
+	Target   string
+}
+
+// DNSResult is the response to a DNS query.
+type DNSResult struct {
+	Name string
+	Type DNSRecordType
+	// Status is the response code, e.g. "NOERROR" or "NXDOMAIN".
+	Status  string
+	Answers []DNSAnswer
+}
+
+var dnsStatusRE = regexp.MustCompile(`status: ([A-Z]+)`)
+
+// ClustersetServiceDNSName returns the name resolving to the service exported from any cluster, e.g.
+// "nginx.default.svc.clusterset.local".
+func ClustersetServiceDNSName(service, namespace string) string {
+	return fmt.Sprintf("%s.%s.svc.%s", service, namespace, ClustersetDomain)
+}
+
+// ClusterServiceDNSName returns the name resolving to the service exported from the given cluster, e.g.
+// "east.nginx.default.svc.clusterset.local".
+func ClusterServiceDNSName(cluster ClusterIndex, service, namespace string) string {
+	return TestContext.ClusterIDs[cluster] + "." + ClustersetServiceDNSName(service, namespace)
+}
+
+// HeadlessPodDNSName returns the name resolving to the given backend pod, identified by its hostname, of the headless
+// service exported from the given cluster, e.g. "web-0.east.nginx.default.svc.clusterset.local".
+func HeadlessPodDNSName(hostname string, cluster ClusterIndex, service, namespace string) string {
+	return hostname + "." + ClusterServiceDNSName(cluster, service, namespace)
+}
+
+// SRVDNSName returns the SRV record name for the given port of the service with the given DNS name, e.g.
+// "_http._tcp.nginx.default.svc.clusterset.local".
+func SRVDNSName(portName string, protocol v1.Protocol, serviceDNSName string) string {
+	return fmt.Sprintf("_%s._%s.%s", portName, strings.ToLower(string(protocol)), serviceDNSName)
+}
+
+// IPs returns the sorted IPs of the A and AAAA answers.
+func (r *DNSResult) IPs() []string {
+	ips := []string{}
+
+	for i := range r.Answers {
+		if r.Answers[i].IP != "" {
+			ips = append(ips, r.Answers[i].IP)
+		}
+	}
+
+	sort.Strings(ips)
+
+	return ips
+}
+
+// SRVAnswers returns the SRV answers.
+func (r *DNSResult) SRVAnswers() []DNSAnswer {
+	answers := []DNSAnswer{}
+
+	for i := range r.Answers {
+		if r.Answers[i].Type == SRVRecord {
+			answers = append(answers, r.Answers[i])
+		}
+	}
+
+	return answers
+}
+
+// QueryDNS runs a DNS query for the given name and record type from a pod in the given cluster. The pod is created in
+// the framework namespace on the first query and reused by subsequent queries on the same cluster.
+func (f *Framework) QueryDNS(ctx context.Context, cluster ClusterIndex, name string, recordType DNSRecordType) *DNSResult {
+	result, err := f.QueryDNSOrError(ctx, cluster, name, recordType)
+	Expect(err).NotTo(HaveOccurred())
+
+	return result
+}
+
+// QueryDNSOrError is like QueryDNS but returns an error instead of failing via Gomega.
+func (f *Framework) QueryDNSOrError(ctx context.Context, cluster ClusterIndex, name string, recordType DNSRecordType,
+) (*DNSResult, error) {
+	clientPod, err := f.dnsClientPod(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, stderr, err := clientPod.RunCommandOrError(ctx, []string{
+		"dig", "+noall", "+comments", "+answer", "+time=5", "+tries=2", name, string(recordType),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error querying %s %q on cluster %q: %s", recordType, name, TestContext.ClusterIDs[cluster],
+			stdout+stderr)
+	}
+
+	return parseDNSResult(name, recordType, stdout)
+}
+
+func (f *Framework) dnsClientPod(ctx context.Context, cluster ClusterIndex) (*NetworkPod, error) {
+	if clientPod, ok := f.dnsClientPods[cluster]; ok {
+		return clientPod, nil
+	}
+
+	clientPod, err := f.NewNetworkPodOrError(ctx, &NetworkPodConfig{
+		Type:          CustomPod,
+		Cluster:       cluster,
+		Scheduling:    NonGatewayNode,
+		ContainerName: "dns-client",
+		ImageName:     TestContext.NettestImageURL,
+		Command:       []string{"sleep", "infinity"},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating the DNS client pod on cluster %q", TestContext.ClusterIDs[cluster])
+	}
+
+	f.dnsClientPods[cluster] = clientPod
+
+	return clientPod, nil
+}
+
+// parseDNSResult parses the output of "dig +noall +comments +answer", e.g.
+//
+//	;; ->>HEADER<<- opcode: QUERY, status: NOERROR, id: 41106
+//	nginx.default.svc.clusterset.local. 5 IN A 10.1.0.12
+//	_http._tcp.nginx.default.svc.clusterset.local. 5 IN SRV 0 50 80 nginx.default.svc.clusterset.local.
+func parseDNSResult(name string, recordType DNSRecordType, output string) (*DNSResult, error) {
+	result := &DNSResult{Name: name, Type: recordType, Answers: []DNSAnswer{}}
+
+	if match := dnsStatusRE.FindStringSubmatch(output); match != nil {
+		result.Status = match[1]
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || strings.HasPrefix(fields[0], ";") || fields[2] != "IN" {
+			continue
+		}
+
+		ttl, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid TTL in DNS answer %q", line)
+		}
+
+		answer := DNSAnswer{
+			Name: strings.TrimSuffix(fields[0], "."),
+			Type: DNSRecordType(fields[3]),
+			TTL:  uint32(ttl),
+		}
+
+		switch answer.Type {
+		case ARecord, AAAARecord:
+			answer.IP = fields[4]
+		case SRVRecord:
+			if err := parseSRVData(fields[4:], &answer); err != nil {
+				return nil, errors.Wrapf(err, "invalid SRV answer %q", line)
+			}
+		}
+
+		result.Answers = append(result.Answers, answer)
+	}
+
+	if result.Status == "" {
+		return nil, fmt.Errorf("no DNS response was received for %s %q: %s", recordType, name, output)
+	}
+
+	return result, nil
+}
+
+func parseSRVData(fields []string, answer *DNSAnswer) error {
+	if len(fields) != 4 {
+		return fmt.Errorf("expected 4 fields, got %d", len(fields))
+	}
+
+	values := make([]uint64, 3)
+
+	for i := range values {
+		var err error
+
+		values[i], err = strconv.ParseUint(fields[i], 10, 16)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value %q", fields[i])
+		}
+	}
+
+	answer.Priority = uint16(values[0])
+	answer.Weight = uint16(values[1])
+	answer.Port = int32(values[2])
+	answer.Target = strings.TrimSuffix(fields[3], ".")
+
+	return nil
+}
+
+// AwaitDNSIPs waits until a DNS query from the given cluster resolves the given name to exactly the expected IPs, in any
+// order.
+func (f *Framework) AwaitDNSIPs(ctx context.Context, cluster ClusterIndex, name string, recordType DNSRecordType,
+	expectedIPs []string,
+) *DNSResult {
+	result, err := f.AwaitDNSIPsOrError(ctx, cluster, name, recordType, expectedIPs)
+	Expect(err).NotTo(HaveOccurred())
+
+	return result
+}
+
+// AwaitDNSIPsOrError is like AwaitDNSIPs but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitDNSIPsOrError(ctx context.Context, cluster ClusterIndex, name string, recordType DNSRecordType,
+	expectedIPs []string,
+) (*DNSResult, error) {
+	expected := slices.Clone(expectedIPs)
+	sort.Strings(expected)
+
+	return f.awaitDNSResultOrError(ctx, cluster, name, recordType, fmt.Sprintf("await %s %q to resolve to %v", recordType, name, expected),
+		func(result *DNSResult) (bool, string) {
+			ips := result.IPs()
+
+			return slices.Equal(ips, expected), fmt.Sprintf("%s %q resolves to %v (%s)", recordType, name, ips, result.Status)
+		})
+}
+
+// AwaitDNSIPsRemoved waits until a DNS query from the given cluster no longer resolves the given name to any of the given
+// IPs.
+func (f *Framework) AwaitDNSIPsRemoved(ctx context.Context, cluster ClusterIndex, name string, recordType DNSRecordType,
+	removedIPs []string,
+) *DNSResult {
+	result, err := f.AwaitDNSIPsRemovedOrError(ctx, cluster, name, recordType, removedIPs)
+	Expect(err).NotTo(HaveOccurred())
+
+	return result
+}
+
+// AwaitDNSIPsRemovedOrError is like AwaitDNSIPsRemoved but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitDNSIPsRemovedOrError(ctx context.Context, cluster ClusterIndex, name string, recordType DNSRecordType,
+	removedIPs []string,
+) (*DNSResult, error) {
+	return f.awaitDNSResultOrError(ctx, cluster, name, recordType, fmt.Sprintf("await %s %q not to resolve to %v", recordType, name,
+		removedIPs), func(result *DNSResult) (bool, string) {
+		ips := result.IPs()
+
+		for _, ip := range removedIPs {
+			if slices.Contains(ips, ip) {
+				return false, fmt.Sprintf("%s %q still resolves to %v", recordType, name, ips)
+			}
+		}
+
+		return true, ""
+	})
+}
+
+func (f *Framework) awaitDNSResultOrError(ctx context.Context, cluster ClusterIndex, name string, recordType DNSRecordType,
+	opMsg string, check func(*DNSResult) (bool, string),
+) (*DNSResult, error) {
+	// Failing to create the client pod isn't a propagation delay, so it isn't retried.
+	if _, err := f.dnsClientPod(ctx, cluster); err != nil {
+		return nil, err
+	}
+
+	var queryErr error
+
+	result, err := awaitUntilOrError(ctx, opMsg, func() (interface{}, error) {
+		// DNS failures are expected while the records are being propagated, so they're retried rather than returned.
+		var result *DNSResult
+
+		result, queryErr = f.QueryDNSOrError(ctx, cluster, name, recordType)
+
+		return result, nil
+	}, func(result interface{}) (bool, string, error) {
+		if queryErr != nil {
+			return false, queryErr.Error(), nil
+		}
+
+		ok, msg := check(result.(*DNSResult))
+
+		return ok, msg, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*DNSResult), nil
+}
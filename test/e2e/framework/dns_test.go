@@ -0,0 +1,165 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/framework/fake"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/testing"
+)
+
+// digHeader is the start of the output of "dig +noall +comments +answer", as run by QueryDNS, for a given status.
+const digHeader = ";; Got answer:\n" +
+	";; ->>HEADER<<- opcode: QUERY, status: %s, id: 41106\n" +
+	";; flags: qr aa rd; QUERY: 1, ANSWER: 2, AUTHORITY: 0, ADDITIONAL: 1\n" +
+	";; WARNING: recursion requested but not available\n" +
+	"\n" +
+	";; OPT PSEUDOSECTION:\n" +
+	"; EDNS: version: 0, flags:; udp: 1232\n" +
+	"; COOKIE: 3d8a1f7e0b7c4a2b0100000066f1c3a2e5b0d8c41e7f2a96 (good)\n"
+
+var digNoErrorHeader = fmt.Sprintf(digHeader, "NOERROR") + ";; ANSWER SECTION:\n"
+
+var _ = DescribeTable("parseDNSResult",
+	func(recordType framework.DNSRecordType, output string, expected *framework.DNSResult) {
+		result, err := framework.ParseDNSResult(expected.Name, recordType, output)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(expected))
+	},
+	Entry("A records", framework.ARecord,
+		digNoErrorHeader+
+			"nginx.default.svc.clusterset.local. 5 IN\tA\t10.1.0.12\n"+
+			"nginx.default.svc.clusterset.local. 5 IN\tA\t10.2.0.7\n",
+		&framework.DNSResult{
+			Name: "nginx.default.svc.clusterset.local", Type: framework.ARecord, Status: "NOERROR",
+			Answers: []framework.DNSAnswer{
+				{Name: "nginx.default.svc.clusterset.local", Type: framework.ARecord, TTL: 5, IP: "10.1.0.12"},
+				{Name: "nginx.default.svc.clusterset.local", Type: framework.ARecord, TTL: 5, IP: "10.2.0.7"},
+			},
+		}),
+	Entry("AAAA record", framework.AAAARecord,
+		digNoErrorHeader+
+			"east.nginx.default.svc.clusterset.local. 5 IN AAAA fd00:10:1::c\n",
+		&framework.DNSResult{
+			Name: "east.nginx.default.svc.clusterset.local", Type: framework.AAAARecord, Status: "NOERROR",
+			Answers: []framework.DNSAnswer{
+				{Name: "east.nginx.default.svc.clusterset.local", Type: framework.AAAARecord, TTL: 5, IP: "fd00:10:1::c"},
+			},
+		}),
+	Entry("SRV records", framework.SRVRecord,
+		digNoErrorHeader+
+			"_http._tcp.nginx.default.svc.clusterset.local. 5 IN SRV 0 50 80 nginx.default.svc.clusterset.local.\n"+
+			"_http._tcp.nginx.default.svc.clusterset.local. 5 IN SRV 0 50 80 east.nginx.default.svc.clusterset.local.\n",
+		&framework.DNSResult{
+			Name: "_http._tcp.nginx.default.svc.clusterset.local", Type: framework.SRVRecord, Status: "NOERROR",
+			Answers: []framework.DNSAnswer{
+				{
+					Name: "_http._tcp.nginx.default.svc.clusterset.local", Type: framework.SRVRecord, TTL: 5,
+					Priority: 0, Weight: 50, Port: 80, Target: "nginx.default.svc.clusterset.local",
+				},
+				{
+					Name: "_http._tcp.nginx.default.svc.clusterset.local", Type: framework.SRVRecord, TTL: 5,
+					Priority: 0, Weight: 50, Port: 80, Target: "east.nginx.default.svc.clusterset.local",
+				},
+			},
+		}),
+	Entry("CNAME answer followed by the A record", framework.ARecord,
+		digNoErrorHeader+
+			"web.example.com. 30 IN CNAME nginx.default.svc.clusterset.local.\n"+
+			"nginx.default.svc.clusterset.local. 5 IN A 10.1.0.12\n",
+		&framework.DNSResult{
+			Name: "web.example.com", Type: framework.ARecord, Status: "NOERROR",
+			Answers: []framework.DNSAnswer{
+				{Name: "web.example.com", Type: "CNAME", TTL: 30},
+				{Name: "nginx.default.svc.clusterset.local", Type: framework.ARecord, TTL: 5, IP: "10.1.0.12"},
+			},
+		}),
+	Entry("no answer", framework.ARecord,
+		digNoErrorHeader,
+		&framework.DNSResult{
+			Name: "nginx.default.svc.clusterset.local", Type: framework.ARecord, Status: "NOERROR",
+			Answers: []framework.DNSAnswer{},
+		}),
+	Entry("unknown name", framework.ARecord,
+		fmt.Sprintf(digHeader, "NXDOMAIN"),
+		&framework.DNSResult{
+			Name: "missing.default.svc.clusterset.local", Type: framework.ARecord, Status: "NXDOMAIN",
+			Answers: []framework.DNSAnswer{},
+		}),
+	Entry("server failure", framework.SRVRecord,
+		fmt.Sprintf(digHeader, "SERVFAIL"),
+		&framework.DNSResult{
+			Name: "_http._tcp.nginx.default.svc.clusterset.local", Type: framework.SRVRecord, Status: "SERVFAIL",
+			Answers: []framework.DNSAnswer{},
+		}),
+)
+
+var _ = DescribeTable("parseDNSResult errors",
+	func(recordType framework.DNSRecordType, output, expectedError string) {
+		_, err := framework.ParseDNSResult("nginx.default.svc.clusterset.local", recordType, output)
+		Expect(err).To(MatchError(ContainSubstring(expectedError)))
+	},
+	Entry("no response", framework.ARecord,
+		";; communications error to 100.96.0.10#53: timed out\n"+
+			";; communications error to 100.96.0.10#53: timed out\n"+
+			";; no servers could be reached\n",
+		"no DNS response was received"),
+	Entry("invalid TTL", framework.ARecord,
+		digNoErrorHeader+"nginx.default.svc.clusterset.local. soon IN A 10.1.0.12\n",
+		"invalid TTL"),
+	Entry("truncated SRV data", framework.SRVRecord,
+		digNoErrorHeader+"_http._tcp.nginx.default.svc.clusterset.local. 5 IN SRV 0 50 80\n",
+		"expected 4 fields, got 3"),
+	Entry("invalid SRV port", framework.SRVRecord,
+		digNoErrorHeader+"_http._tcp.nginx.default.svc.clusterset.local. 5 IN SRV 0 50 http nginx.default.svc.clusterset.local.\n",
+		`invalid value "http"`),
+	Entry("SRV port out of range", framework.SRVRecord,
+		digNoErrorHeader+"_http._tcp.nginx.default.svc.clusterset.local. 5 IN SRV 0 50 65536 nginx.default.svc.clusterset.local.\n",
+		`invalid value "65536"`),
+)
+
+var _ = Describe("AwaitDNSIPsOrError", func() {
+	It("should return the error creating the DNS client pod without retrying", func(ctx context.Context) {
+		env := fake.NewEnvironment(fake.ClusterConfig{ID: "east", NonGatewayNodes: []string{"east-worker"}})
+		DeferCleanup(env.Install())
+
+		creates := 0
+
+		env.Cluster(framework.ClusterA).KubeClient.PrependReactor("create", "pods",
+			func(_ testing.Action) (bool, runtime.Object, error) {
+				creates++
+				return true, nil, errors.New("fake create error")
+			})
+
+		f := framework.NewBareFramework("dns")
+		f.Namespace = "e2e-tests-dns"
+
+		_, err := f.AwaitDNSIPsOrError(ctx, framework.ClusterA, "nginx.default.svc.clusterset.local", framework.ARecord,
+			[]string{"10.1.0.12"})
+		Expect(err).To(MatchError(And(ContainSubstring("error creating the DNS client pod"), ContainSubstring("fake create error"))))
+		Expect(creates).To(Equal(1))
+	})
+})
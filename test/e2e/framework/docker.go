@@ -20,6 +20,7 @@ package framework
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -36,14 +37,14 @@ func New(name string) *Docker {
 	return &Docker{Name: name}
 }
 
-func (d *Docker) GetIP(networkName string) string {
+func (d *Docker) GetIP(ctx context.Context, networkName string) string {
 	var stdout bytes.Buffer
 
 	cmdargs := []string{
 		"inspect", d.Name, "-f",
 		fmt.Sprintf("{{(index .NetworkSettings.Networks %q).IPAddress}}", networkName),
 	}
-	cmd := exec.Command("docker", cmdargs...)
+	cmd := exec.CommandContext(ctx, "docker", cmdargs...)
 	cmd.Stdout = &stdout
 	err := cmd.Run()
 	Expect(err).NotTo(HaveOccurred())
@@ -52,12 +53,12 @@ func (d *Docker) GetIP(networkName string) string {
 	return strings.TrimSuffix(stdout.String(), "\n")
 }
 
-func (d *Docker) GetLog() (string, string) {
+func (d *Docker) GetLog(ctx context.Context) (string, string) {
 	var stdout, stderr bytes.Buffer
 
 	// get stdout and stderr of `docker log {d.Name}` command
 	// #nosec G204 -- the caller-controlled value is only used as the logs argument
-	cmd := exec.Command("docker", "logs", d.Name)
+	cmd := exec.CommandContext(ctx, "docker", "logs", d.Name)
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
@@ -67,12 +68,12 @@ func (d *Docker) GetLog() (string, string) {
 	return stdout.String(), stderr.String()
 }
 
-func (d *Docker) runCommand(command ...string) (string, string, error) {
+func (d *Docker) runCommand(ctx context.Context, command ...string) (string, string, error) {
 	var stdout, stderr bytes.Buffer
 
 	cmdargs := []string{"exec", "-i", d.Name}
 	cmdargs = append(cmdargs, command...)
-	cmd := exec.Command("docker", cmdargs...)
+	cmd := exec.CommandContext(ctx, "docker", cmdargs...)
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
@@ -81,22 +82,22 @@ func (d *Docker) runCommand(command ...string) (string, string, error) {
 	return stdout.String(), stderr.String(), err
 }
 
-func (d *Docker) RunCommand(command ...string) (string, string) {
-	stdout, stderr, err := d.runCommand(command...)
+func (d *Docker) RunCommand(ctx context.Context, command ...string) (string, string) {
+	stdout, stderr, err := d.runCommand(ctx, command...)
 	Expect(err).NotTo(HaveOccurred())
 
 	return stdout, stderr
 }
 
-func (d *Docker) RunCommandUntil(command ...string) (string, string) {
+func (d *Docker) RunCommandUntil(ctx context.Context, command ...string) (string, string) {
 	var stdout, stderr string
 
 	Eventually(func() error {
 		var err error
 
-		stdout, stderr, err = d.runCommand(command...)
+		stdout, stderr, err = d.runCommand(ctx, command...)
 		return err
-	}, TestContext.OperationTimeoutToDuration(), 5*time.Second).Should(Succeed(),
+	}, TestContext.OperationTimeoutToDuration(), 5*time.Second).WithContext(ctx).Should(Succeed(),
 		"Error attempting to run %v", append([]string{}, command...))
 
 	return stdout, stderr
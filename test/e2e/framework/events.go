@@ -0,0 +1,254 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// CapturedEvent is a kubernetes event observed while a spec was running.
+type CapturedEvent struct {
+	ClusterID string
+	Namespace string
+	Type      string
+	Reason    string
+	Object    string
+	Message   string
+	Timestamp time.Time
+}
+
+func (e *CapturedEvent) String() string {
+	return fmt.Sprintf("%s [%s] %s %s %s/%s: %s", e.Timestamp.Format(time.StampMilli), e.ClusterID, e.Type, e.Reason,
+		e.Namespace, e.Object, e.Message)
+}
+
+// eventWatcher watches the events in a set of namespaces on every cluster, logging and recording them until stopped.
+// The watches run in their own goroutines, which can't use By, so they log to the given writer instead.
+type eventWatcher struct {
+	mutex  sync.Mutex
+	events []CapturedEvent
+	errs   []string
+	out    io.Writer
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// startEventWatcher starts watching the events in the given namespaces on every cluster, logging them to the given
+// writer. The watches outlive the given context, which is typically that of the BeforeEach node, until the watcher is
+// stopped.
+func startEventWatcher(ctx context.Context, out io.Writer, namespaces ...string) *eventWatcher {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	w := &eventWatcher{cancel: cancel, out: out}
+
+	for i := range KubeClients {
+		for _, namespace := range namespaces {
+			w.wg.Add(1)
+
+			go func() {
+				defer w.wg.Done()
+				w.watch(ctx, ClusterIndex(i), namespace)
+			}()
+		}
+	}
+
+	return w
+}
+
+// stop stops the watches and returns the recorded events, and the errors which prevented watching some namespaces.
+func (w *eventWatcher) stop() ([]CapturedEvent, []string) {
+	w.cancel()
+	w.wg.Wait()
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.events, w.errs
+}
+
+func (w *eventWatcher) watch(ctx context.Context, cluster ClusterIndex, namespace string) {
+	clusterID := TestContext.ClusterIDs[cluster]
+	events := KubeClients[cluster].CoreV1().Events(namespace)
+	resourceVersion := ""
+	expired := true
+
+	for ctx.Err() == nil {
+		if expired {
+			// Only the events occurring from now on are of interest, so (re)start watching from the current resource version.
+			list, err := events.List(ctx, metav1.ListOptions{Limit: 1})
+			if err != nil {
+				w.fail(fmt.Sprintf("Error listing events in namespace %q on cluster %q, they aren't captured: %v", namespace,
+					clusterID, err))
+
+				return
+			}
+
+			resourceVersion = list.ResourceVersion
+		}
+
+		watcher, err := events.Watch(ctx, metav1.ListOptions{ResourceVersion: resourceVersion})
+		if err != nil {
+			w.logf("ERROR", "Error watching events in namespace %q on cluster %q: %v", namespace, clusterID, err)
+
+			if !sleepWithContext(ctx, PollInterval) {
+				return
+			}
+
+			continue
+		}
+
+		resourceVersion, expired = w.record(ctx, watcher, clusterID, resourceVersion)
+		if expired && !sleepWithContext(ctx, PollInterval) {
+			return
+		}
+	}
+}
+
+// record records the events received from the given watcher until it's closed or the context is done. It returns the
+// last resource version seen, so the watch can be resumed, and whether the watch failed, e.g. because the resource
+// version expired, in which case it must be restarted from the current resource version.
+func (w *eventWatcher) record(ctx context.Context, watcher watch.Interface, clusterID, resourceVersion string) (string, bool) {
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return resourceVersion, false
+		case result, ok := <-watcher.ResultChan():
+			if !ok {
+				return resourceVersion, false
+			}
+
+			if result.Type == watch.Error {
+				return resourceVersion, true
+			}
+
+			event, ok := result.Object.(*corev1.Event)
+			if !ok {
+				continue
+			}
+
+			resourceVersion = event.ResourceVersion
+
+			if result.Type == watch.Added || result.Type == watch.Modified {
+				w.add(newCapturedEvent(clusterID, event))
+			}
+		}
+	}
+}
+
+func (w *eventWatcher) add(event *CapturedEvent) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.events = append(w.events, *event)
+
+	fmt.Fprintf(w.out, "EVENT: %s\n", event)
+}
+
+// fail records an error which stops the watch of a namespace, to be reported once the watcher is stopped.
+func (w *eventWatcher) fail(message string) {
+	w.logf("ERROR", "%s", message)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.errs = append(w.errs, message)
+}
+
+func (w *eventWatcher) logf(level, format string, args ...interface{}) {
+	fmt.Fprintf(w.out, nowStamp()+": "+level+": "+format+"\n", args...)
+}
+
+func newCapturedEvent(clusterID string, event *corev1.Event) *CapturedEvent {
+	timestamp := event.LastTimestamp.Time
+	if timestamp.IsZero() {
+		timestamp = event.EventTime.Time
+	}
+
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	return &CapturedEvent{
+		ClusterID: clusterID,
+		Namespace: event.Namespace,
+		Type:      event.Type,
+		Reason:    event.Reason,
+		Object:    strings.ToLower(event.InvolvedObject.Kind) + "/" + event.InvolvedObject.Name,
+		Message:   strings.TrimSpace(event.Message),
+		Timestamp: timestamp,
+	}
+}
+
+// Events returns the kubernetes events captured in the test namespaces and the Submariner namespace while the
+// current, or last, spec ran. Events are only captured if TestContext.WatchEvents is set. They're also logged as they
+// occur, to the GinkgoWriter for frameworks created by NewFramework and to the standard output otherwise.
+func (f *Framework) Events() []CapturedEvent {
+	return f.events
+}
+
+// EventsReport formats the captured events, one per line, preceded by the errors which prevented capturing some of them.
+func (f *Framework) EventsReport() string {
+	lines := make([]string, 0, len(f.eventErrors)+len(f.events))
+	for _, err := range f.eventErrors {
+		lines = append(lines, "ERROR: "+err)
+	}
+
+	for i := range f.events {
+		lines = append(lines, f.events[i].String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (f *Framework) startWatchingEvents(ctx context.Context) {
+	f.events = nil
+	f.eventErrors = nil
+
+	if !TestContext.WatchEvents {
+		return
+	}
+
+	namespaces := []string{TestContext.SubmarinerNamespace}
+	for ns := range f.namespacesToDelete {
+		namespaces = append(namespaces, ns)
+	}
+
+	f.eventWatcher = startEventWatcher(ctx, f.eventWriter, namespaces...)
+}
+
+func (f *Framework) stopWatchingEvents() {
+	if f.eventWatcher != nil {
+		f.events, f.eventErrors = f.eventWatcher.stop()
+		f.eventWatcher = nil
+
+		for _, err := range f.eventErrors {
+			Errorf("%s", err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/framework/fake"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/testing"
+)
+
+var _ = Describe("Event capture", func() {
+	var (
+		env      *fake.Environment
+		f        *framework.Framework
+		out      *gbytes.Buffer
+		watchers chan *watch.FakeWatcher
+	)
+
+	BeforeEach(func() {
+		env = fake.NewEnvironment(fake.ClusterConfig{ID: "east", GatewayNodes: []string{"east-gw"}})
+		DeferCleanup(env.Install())
+
+		framework.TestContext.WatchEvents = true
+
+		// The watches are served by fake watchers so the tests control exactly which events are received.
+		watchers = make(chan *watch.FakeWatcher, 10)
+		env.Cluster(framework.ClusterA).KubeClient.PrependWatchReactor("events", func(action testing.Action) (bool, watch.Interface, error) {
+			if action.GetNamespace() == framework.TestContext.SubmarinerNamespace {
+				return false, nil, nil
+			}
+
+			watcher := watch.NewFake()
+			watchers <- watcher
+
+			return true, watcher, nil
+		})
+
+		out = gbytes.NewBuffer()
+		f = framework.NewBareFramework("events")
+		framework.SetEventWriter(f, out)
+	})
+
+	It("should capture the events occurring in the test namespace", func(ctx context.Context) {
+		Expect(f.BeforeEachOrError(ctx)).To(Succeed())
+
+		var watcher *watch.FakeWatcher
+		Eventually(watchers).Should(Receive(&watcher))
+
+		timestamp := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		watcher.Add(&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "event", Namespace: f.Namespace, ResourceVersion: "5"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "FailedScheduling",
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "nettest"},
+			Message:        "0/1 nodes are available\n",
+			LastTimestamp:  metav1.NewTime(timestamp),
+		})
+
+		Eventually(out).Should(gbytes.Say(`EVENT: .* \[east\] Warning FailedScheduling e2e-tests-events.*/pod/nettest`))
+
+		namespace := f.Namespace
+		Expect(f.AfterEachOrError(ctx)).To(Succeed())
+
+		Expect(f.Events()).To(Equal([]framework.CapturedEvent{{
+			ClusterID: "east",
+			Namespace: namespace,
+			Type:      corev1.EventTypeWarning,
+			Reason:    "FailedScheduling",
+			Object:    "pod/nettest",
+			Message:   "0/1 nodes are available",
+			Timestamp: timestamp,
+		}}))
+		Expect(f.EventsReport()).To(HaveSuffix(namespace + "/pod/nettest: 0/1 nodes are available"))
+	})
+
+	It("should report the namespaces whose events couldn't be listed", func(ctx context.Context) {
+		env.Cluster(framework.ClusterA).KubeClient.PrependReactor("list", "events", func(action testing.Action) (bool, runtime.Object, error) {
+			if action.GetNamespace() != framework.TestContext.SubmarinerNamespace {
+				return false, nil, nil
+			}
+
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "events"}, "", nil)
+		})
+
+		Expect(f.BeforeEachOrError(ctx)).To(Succeed())
+		Eventually(out).Should(gbytes.Say(`ERROR: Error listing events in namespace "submariner"`))
+		Expect(f.AfterEachOrError(ctx)).To(Succeed())
+
+		Expect(f.EventsReport()).To(HavePrefix(`ERROR: Error listing events in namespace "submariner" on cluster "east", ` +
+			"they aren't captured: events is forbidden"))
+	})
+
+	It("should not capture events unless requested", func(ctx context.Context) {
+		framework.TestContext.WatchEvents = false
+
+		Expect(f.BeforeEachOrError(ctx)).To(Succeed())
+		Expect(f.AfterEachOrError(ctx)).To(Succeed())
+
+		Expect(watchers).NotTo(Receive())
+		Expect(f.Events()).To(BeEmpty())
+		Expect(f.EventsReport()).To(BeEmpty())
+	})
+})
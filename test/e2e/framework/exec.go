@@ -85,7 +85,10 @@ func (f *Framework) ExecWithOptions(ctx context.Context, options *ExecOptions, i
 			break
 		}
 
-		time.Sleep(time.Millisecond * 5000)
+		if !sleepWithContext(ctx, time.Millisecond*5000) {
+			break
+		}
+
 		Logf("Retrying due to error  %+v", err)
 	}
 
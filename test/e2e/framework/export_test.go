@@ -0,0 +1,77 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"io"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	mcsv1a1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+)
+
+// NodeAffinity exposes nodeAffinity to the tests, for a network pod in the given cluster.
+func NodeAffinity(ctx context.Context, cluster ClusterIndex, scheduling NetworkPodScheduling) (*v1.Affinity, error) {
+	np := &NetworkPod{Config: &NetworkPodConfig{Cluster: cluster}}
+
+	return np.nodeAffinity(ctx, scheduling)
+}
+
+// NewExistingNetworkPod returns a NetworkPod for the given pod, which is assumed to exist already.
+func NewExistingNetworkPod(f *Framework, pod *v1.Pod, config *NetworkPodConfig) *NetworkPod {
+	return &NetworkPod{Pod: pod, Config: config, framework: f}
+}
+
+// SetClusterInfos sets the cluster information discovered by BeforeSuite.
+func SetClusterInfos(infos []ClusterInfo) {
+	clusterInfos = infos
+}
+
+// SetEventWriter sets the writer the framework logs the captured events to.
+func SetEventWriter(f *Framework, w io.Writer) {
+	f.eventWriter = w
+}
+
+// SetEnvironmentFingerprint sets the fingerprint reported by ReportEnvironment.
+func SetEnvironmentFingerprint(fingerprint *EnvironmentFingerprint) {
+	environmentFingerprint = fingerprint
+}
+
+// SetFieldFromString exposes setFromString for the named TestContextType field.
+func SetFieldFromString(t *TestContextType, field, value string) error {
+	return setFromString(reflect.ValueOf(t).Elem().FieldByName(field), value)
+}
+
+// UnmetBy exposes unmetBy to the tests.
+func UnmetBy(e ServiceExportConditionExpectation, conditions []mcsv1a1.ServiceExportCondition) string {
+	return e.unmetBy(conditions)
+}
+
+var (
+	FetchClusterIDs       = fetchClusterIDs
+	ParseHTTPProbeResult  = parseHTTPProbeResult
+	ParseThroughputResult = parseThroughputResult
+	ParseLatencyResult    = parseLatencyResult
+	LoadTestContextConfig = loadTestContextConfig
+	ParseDNSResult        = parseDNSResult
+	EnvVarName            = envVarName
+	EndpointAddresses     = endpointAddresses
+	NetperfLatencyCommand = netperfLatencyCommand
+)
@@ -0,0 +1,222 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake provides a fake multi-cluster environment, backed by the client-go fake clients, so code using the e2e
+// framework can be unit tested without real clusters.
+package fake
+
+import (
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/testing"
+)
+
+const (
+	defaultSubmarinerNamespace = "submariner"
+	defaultBrokerNamespace     = "submariner-k8s-broker"
+)
+
+// ClusterConfig describes a fake cluster.
+type ClusterConfig struct {
+	// ID is the Submariner cluster ID.
+	ID string
+
+	// GatewayNodes are the names of the nodes labeled as gateways. The first one hosts the active gateway.
+	GatewayNodes []string
+
+	// NonGatewayNodes are the names of the other nodes.
+	NonGatewayNodes []string
+
+	// Broker indicates whether the cluster hosts the broker namespace.
+	Broker bool
+
+	// GlobalCIDR is the Globalnet CIDR of the cluster. If any cluster has one, Globalnet is detected as enabled.
+	GlobalCIDR string
+
+	// Provider is the prefix of the nodes' provider ID, e.g. "kind". It defaults to "fake".
+	Provider string
+
+	// ServerVersion is the kubernetes version reported by discovery. It defaults to 1.31.
+	ServerVersion *version.Info
+
+	// KubeObjects are additional objects preloaded into the kubernetes clientset.
+	KubeObjects []runtime.Object
+
+	// DynamicObjects are additional unstructured objects preloaded into the dynamic client.
+	DynamicObjects []runtime.Object
+}
+
+// Cluster holds the fake clients of a cluster.
+type Cluster struct {
+	Config     ClusterConfig
+	KubeClient *fakekube.Clientset
+	DynClient  *fakedynamic.FakeDynamicClient
+}
+
+// Environment is a set of fake clusters, indexed in the same order as the framework's ClusterIndex.
+type Environment struct {
+	Clusters []*Cluster
+}
+
+// NewEnvironment creates a fake cluster for each given config, preloaded with the nodes, Submariner pods and Submariner
+// CRs that the framework expects to find.
+func NewEnvironment(configs ...ClusterConfig) *Environment {
+	env := &Environment{}
+
+	for i := range configs {
+		env.Clusters = append(env.Clusters, newCluster(&configs[i]))
+	}
+
+	return env
+}
+
+func newCluster(config *ClusterConfig) *Cluster {
+	if config.Provider == "" {
+		config.Provider = "fake"
+	}
+
+	if config.ServerVersion == nil {
+		config.ServerVersion = &version.Info{Major: "1", Minor: "31", GitVersion: "v1.31.0"}
+	}
+
+	kubeObjs := append(kubeObjects(config), config.KubeObjects...)
+	kubeClient := fakekube.NewSimpleClientset(kubeObjs...)
+	kubeClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = config.ServerVersion
+	kubeClient.PrependReactor("create", "*", generateNameReactor)
+
+	dynClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+
+	for _, obj := range append(submarinerObjects(config), config.DynamicObjects...) {
+		addDynamicObject(dynClient.Tracker(), obj)
+	}
+
+	dynClient.PrependReactor("create", "*", generateNameReactor)
+
+	return &Cluster{
+		Config:     *config,
+		KubeClient: kubeClient,
+		DynClient:  dynClient,
+	}
+}
+
+// Install replaces the framework's clients and cluster IDs with the fake ones. framework.BeforeSuiteOrError may be called
+// afterwards to initialize the remaining suite state from the fake clusters. The returned function restores the clients
+// and the TestContext as they were before, including any changes made to the TestContext since, e.g.
+//
+//	DeferCleanup(env.Install())
+func (e *Environment) Install() (restore func()) {
+	restConfigs, kubeClients, dynClients := framework.RestConfigs, framework.KubeClients, framework.DynClients
+	testContext := *framework.TestContext
+
+	restore = func() {
+		framework.RestConfigs, framework.KubeClients, framework.DynClients = restConfigs, kubeClients, dynClients
+		*framework.TestContext = testContext
+	}
+
+	framework.RestConfigs = nil
+	framework.KubeClients = nil
+	framework.DynClients = nil
+	framework.TestContext.ClusterIDs = nil
+
+	if framework.TestContext.SubmarinerNamespace == "" {
+		framework.TestContext.SubmarinerNamespace = defaultSubmarinerNamespace
+	}
+
+	if framework.TestContext.BrokerNamespace == "" {
+		framework.TestContext.BrokerNamespace = defaultBrokerNamespace
+	}
+
+	for _, cluster := range e.Clusters {
+		framework.RestConfigs = append(framework.RestConfigs, &rest.Config{Host: "https://" + cluster.Config.ID})
+		framework.KubeClients = append(framework.KubeClients, kubernetes.Interface(cluster.KubeClient))
+		framework.DynClients = append(framework.DynClients, dynamic.Interface(cluster.DynClient))
+		framework.TestContext.ClusterIDs = append(framework.TestContext.ClusterIDs, cluster.Config.ID)
+	}
+
+	return restore
+}
+
+// Cluster returns the fake cluster with the given index.
+func (e *Environment) Cluster(index framework.ClusterIndex) *Cluster {
+	return e.Clusters[index]
+}
+
+// addDynamicObject adds the object to the dynamic client's tracker under its known resource. The tracker would otherwise
+// guess the resource from the kind, and get irregular plurals such as "gateways" wrong.
+func addDynamicObject(tracker testing.ObjectTracker, obj runtime.Object) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+
+	for knownGVR, listKind := range listKinds {
+		if knownGVR.GroupVersion() == gvk.GroupVersion() && listKind == gvk.Kind+"List" {
+			gvr = knownGVR
+		}
+	}
+
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := tracker.Create(gvr, obj, objMeta.GetNamespace()); err != nil {
+		panic(err)
+	}
+}
+
+// generateNameReactor emulates the API server's handling of GenerateName, which the fake object trackers don't support.
+func generateNameReactor(action testing.Action) (bool, runtime.Object, error) {
+	setGeneratedName(action.(testing.CreateAction).GetObject())
+
+	return false, nil, nil
+}
+
+func setGeneratedName(obj runtime.Object) {
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	if objMeta.GetName() == "" && objMeta.GetGenerateName() != "" {
+		objMeta.SetName(objMeta.GetGenerateName() + utilrand.String(5))
+	}
+}
+
+func submarinerNamespace() string {
+	if framework.TestContext.SubmarinerNamespace != "" {
+		return framework.TestContext.SubmarinerNamespace
+	}
+
+	return defaultSubmarinerNamespace
+}
+
+func brokerNamespace() string {
+	if framework.TestContext.BrokerNamespace != "" {
+		return framework.TestContext.BrokerNamespace
+	}
+
+	return defaultBrokerNamespace
+}
@@ -0,0 +1,125 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/framework/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Environment", func() {
+	var env *fake.Environment
+
+	BeforeEach(func() {
+		env = fake.NewEnvironment(
+			fake.ClusterConfig{
+				ID:              "east",
+				GatewayNodes:    []string{"east-gw1", "east-gw2"},
+				NonGatewayNodes: []string{"east-worker"},
+				Broker:          true,
+				GlobalCIDR:      "242.0.0.0/16",
+				Provider:        "kind",
+			},
+			fake.ClusterConfig{ID: "west", GatewayNodes: []string{"west-gw"}, GlobalCIDR: "242.1.0.0/16"},
+		)
+		DeferCleanup(env.Install())
+	})
+
+	It("should install the fake clients and cluster IDs in the framework", func() {
+		Expect(framework.TestContext.ClusterIDs).To(Equal([]string{"east", "west"}))
+		Expect(framework.KubeClients).To(HaveLen(2))
+		Expect(framework.DynClients).To(HaveLen(2))
+		Expect(framework.KubeClients[framework.ClusterB]).To(BeIdenticalTo(env.Cluster(framework.ClusterB).KubeClient))
+	})
+
+	It("should restore the framework globals and the TestContext", func() {
+		kubeClients := framework.KubeClients
+		testContext := *framework.TestContext
+
+		restore := fake.NewEnvironment(fake.ClusterConfig{ID: "north"}).Install()
+		framework.TestContext.GlobalnetEnabled = true
+		framework.TestContext.SubmarinerNamespace = "other"
+
+		Expect(framework.TestContext.ClusterIDs).To(Equal([]string{"north"}))
+
+		restore()
+
+		Expect(framework.KubeClients).To(Equal(kubeClients))
+		Expect(*framework.TestContext).To(Equal(testContext))
+		Expect(framework.TestContext.ClusterIDs).To(Equal([]string{"east", "west"}))
+	})
+
+	It("should preload the Submariner resources", func(ctx context.Context) {
+		dynClient := env.Cluster(framework.ClusterA).DynClient
+		namespace := framework.TestContext.SubmarinerNamespace
+
+		gateways, err := dynClient.Resource(fake.GatewayGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gateways.Items).To(HaveLen(2))
+
+		endpoints, err := dynClient.Resource(fake.EndpointGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(endpoints.Items).To(HaveLen(2))
+
+		cluster, err := dynClient.Resource(fake.ClusterGVR).Namespace(namespace).Get(ctx, "east", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cluster.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("global_cidr", ConsistOf("242.0.0.0/16"))))
+	})
+
+	It("should preload the gateway nodes and pods", func(ctx context.Context) {
+		Expect(framework.FindGatewayNodes(ctx, framework.ClusterA)).To(HaveLen(2))
+		Expect(framework.FindNonGatewayNodes(ctx, framework.ClusterA)).To(HaveLen(1))
+
+		pods, err := env.Cluster(framework.ClusterA).KubeClient.CoreV1().Pods(framework.TestContext.SubmarinerNamespace).List(ctx,
+			metav1.ListOptions{LabelSelector: framework.ActiveGatewayLabel})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pods.Items).To(HaveLen(1))
+		Expect(pods.Items[0].Spec.NodeName).To(Equal("east-gw1"))
+	})
+
+	It("should generate the names of created objects with a GenerateName", func(ctx context.Context) {
+		pod, err := framework.KubeClients[framework.ClusterA].CoreV1().Pods("default").Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{GenerateName: "test-"},
+		}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pod.Name).To(HavePrefix("test-"))
+		Expect(len(pod.Name)).To(BeNumerically(">", len("test-")))
+	})
+
+	It("should support BeforeSuiteOrError", func(ctx context.Context) {
+		Expect(framework.BeforeSuiteOrError(ctx)).To(Succeed())
+
+		f := framework.NewBareFramework("fake")
+		Expect(f.ClustersWithRoles(framework.BrokerRole)).To(Equal([]framework.ClusterIndex{framework.ClusterA}))
+		Expect(f.ClustersWithRoles(framework.GlobalnetRole)).To(HaveLen(2))
+		Expect(f.ClusterInfo(framework.ClusterA).Provider).To(Equal("kind"))
+		Expect(f.ClusterInfo(framework.ClusterA).NumGatewayNodes).To(Equal(2))
+	})
+
+	It("should have Globalnet detected from the GlobalCIDR", func(ctx context.Context) {
+		Expect(framework.DetectGlobalnetOrError(ctx)).To(Succeed())
+		Expect(framework.TestContext.GlobalnetEnabled).To(BeTrue())
+	})
+})
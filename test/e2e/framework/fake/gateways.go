@@ -0,0 +1,180 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/testing"
+)
+
+const (
+	haStatusActive  = "active"
+	haStatusPassive = "passive"
+)
+
+var podGVR = corev1.SchemeGroupVersion.WithResource("pods")
+
+func (s *Simulator) addGatewayReactors(c *simulatedCluster) {
+	reactor := s.afterReaction(c, c.KubeClient.Tracker(), func(c *simulatedCluster, _ testing.Action, obj runtime.Object,
+	) (runtime.Object, error) {
+		return obj, s.onNodeChanged(c, obj.(*corev1.Node))
+	})
+
+	c.KubeClient.PrependReactor("update", "nodes", reactor)
+	c.KubeClient.PrependReactor("patch", "nodes", reactor)
+}
+
+func (s *Simulator) onNodeChanged(c *simulatedCluster, node *corev1.Node) error {
+	isGateway := node.Labels[framework.GatewayLabel] == "true"
+	index := slices.Index(c.gateways, node.Name)
+
+	switch {
+	case isGateway && index < 0:
+		c.gateways = append(c.gateways, node.Name)
+
+		if c.activeGateway == "" {
+			c.activeGateway = node.Name
+		}
+
+		err := createOrUpdate(c.DynClient.Tracker(), EndpointGVR, newEndpoint(&c.Config, node.Name))
+		if err != nil {
+			return err
+		}
+	case !isGateway && index >= 0:
+		c.gateways = slices.Delete(c.gateways, index, index+1)
+
+		if c.activeGateway == node.Name {
+			c.activeGateway = ""
+
+			if len(c.gateways) > 0 {
+				c.activeGateway = c.gateways[0]
+			}
+		}
+
+		if err := s.removeGateway(c, node.Name); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	return s.syncGateways()
+}
+
+func (s *Simulator) removeGateway(c *simulatedCluster, name string) error {
+	namespace := submarinerNamespace()
+
+	err := deleteIfExists(c.KubeClient.Tracker(), podGVR, namespace, gatewayPodName(name))
+	if err != nil {
+		return err
+	}
+
+	err = deleteIfExists(c.DynClient.Tracker(), GatewayGVR, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	return deleteIfExists(c.DynClient.Tracker(), EndpointGVR, namespace, endpointName(&c.Config, name))
+}
+
+// syncGateways writes the status of every gateway from the simulated state.
+func (s *Simulator) syncGateways() error {
+	for _, c := range s.clusters {
+		for _, name := range c.gateways {
+			if err := s.syncGateway(c, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Simulator) syncGateway(c *simulatedCluster, name string) error {
+	haStatus := haStatusPassive
+	connections := []interface{}{}
+
+	if name == c.activeGateway {
+		haStatus = haStatusActive
+
+		for _, remote := range s.clusters {
+			if remote == c || remote.activeGateway == "" {
+				continue
+			}
+
+			conn, found := c.connections[remote.Config.ID]
+			if !found {
+				conn = connectionStatus{status: ConnectionConnected}
+			}
+
+			connections = append(connections, map[string]interface{}{
+				"status":        conn.status,
+				"statusMessage": conn.message,
+				"endpoint":      endpointSpec(&remote.Config, remote.activeGateway),
+			})
+		}
+	}
+
+	gateway := NewUnstructured(submarinerGroupVersion, "Gateway", submarinerNamespace(), name, map[string]interface{}{
+		"status": map[string]interface{}{
+			"haStatus":      haStatus,
+			"localEndpoint": endpointSpec(&c.Config, name),
+			"connections":   connections,
+		},
+	})
+
+	if err := createOrUpdate(c.DynClient.Tracker(), GatewayGVR, gateway); err != nil {
+		return err
+	}
+
+	return setGatewayPodStatus(c, name, haStatus)
+}
+
+func setGatewayPodStatus(c *simulatedCluster, name, haStatus string) error {
+	namespace := submarinerNamespace()
+	tracker := c.KubeClient.Tracker()
+
+	obj, err := tracker.Get(podGVR, namespace, gatewayPodName(name))
+	if apierrors.IsNotFound(err) {
+		return errors.Wrapf(tracker.Create(podGVR, newPod(namespace, gatewayPodName(name), name, map[string]string{
+			"app":              framework.SubmarinerGateway,
+			gatewayNodeLabel:   name,
+			gatewayStatusLabel: haStatus,
+		}), namespace), "error creating the gateway pod on %q", name)
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving the gateway pod on %q", name)
+	}
+
+	pod := obj.(*corev1.Pod)
+	if pod.Labels[gatewayStatusLabel] == haStatus {
+		return nil
+	}
+
+	pod.Labels[gatewayStatusLabel] = haStatus
+
+	return errors.Wrapf(tracker.Update(podGVR, pod, namespace), "error updating the gateway pod on %q", name)
+}
@@ -0,0 +1,280 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/testing"
+)
+
+const (
+	// ClusterGlobalEgressIPName is the name of the ClusterGlobalEgressIP created by Globalnet.
+	ClusterGlobalEgressIPName = "cluster-egress.submariner.io"
+
+	defaultClusterGlobalEgressIPs = 8
+)
+
+// globalIPOwner identifies an object to which global IPs are allocated.
+type globalIPOwner struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+	name      string
+}
+
+func (o globalIPOwner) String() string {
+	return fmt.Sprintf("%s %s/%s", o.gvr.Resource, o.namespace, o.name)
+}
+
+// ipPool allocates IPs from a Globalnet CIDR. IPs are handed out round robin so a reallocation always yields new IPs
+// while the pool isn't exhausted.
+type ipPool struct {
+	prefix    netip.Prefix
+	next      netip.Addr
+	allocated map[netip.Addr]bool
+	owned     map[globalIPOwner][]netip.Addr
+}
+
+func newIPPool(cidr string) (*ipPool, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid GlobalCIDR %q", cidr)
+	}
+
+	prefix = prefix.Masked()
+
+	return &ipPool{
+		prefix:    prefix,
+		next:      prefix.Addr().Next(),
+		allocated: map[netip.Addr]bool{},
+		owned:     map[globalIPOwner][]netip.Addr{},
+	}, nil
+}
+
+func (p *ipPool) allocate(owner globalIPOwner, count int) ([]string, error) {
+	addrs := make([]netip.Addr, 0, count)
+	addr := p.next
+
+	for scanned := 0; len(addrs) < count; scanned++ {
+		if scanned == p.size() {
+			for _, a := range addrs {
+				delete(p.allocated, a)
+			}
+
+			return nil, fmt.Errorf("unable to allocate %d IP(s) for %s: GlobalCIDR %q is exhausted", count, owner, p.prefix)
+		}
+
+		if !p.allocated[addr] {
+			p.allocated[addr] = true
+			addrs = append(addrs, addr)
+		}
+
+		addr = addr.Next()
+		if !p.prefix.Contains(addr) {
+			addr = p.prefix.Addr().Next()
+		}
+	}
+
+	p.next = addr
+	p.owned[owner] = append(p.owned[owner], addrs...)
+
+	return toStrings(addrs), nil
+}
+
+// size returns the number of allocatable IPs, excluding the network address.
+func (p *ipPool) size() int {
+	return 1<<min(p.prefix.Addr().BitLen()-p.prefix.Bits(), 24) - 1
+}
+
+func (p *ipPool) release(owner globalIPOwner) {
+	for _, addr := range p.owned[owner] {
+		delete(p.allocated, addr)
+	}
+
+	delete(p.owned, owner)
+}
+
+func (p *ipPool) reallocate(owner globalIPOwner) ([]string, error) {
+	old := p.owned[owner]
+	delete(p.owned, owner)
+
+	ips, err := p.allocate(owner, len(old))
+	if err != nil {
+		p.owned[owner] = old
+		return nil, err
+	}
+
+	for _, addr := range old {
+		delete(p.allocated, addr)
+	}
+
+	return ips, nil
+}
+
+func (p *ipPool) owners() []globalIPOwner {
+	owners := make([]globalIPOwner, 0, len(p.owned))
+	for owner := range p.owned {
+		owners = append(owners, owner)
+	}
+
+	sort.Slice(owners, func(i, j int) bool {
+		return owners[i].String() < owners[j].String()
+	})
+
+	return owners
+}
+
+func toStrings(addrs []netip.Addr) []string {
+	ips := make([]string, len(addrs))
+	for i := range addrs {
+		ips[i] = addrs[i].String()
+	}
+
+	return ips
+}
+
+func (s *Simulator) addGlobalnetReactors(c *simulatedCluster) {
+	tracker := c.DynClient.Tracker()
+
+	for gvr, defaultNumIPs := range map[schema.GroupVersionResource]int64{
+		GlobalIngressIPGVR:       1,
+		GlobalEgressIPGVR:        1,
+		ClusterGlobalEgressIPGVR: defaultClusterGlobalEgressIPs,
+	} {
+		c.DynClient.PrependReactor("create", gvr.Resource, s.afterReaction(c, tracker,
+			func(c *simulatedCluster, _ testing.Action, obj runtime.Object) (runtime.Object, error) {
+				return allocateGlobalIPs(c, gvr, obj.(*unstructured.Unstructured), defaultNumIPs)
+			}))
+
+		c.DynClient.PrependReactor("delete", gvr.Resource, s.afterReaction(c, tracker,
+			func(c *simulatedCluster, action testing.Action, obj runtime.Object) (runtime.Object, error) {
+				c.ipPool.release(globalIPOwner{gvr: gvr, namespace: action.GetNamespace(), name: action.(testing.DeleteAction).GetName()})
+				return obj, nil
+			}))
+	}
+}
+
+func (s *Simulator) createClusterGlobalEgressIP(c *simulatedCluster) error {
+	obj := NewUnstructured(submarinerGroupVersion, "ClusterGlobalEgressIP", "", ClusterGlobalEgressIPName,
+		map[string]interface{}{
+			"spec": map[string]interface{}{
+				"numberOfIPs": int64(defaultClusterGlobalEgressIPs),
+			},
+		})
+
+	if err := createOrUpdate(c.DynClient.Tracker(), ClusterGlobalEgressIPGVR, obj); err != nil {
+		return err
+	}
+
+	_, err := allocateGlobalIPs(c, ClusterGlobalEgressIPGVR, obj, defaultClusterGlobalEgressIPs)
+
+	return err
+}
+
+// createGlobalIngressIP creates the GlobalIngressIP that Globalnet allocates for an exported ClusterIP service.
+func (s *Simulator) createGlobalIngressIP(c *simulatedCluster, namespace, serviceName string) error {
+	obj := NewUnstructured(submarinerGroupVersion, "GlobalIngressIP", namespace, serviceName, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"target": "ClusterIPService",
+			"serviceRef": map[string]interface{}{
+				"name": serviceName,
+			},
+		},
+	})
+
+	if err := createOrUpdate(c.DynClient.Tracker(), GlobalIngressIPGVR, obj); err != nil {
+		return err
+	}
+
+	_, err := allocateGlobalIPs(c, GlobalIngressIPGVR, obj, 1)
+
+	return err
+}
+
+func (s *Simulator) deleteGlobalIngressIP(c *simulatedCluster, namespace, serviceName string) error {
+	c.ipPool.release(globalIPOwner{gvr: GlobalIngressIPGVR, namespace: namespace, name: serviceName})
+
+	return deleteIfExists(c.DynClient.Tracker(), GlobalIngressIPGVR, namespace, serviceName)
+}
+
+func allocateGlobalIPs(c *simulatedCluster, gvr schema.GroupVersionResource, obj *unstructured.Unstructured, defaultNumIPs int64,
+) (*unstructured.Unstructured, error) {
+	owner := globalIPOwner{gvr: gvr, namespace: obj.GetNamespace(), name: obj.GetName()}
+	c.ipPool.release(owner)
+
+	numIPs := defaultNumIPs
+	if gvr != GlobalIngressIPGVR {
+		numIPs = numberOfIPs(obj, defaultNumIPs)
+	}
+
+	ips, err := c.ipPool.allocate(owner, int(numIPs))
+	if err != nil {
+		return nil, err
+	}
+
+	return setAllocatedIPs(c, owner, ips)
+}
+
+// numberOfIPs reads spec.numberOfIPs, which may hold any numeric type when set by a caller of the fake client.
+func numberOfIPs(obj *unstructured.Unstructured, defaultNumIPs int64) int64 {
+	value, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "numberOfIPs")
+	if !found {
+		return defaultNumIPs
+	}
+
+	switch n := value.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case float64:
+		return int64(n)
+	}
+
+	return defaultNumIPs
+}
+
+func setAllocatedIPs(c *simulatedCluster, owner globalIPOwner, ips []string) (*unstructured.Unstructured, error) {
+	tracker := c.DynClient.Tracker()
+
+	obj, err := tracker.Get(owner.gvr, owner.namespace, owner.name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving %s", owner)
+	}
+
+	u := obj.(*unstructured.Unstructured)
+
+	if owner.gvr == GlobalIngressIPGVR {
+		err = unstructured.SetNestedField(u.Object, ips[0], "status", "allocatedIP")
+	} else {
+		err = unstructured.SetNestedStringSlice(u.Object, ips, "status", "allocatedIPs")
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "error setting the allocated IPs of %s", owner)
+	}
+
+	return u, errors.Wrapf(tracker.Update(owner.gvr, u, owner.namespace), "error updating %s", owner)
+}
@@ -0,0 +1,209 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/testing"
+)
+
+const mcsGroupVersion = "multicluster.x-k8s.io/v1alpha1"
+
+var serviceGVR = corev1.SchemeGroupVersion.WithResource("services")
+
+func (s *Simulator) addLighthouseReactors(c *simulatedCluster) {
+	tracker := c.DynClient.Tracker()
+
+	c.DynClient.PrependReactor("create", ServiceExportGVR.Resource, s.afterReaction(c, tracker,
+		func(c *simulatedCluster, _ testing.Action, obj runtime.Object) (runtime.Object, error) {
+			return s.onServiceExported(c, obj.(*unstructured.Unstructured))
+		}))
+
+	c.DynClient.PrependReactor("delete", ServiceExportGVR.Resource, s.afterReaction(c, tracker,
+		func(c *simulatedCluster, action testing.Action, obj runtime.Object) (runtime.Object, error) {
+			return obj, s.onServiceUnexported(c, action.GetNamespace(), action.(testing.DeleteAction).GetName())
+		}))
+}
+
+func (s *Simulator) onServiceExported(c *simulatedCluster, export *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	obj, err := c.KubeClient.Tracker().Get(serviceGVR, export.GetNamespace(), export.GetName())
+	if apierrors.IsNotFound(err) {
+		return setServiceExportConditions(c, export,
+			newCondition("Valid", metav1.ConditionFalse, "ServiceUnavailable", "Service to be exported doesn't exist"))
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving Service %s/%s", export.GetNamespace(), export.GetName())
+	}
+
+	service := obj.(*corev1.Service)
+
+	if c.ipPool != nil && service.Spec.ClusterIP != corev1.ClusterIPNone {
+		if err := s.createGlobalIngressIP(c, service.Namespace, service.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, cluster := range s.clusters {
+		if err := addToServiceImport(cluster, c.Config.ID, service); err != nil {
+			return nil, err
+		}
+	}
+
+	return setServiceExportConditions(c, export,
+		newCondition("Valid", metav1.ConditionTrue, "", "Service was successfully exported"),
+		newCondition("Ready", metav1.ConditionTrue, "", "Service was successfully exported to the broker"))
+}
+
+func (s *Simulator) onServiceUnexported(c *simulatedCluster, namespace, name string) error {
+	if c.ipPool != nil {
+		if err := s.deleteGlobalIngressIP(c, namespace, name); err != nil {
+			return err
+		}
+	}
+
+	for _, cluster := range s.clusters {
+		if err := removeFromServiceImport(cluster, c.Config.ID, namespace, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func addToServiceImport(c *simulatedCluster, clusterID string, service *corev1.Service) error {
+	importType := "ClusterSetIP"
+	if service.Spec.ClusterIP == corev1.ClusterIPNone {
+		importType = "Headless"
+	}
+
+	ports := []interface{}{}
+	for i := range service.Spec.Ports {
+		ports = append(ports, map[string]interface{}{
+			"name":     service.Spec.Ports[i].Name,
+			"port":     int64(service.Spec.Ports[i].Port),
+			"protocol": string(service.Spec.Ports[i].Protocol),
+		})
+	}
+
+	clusters, err := serviceImportClusters(c, service.Namespace, service.Name)
+	if err != nil {
+		return err
+	}
+
+	if !containsCluster(clusters, clusterID) {
+		clusters = append(clusters, map[string]interface{}{"cluster": clusterID})
+	}
+
+	return createOrUpdate(c.DynClient.Tracker(), ServiceImportGVR, NewUnstructured(mcsGroupVersion, "ServiceImport",
+		service.Namespace, service.Name, map[string]interface{}{
+			"spec": map[string]interface{}{
+				"type":  importType,
+				"ports": ports,
+			},
+			"status": map[string]interface{}{
+				"clusters": clusters,
+			},
+		}))
+}
+
+func removeFromServiceImport(c *simulatedCluster, clusterID, namespace, name string) error {
+	tracker := c.DynClient.Tracker()
+
+	obj, err := tracker.Get(ServiceImportGVR, namespace, name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving ServiceImport %s/%s", namespace, name)
+	}
+
+	serviceImport := obj.(*unstructured.Unstructured)
+	clusters, _, _ := unstructured.NestedSlice(serviceImport.Object, "status", "clusters")
+
+	remaining := []interface{}{}
+
+	for _, cluster := range clusters {
+		if !containsCluster([]interface{}{cluster}, clusterID) {
+			remaining = append(remaining, cluster)
+		}
+	}
+
+	if len(remaining) == 0 {
+		return deleteIfExists(tracker, ServiceImportGVR, namespace, name)
+	}
+
+	if err := unstructured.SetNestedSlice(serviceImport.Object, remaining, "status", "clusters"); err != nil {
+		return errors.Wrapf(err, "error setting the clusters of ServiceImport %s/%s", namespace, name)
+	}
+
+	return createOrUpdate(tracker, ServiceImportGVR, serviceImport)
+}
+
+func serviceImportClusters(c *simulatedCluster, namespace, name string) ([]interface{}, error) {
+	obj, err := c.DynClient.Tracker().Get(ServiceImportGVR, namespace, name)
+	if apierrors.IsNotFound(err) {
+		return []interface{}{}, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving ServiceImport %s/%s", namespace, name)
+	}
+
+	clusters, _, _ := unstructured.NestedSlice(obj.(*unstructured.Unstructured).Object, "status", "clusters")
+
+	return clusters, nil
+}
+
+func containsCluster(clusters []interface{}, clusterID string) bool {
+	for _, cluster := range clusters {
+		if m, ok := cluster.(map[string]interface{}); ok && m["cluster"] == clusterID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newCondition(condType string, status metav1.ConditionStatus, reason, message string) interface{} {
+	return map[string]interface{}{
+		"type":               condType,
+		"status":             string(status),
+		"reason":             reason,
+		"message":            message,
+		"lastTransitionTime": time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+func setServiceExportConditions(c *simulatedCluster, export *unstructured.Unstructured, conditions ...interface{},
+) (*unstructured.Unstructured, error) {
+	if err := unstructured.SetNestedSlice(export.Object, conditions, "status", "conditions"); err != nil {
+		return nil, errors.Wrapf(err, "error setting the conditions of ServiceExport %q", export.GetName())
+	}
+
+	return export, createOrUpdate(c.DynClient.Tracker(), ServiceExportGVR, export)
+}
@@ -0,0 +1,217 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"fmt"
+
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	submarinerGroupVersion = "submariner.io/v1"
+	hostnameLabel          = "kubernetes.io/hostname"
+	gatewayNodeLabel       = "gateway.submariner.io/node"
+	gatewayStatusLabel     = "gateway.submariner.io/status"
+)
+
+var (
+	GatewayGVR               = schema.GroupVersionResource{Group: "submariner.io", Version: "v1", Resource: "gateways"}
+	ClusterGVR               = schema.GroupVersionResource{Group: "submariner.io", Version: "v1", Resource: "clusters"}
+	EndpointGVR              = schema.GroupVersionResource{Group: "submariner.io", Version: "v1", Resource: "endpoints"}
+	GlobalIngressIPGVR       = schema.GroupVersionResource{Group: "submariner.io", Version: "v1", Resource: "globalingressips"}
+	GlobalEgressIPGVR        = schema.GroupVersionResource{Group: "submariner.io", Version: "v1", Resource: "globalegressips"}
+	ClusterGlobalEgressIPGVR = schema.GroupVersionResource{Group: "submariner.io", Version: "v1", Resource: "clusterglobalegressips"}
+	ServiceExportGVR         = schema.GroupVersionResource{Group: "multicluster.x-k8s.io", Version: "v1alpha1", Resource: "serviceexports"}
+	ServiceImportGVR         = schema.GroupVersionResource{Group: "multicluster.x-k8s.io", Version: "v1alpha1", Resource: "serviceimports"}
+)
+
+var listKinds = map[schema.GroupVersionResource]string{
+	GatewayGVR:               "GatewayList",
+	ClusterGVR:               "ClusterList",
+	EndpointGVR:              "EndpointList",
+	GlobalIngressIPGVR:       "GlobalIngressIPList",
+	GlobalEgressIPGVR:        "GlobalEgressIPList",
+	ClusterGlobalEgressIPGVR: "ClusterGlobalEgressIPList",
+	ServiceExportGVR:         "ServiceExportList",
+	ServiceImportGVR:         "ServiceImportList",
+}
+
+func kubeObjects(config *ClusterConfig) []runtime.Object {
+	namespace := submarinerNamespace()
+
+	objs := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}},
+	}
+
+	if config.Broker {
+		objs = append(objs, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: brokerNamespace()}})
+	}
+
+	for i, name := range config.GatewayNodes {
+		objs = append(objs, newNode(config, name, true))
+
+		status := "passive"
+		if i == 0 {
+			status = "active"
+		}
+
+		objs = append(objs, newPod(namespace, gatewayPodName(name), name, map[string]string{
+			"app":              framework.SubmarinerGateway,
+			gatewayNodeLabel:   name,
+			gatewayStatusLabel: status,
+		}))
+	}
+
+	for _, name := range config.NonGatewayNodes {
+		objs = append(objs, newNode(config, name, false))
+	}
+
+	for _, name := range append(append([]string{}, config.GatewayNodes...), config.NonGatewayNodes...) {
+		objs = append(objs, newPod(namespace, fmt.Sprintf("%s-%s", framework.RouteAgent, name), name, map[string]string{
+			"app": framework.RouteAgent,
+		}))
+	}
+
+	if len(config.GatewayNodes) > 0 {
+		objs = append(objs, &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      framework.SubmarinerGateway,
+				Namespace: namespace,
+			},
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name: framework.SubmarinerGateway,
+							Env:  []corev1.EnvVar{{Name: "SUBMARINER_CLUSTERID", Value: config.ID}},
+						}},
+					},
+				},
+			},
+		})
+	}
+
+	return objs
+}
+
+func newNode(config *ClusterConfig, name string, isGateway bool) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{hostnameLabel: name},
+		},
+		Spec: corev1.NodeSpec{
+			ProviderID: fmt.Sprintf("%s://%s", config.Provider, name),
+		},
+	}
+
+	if isGateway {
+		node.Labels[framework.GatewayLabel] = "true"
+	}
+
+	return node
+}
+
+func newPod(namespace, name, nodeName string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+}
+
+func submarinerObjects(config *ClusterConfig) []runtime.Object {
+	namespace := submarinerNamespace()
+
+	clusterSpec := map[string]interface{}{
+		"cluster_id": config.ID,
+	}
+
+	if config.GlobalCIDR != "" {
+		clusterSpec["global_cidr"] = []interface{}{config.GlobalCIDR}
+	}
+
+	objs := []runtime.Object{
+		NewUnstructured(submarinerGroupVersion, "Cluster", namespace, config.ID, map[string]interface{}{
+			"spec": clusterSpec,
+		}),
+	}
+
+	for _, name := range config.GatewayNodes {
+		objs = append(objs, newEndpoint(config, name), NewUnstructured(submarinerGroupVersion, "Gateway", namespace, name, nil))
+	}
+
+	return objs
+}
+
+func newEndpoint(config *ClusterConfig, hostname string) *unstructured.Unstructured {
+	return NewUnstructured(submarinerGroupVersion, "Endpoint", submarinerNamespace(), endpointName(config, hostname),
+		map[string]interface{}{
+			"spec": endpointSpec(config, hostname),
+		})
+}
+
+func endpointName(config *ClusterConfig, hostname string) string {
+	return fmt.Sprintf("%s-%s", config.ID, hostname)
+}
+
+func endpointSpec(config *ClusterConfig, hostname string) map[string]interface{} {
+	return map[string]interface{}{
+		"cluster_id": config.ID,
+		"hostname":   hostname,
+		"cable_name": fmt.Sprintf("submariner-cable-%s-%s", config.ID, hostname),
+		"backend":    "libreswan",
+	}
+}
+
+func gatewayPodName(hostname string) string {
+	return fmt.Sprintf("%s-%s", framework.SubmarinerGateway, hostname)
+}
+
+// NewUnstructured creates an unstructured object with the given identity, merging in the given top-level fields such as
+// "spec" or "status".
+func NewUnstructured(apiVersion, kind, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+	for k, v := range fields {
+		obj.Object[k] = v
+	}
+
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+
+	return obj
+}
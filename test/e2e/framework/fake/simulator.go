@@ -0,0 +1,222 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/testing"
+)
+
+// Connection statuses reported in a Gateway's status.connections.
+const (
+	ConnectionConnected  = "connected"
+	ConnectionConnecting = "connecting"
+	ConnectionError      = "error"
+)
+
+// Simulator emulates the status writers of the Submariner gateway, Globalnet and Lighthouse controllers on the fake
+// clusters of an Environment. It reacts synchronously to changes made through the fake clients, so the framework's Await
+// helpers observe the same results a real deployment would eventually produce:
+//
+//   - Gateways report their HA status and, for the active gateway, a connection to the active gateway of every other
+//     cluster.
+//   - Changing the gateway label on a node (as DoFailover does for the "kind" provider) adds or removes the gateway on
+//     that node, promoting another gateway if the active one was removed.
+//   - GlobalIngressIPs, GlobalEgressIPs and ClusterGlobalEgressIPs are allocated IPs from the cluster's GlobalCIDR.
+//   - ServiceExports are validated and a ServiceImport is maintained in every cluster. On Globalnet clusters, a
+//     GlobalIngressIP is also created for exported ClusterIP services.
+//
+// Scripted transitions, e.g. Failover, SetConnectionStatus and ReallocateGlobalIPs, allow tests to drive the clusters
+// through the scenarios the e2e specs verify.
+type Simulator struct {
+	mutex    sync.Mutex
+	clusters []*simulatedCluster
+}
+
+type simulatedCluster struct {
+	*Cluster
+	gateways      []string
+	activeGateway string
+	connections   map[string]connectionStatus
+	ipPool        *ipPool
+}
+
+type connectionStatus struct {
+	status  string
+	message string
+}
+
+// NewSimulator starts simulating the Submariner control plane on the given environment's clusters. The initial
+// Gateway statuses are written immediately.
+func NewSimulator(env *Environment) (*Simulator, error) {
+	s := &Simulator{}
+
+	for _, cluster := range env.Clusters {
+		c := &simulatedCluster{
+			Cluster:     cluster,
+			gateways:    append([]string{}, cluster.Config.GatewayNodes...),
+			connections: map[string]connectionStatus{},
+		}
+
+		if len(c.gateways) > 0 {
+			c.activeGateway = c.gateways[0]
+		}
+
+		if cluster.Config.GlobalCIDR != "" {
+			pool, err := newIPPool(cluster.Config.GlobalCIDR)
+			if err != nil {
+				return nil, err
+			}
+
+			c.ipPool = pool
+		}
+
+		s.clusters = append(s.clusters, c)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, c := range s.clusters {
+		s.addGatewayReactors(c)
+		s.addLighthouseReactors(c)
+
+		if c.ipPool != nil {
+			s.addGlobalnetReactors(c)
+
+			if err := s.createClusterGlobalEgressIP(c); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return s, s.syncGateways()
+}
+
+// Failover makes the next gateway of the given cluster the active one and returns its node name.
+func (s *Simulator) Failover(cluster framework.ClusterIndex) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c := s.clusters[cluster]
+	if len(c.gateways) < 2 {
+		return "", fmt.Errorf("cluster %q has %d gateway(s) - at least 2 are needed for a failover", c.Config.ID, len(c.gateways))
+	}
+
+	for i, name := range c.gateways {
+		if name == c.activeGateway {
+			c.activeGateway = c.gateways[(i+1)%len(c.gateways)]
+			break
+		}
+	}
+
+	return c.activeGateway, s.syncGateways()
+}
+
+// SetConnectionStatus sets the status and status message reported by the active gateway of the given cluster for its
+// connection to the remote cluster. Use ConnectionError, for example, to simulate the loss of a connection. The reverse
+// connection isn't affected.
+func (s *Simulator) SetConnectionStatus(cluster, remoteCluster framework.ClusterIndex, status, message string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c := s.clusters[cluster]
+	c.connections[s.clusters[remoteCluster].Config.ID] = connectionStatus{status: status, message: message}
+
+	return s.syncGateways()
+}
+
+// ReallocateGlobalIPs allocates new global IPs to every GlobalIngressIP, GlobalEgressIP and ClusterGlobalEgressIP
+// on the given cluster, as happens when Globalnet is restarted with a different configuration.
+func (s *Simulator) ReallocateGlobalIPs(cluster framework.ClusterIndex) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c := s.clusters[cluster]
+	if c.ipPool == nil {
+		return fmt.Errorf("globalnet isn't enabled on cluster %q", c.Config.ID)
+	}
+
+	for _, owner := range c.ipPool.owners() {
+		ips, err := c.ipPool.reallocate(owner)
+		if err != nil {
+			return err
+		}
+
+		if _, err := setAllocatedIPs(c, owner, ips); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// objectHandler is invoked with the result of an action once it has been applied by the object tracker. It returns the
+// object to be returned to the client.
+type objectHandler func(c *simulatedCluster, action testing.Action, obj runtime.Object) (runtime.Object, error)
+
+// afterReaction returns a reactor that lets the object tracker apply the action and then invokes the given handler, the
+// way a controller reacts to a change after it's persisted. The handler runs with the simulator's lock held.
+func (s *Simulator) afterReaction(c *simulatedCluster, tracker testing.ObjectTracker, handler objectHandler) testing.ReactionFunc {
+	objectReaction := testing.ObjectReaction(tracker)
+
+	return func(action testing.Action) (bool, runtime.Object, error) {
+		if create, ok := action.(testing.CreateAction); ok {
+			setGeneratedName(create.GetObject())
+		}
+
+		handled, obj, err := objectReaction(action)
+		if err != nil || !handled {
+			return handled, obj, err
+		}
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		obj, err = handler(c, action, obj)
+
+		return true, obj, err
+	}
+}
+
+func createOrUpdate(tracker testing.ObjectTracker, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	err := tracker.Update(gvr, obj, obj.GetNamespace())
+	if apierrors.IsNotFound(err) {
+		err = tracker.Create(gvr, obj, obj.GetNamespace())
+	}
+
+	return errors.Wrapf(err, "error writing %s %q", obj.GetKind(), obj.GetName())
+}
+
+func deleteIfExists(tracker testing.ObjectTracker, gvr schema.GroupVersionResource, namespace, name string) error {
+	err := tracker.Delete(gvr, namespace, name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	return errors.Wrapf(err, "error deleting %s %q", gvr.Resource, name)
+}
@@ -0,0 +1,195 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake_test
+
+import (
+	"context"
+	"net/netip"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/framework/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Simulator", func() {
+	var (
+		env       *fake.Environment
+		simulator *fake.Simulator
+		f         *framework.Framework
+	)
+
+	BeforeEach(func() {
+		env = fake.NewEnvironment(
+			fake.ClusterConfig{ID: "east", GatewayNodes: []string{"east-gw1", "east-gw2"}, GlobalCIDR: "242.0.0.0/24"},
+			fake.ClusterConfig{ID: "west", GatewayNodes: []string{"west-gw"}, GlobalCIDR: "242.1.0.0/24"},
+		)
+		DeferCleanup(env.Install())
+
+		var err error
+
+		simulator, err = fake.NewSimulator(env)
+		Expect(err).NotTo(HaveOccurred())
+
+		framework.TestContext.GlobalnetEnabled = true
+		f = framework.NewBareFramework("simulator")
+	})
+
+	remoteHostname := func(gw *unstructured.Unstructured) string {
+		connections, _, err := unstructured.NestedSlice(gw.Object, "status", "connections")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(connections).To(HaveLen(1))
+
+		hostname, _, err := unstructured.NestedString(connections[0].(map[string]interface{}), "endpoint", "hostname")
+		Expect(err).NotTo(HaveOccurred())
+
+		return hostname
+	}
+
+	activeGatewayPodNode := func(ctx context.Context, cluster framework.ClusterIndex) string {
+		pods, err := env.Cluster(cluster).KubeClient.CoreV1().Pods(framework.TestContext.SubmarinerNamespace).List(ctx,
+			metav1.ListOptions{LabelSelector: framework.ActiveGatewayLabel})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pods.Items).To(HaveLen(1))
+
+		return pods.Items[0].Spec.NodeName
+	}
+
+	It("should report the active gateways as fully connected", func(ctx context.Context) {
+		_, err := f.AwaitGatewayFullyConnectedOrError(ctx, framework.ClusterA, "east-gw1")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = f.AwaitGatewayWithStatusOrError(ctx, framework.ClusterA, "east-gw2", "passive")
+		Expect(err).NotTo(HaveOccurred())
+
+		gw, err := f.AwaitGatewayFullyConnectedOrError(ctx, framework.ClusterB, "west-gw")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remoteHostname(gw)).To(Equal("east-gw1"))
+	})
+
+	When("a failover is scripted", func() {
+		It("should make the next gateway active and connect the remote gateway to it", func(ctx context.Context) {
+			active, err := simulator.Failover(framework.ClusterA)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(active).To(Equal("east-gw2"))
+
+			_, err = f.AwaitGatewayFullyConnectedOrError(ctx, framework.ClusterA, "east-gw2")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = f.AwaitGatewayWithStatusOrError(ctx, framework.ClusterA, "east-gw1", "passive")
+			Expect(err).NotTo(HaveOccurred())
+
+			gw, err := f.AwaitGatewayFullyConnectedOrError(ctx, framework.ClusterB, "west-gw")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remoteHostname(gw)).To(Equal("east-gw2"))
+
+			Expect(activeGatewayPodNode(ctx, framework.ClusterA)).To(Equal("east-gw2"))
+		})
+
+		It("should fail if the cluster has a single gateway", func() {
+			_, err := simulator.Failover(framework.ClusterB)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the active gateway's node is unlabeled", func() {
+		It("should remove the gateway and promote another one", func(ctx context.Context) {
+			Expect(f.SetGatewayLabelOnNodeOrError(ctx, framework.ClusterA, "east-gw1", false)).To(Succeed())
+
+			Expect(f.AwaitGatewayRemovedOrError(ctx, framework.ClusterA, "east-gw1")).To(Succeed())
+
+			_, err := f.AwaitGatewayFullyConnectedOrError(ctx, framework.ClusterA, "east-gw2")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(activeGatewayPodNode(ctx, framework.ClusterA)).To(Equal("east-gw2"))
+		})
+	})
+
+	When("a connection loss is scripted", func() {
+		BeforeEach(func() {
+			Expect(simulator.SetConnectionStatus(framework.ClusterA, framework.ClusterB, fake.ConnectionError, "cable down")).
+				To(Succeed())
+		})
+
+		It("should report the connection as not connected on the local gateway only", func(ctx context.Context) {
+			_, err := f.AwaitGatewayFullyConnectedOrError(ctx, framework.ClusterA, "east-gw1")
+			Expect(err).To(MatchError(And(ContainSubstring(`cluster "west" is not connected`), ContainSubstring("cable down"))))
+
+			_, err = f.AwaitGatewayFullyConnectedOrError(ctx, framework.ClusterB, "west-gw")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should report the connection as connected once restored", func(ctx context.Context) {
+			Expect(simulator.SetConnectionStatus(framework.ClusterA, framework.ClusterB, fake.ConnectionConnected, "")).To(Succeed())
+
+			_, err := f.AwaitGatewayFullyConnectedOrError(ctx, framework.ClusterA, "east-gw1")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	When("a global IP reallocation is scripted", func() {
+		It("should allocate new global IPs from the cluster's GlobalCIDR", func(ctx context.Context) {
+			_, err := env.Cluster(framework.ClusterA).DynClient.Resource(fake.GlobalIngressIPGVR).Namespace("default").Create(ctx,
+				fake.NewUnstructured("submariner.io/v1", "GlobalIngressIP", "default", "nginx", nil), metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			oldIngressIP, err := f.AwaitGlobalIngressIPOrError(ctx, framework.ClusterA, "nginx", "default")
+			Expect(err).NotTo(HaveOccurred())
+
+			oldEgressIPs, err := f.AwaitClusterGlobalEgressIPsOrError(ctx, framework.ClusterA, fake.ClusterGlobalEgressIPName)
+			Expect(err).NotTo(HaveOccurred())
+
+			westEgressIPs, err := f.AwaitClusterGlobalEgressIPsOrError(ctx, framework.ClusterB, fake.ClusterGlobalEgressIPName)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(simulator.ReallocateGlobalIPs(framework.ClusterA)).To(Succeed())
+
+			newIngressIP, err := f.AwaitGlobalIngressIPOrError(ctx, framework.ClusterA, "nginx", "default")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newIngressIP).NotTo(Equal(oldIngressIP))
+
+			newEgressIPs, err := f.AwaitClusterGlobalEgressIPsOrError(ctx, framework.ClusterA, fake.ClusterGlobalEgressIPName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newEgressIPs).To(HaveLen(len(oldEgressIPs)))
+			Expect(newEgressIPs).NotTo(ContainElement(BeElementOf(oldEgressIPs)))
+
+			cidr := netip.MustParsePrefix("242.0.0.0/24")
+			for _, ip := range append(newEgressIPs, newIngressIP) {
+				Expect(cidr.Contains(netip.MustParseAddr(ip))).To(BeTrue(), "IP %s isn't in %s", ip, cidr)
+			}
+
+			Expect(f.AwaitClusterGlobalEgressIPsOrError(ctx, framework.ClusterB, fake.ClusterGlobalEgressIPName)).
+				To(Equal(westEgressIPs))
+		})
+
+		It("should fail if Globalnet isn't enabled on the cluster", func() {
+			env = fake.NewEnvironment(fake.ClusterConfig{ID: "north", GatewayNodes: []string{"north-gw"}})
+			DeferCleanup(env.Install())
+
+			var err error
+
+			simulator, err = fake.NewSimulator(env)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(simulator.ReallocateGlobalIPs(framework.ClusterA)).NotTo(Succeed())
+		})
+	})
+})
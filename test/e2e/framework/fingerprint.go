@@ -0,0 +1,296 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8serrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// ClusterFingerprint describes the environment provided by a cluster under test.
+type ClusterFingerprint struct {
+	ID               string `json:"id"`
+	ServerVersion    string `json:"serverVersion"`
+	NumNodes         int    `json:"numNodes"`
+	GlobalnetEnabled bool   `json:"globalnetEnabled"`
+	FIPSEnabled      bool   `json:"fipsEnabled"`
+	Provider         string `json:"provider"`
+	CableDriver      string `json:"cableDriver"`
+	// Images maps the Submariner containers to their images.
+	Images map[string]string `json:"images"`
+}
+
+// EnvironmentFingerprint describes the environment the suite ran against, so failures can be triaged across environments.
+type EnvironmentFingerprint struct {
+	Clusters []ClusterFingerprint `json:"clusters"`
+}
+
+var environmentFingerprint *EnvironmentFingerprint
+
+// FingerprintEnvironment collects the EnvironmentFingerprint from the clusters, to be reported by ReportEnvironment. It's
+// meant to be called once BeforeSuite has run. Collection is best-effort: failures are logged and the corresponding
+// fields are left empty.
+func FingerprintEnvironment(ctx context.Context) *EnvironmentFingerprint {
+	fingerprint, err := FingerprintEnvironmentOrError(ctx)
+	if err != nil {
+		Errorf("Error fingerprinting the environment: %v", err)
+	}
+
+	Logf("Environment:\n%s", fingerprint)
+
+	return fingerprint
+}
+
+// FingerprintEnvironmentOrError is like FingerprintEnvironment but returns the collection errors. The fingerprint is
+// returned, possibly incomplete, even if an error occurred.
+func FingerprintEnvironmentOrError(ctx context.Context) (*EnvironmentFingerprint, error) {
+	var errs []error
+
+	if len(TestContext.NumNodesInCluster) != len(KubeClients) {
+		if err := InitNumClusterNodes(ctx); err != nil {
+			errs = append(errs, errors.Wrap(err, "error counting the cluster nodes"))
+		}
+	}
+
+	fingerprint := &EnvironmentFingerprint{Clusters: make([]ClusterFingerprint, len(KubeClients))}
+
+	for i := range KubeClients {
+		fingerprint.Clusters[i] = fingerprintCluster(ctx, ClusterIndex(i), func(err error) {
+			errs = append(errs, errors.Wrapf(err, "cluster %q", TestContext.ClusterIDs[i]))
+		})
+	}
+
+	environmentFingerprint = fingerprint
+
+	return fingerprint, k8serrors.NewAggregate(errs)
+}
+
+func fingerprintCluster(ctx context.Context, cluster ClusterIndex, onError func(error)) ClusterFingerprint {
+	clusterID := TestContext.ClusterIDs[cluster]
+
+	fingerprint := ClusterFingerprint{
+		ID:               clusterID,
+		NumNodes:         TestContext.NumNodesInCluster[cluster],
+		GlobalnetEnabled: TestContext.GlobalnetEnabled,
+		Images:           map[string]string{},
+	}
+
+	serverVersion, err := KubeClients[cluster].Discovery().ServerVersion()
+	if err != nil {
+		onError(errors.Wrap(err, "error retrieving the server version"))
+	} else {
+		fingerprint.ServerVersion = serverVersion.GitVersion
+	}
+
+	if int(cluster) < len(clusterInfos) {
+		// The cluster was discovered by BeforeSuite.
+		info := &clusterInfos[cluster]
+		fingerprint.GlobalnetEnabled = info.HasRoles(GlobalnetRole)
+		fingerprint.FIPSEnabled = info.FIPSEnabled
+		fingerprint.Provider = info.Provider
+	} else {
+		fingerprint.FIPSEnabled, err = DetectFIPSConfig(ctx, cluster)
+		if err != nil {
+			onError(errors.Wrap(err, "error detecting the FIPS configuration"))
+		}
+
+		fingerprint.Provider, err = detectClusterProvider(ctx, cluster)
+		if err != nil {
+			onError(err)
+		}
+	}
+
+	fingerprint.CableDriver, err = detectCableDriver(ctx, cluster)
+	if err != nil {
+		onError(err)
+	}
+
+	pods, err := KubeClients[cluster].CoreV1().Pods(TestContext.SubmarinerNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		onError(errors.Wrap(err, "error listing the Submariner pods"))
+		return fingerprint
+	}
+
+	images := map[string][]string{}
+
+	for i := range pods.Items {
+		for _, container := range pods.Items[i].Spec.Containers {
+			// Pods of the same component may run different images, e.g. during an upgrade.
+			if !slices.Contains(images[container.Name], container.Image) {
+				images[container.Name] = append(images[container.Name], container.Image)
+			}
+		}
+	}
+
+	for container := range images {
+		sort.Strings(images[container])
+		fingerprint.Images[container] = strings.Join(images[container], ",")
+	}
+
+	return fingerprint
+}
+
+// detectCableDriver returns the cable driver of the cluster's local Endpoint, if any.
+func detectCableDriver(ctx context.Context, cluster ClusterIndex) (string, error) {
+	endpoints, err := DynClients[cluster].Resource(*endpointGVR).Namespace(TestContext.SubmarinerNamespace).List(ctx,
+		metav1.ListOptions{})
+	if err != nil {
+		return "", errors.Wrap(err, "error listing Endpoints")
+	}
+
+	for i := range endpoints.Items {
+		clusterID, _, _ := unstructured.NestedString(endpoints.Items[i].Object, "spec", "cluster_id")
+		if clusterID == TestContext.ClusterIDs[cluster] {
+			backend, _, err := unstructured.NestedString(endpoints.Items[i].Object, "spec", "backend")
+			return backend, errors.Wrap(err, "error reading the Endpoint backend")
+		}
+	}
+
+	return "", nil
+}
+
+// junitProperties flattens the fingerprint into JUnit properties, e.g. "cluster.east.serverVersion".
+func (e *EnvironmentFingerprint) junitProperties() []reporters.JUnitProperty {
+	properties := []reporters.JUnitProperty{}
+
+	add := func(clusterID, name, value string) {
+		properties = append(properties, reporters.JUnitProperty{Name: "cluster." + clusterID + "." + name, Value: value})
+	}
+
+	for i := range e.Clusters {
+		c := &e.Clusters[i]
+
+		add(c.ID, "serverVersion", c.ServerVersion)
+		add(c.ID, "numNodes", strconv.Itoa(c.NumNodes))
+		add(c.ID, "globalnetEnabled", strconv.FormatBool(c.GlobalnetEnabled))
+		add(c.ID, "fipsEnabled", strconv.FormatBool(c.FIPSEnabled))
+		add(c.ID, "provider", c.Provider)
+		add(c.ID, "cableDriver", c.CableDriver)
+
+		containers := make([]string, 0, len(c.Images))
+		for container := range c.Images {
+			containers = append(containers, container)
+		}
+
+		sort.Strings(containers)
+
+		for _, container := range containers {
+			add(c.ID, "image."+container, c.Images[container])
+		}
+	}
+
+	return properties
+}
+
+// ReportEnvironment generates the JUnit report of the suite at the given path, if any, with the EnvironmentFingerprint
+// collected by FingerprintEnvironment added to its properties, and writes the fingerprint to a JSON sidecar file named
+// after the JUnit report, or to "environment.json" in TestContext.ArtifactsDir. It's meant to be called from
+// ReportAfterSuite with the JUnit report removed from the Ginkgo reporter configuration.
+func ReportEnvironment(report types.Report, junitReport string) {
+	if err := ReportEnvironmentOrError(report, junitReport); err != nil {
+		Errorf("Error reporting the environment: %v", err)
+	}
+}
+
+// ReportEnvironmentOrError is like ReportEnvironment but returns an error instead of logging it.
+func ReportEnvironmentOrError(report types.Report, junitReport string) error {
+	fingerprint := environmentFingerprint
+	if fingerprint == nil {
+		// BeforeSuite failed or FingerprintEnvironment wasn't called.
+		fingerprint = &EnvironmentFingerprint{Clusters: []ClusterFingerprint{}}
+	}
+
+	sidecar := ""
+
+	switch {
+	case junitReport != "":
+		if err := generateJUnitReport(report, junitReport, fingerprint); err != nil {
+			return err
+		}
+
+		sidecar = strings.TrimSuffix(junitReport, filepath.Ext(junitReport)) + "-environment.json"
+	case TestContext.ArtifactsDir != "":
+		sidecar = filepath.Join(TestContext.ArtifactsDir, "environment.json")
+	default:
+		return nil
+	}
+
+	data, err := json.MarshalIndent(fingerprint, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error marshaling the environment fingerprint")
+	}
+
+	return writeFile(sidecar, data)
+}
+
+func generateJUnitReport(report types.Report, path string, fingerprint *EnvironmentFingerprint) error {
+	if err := reporters.GenerateJUnitReport(report, path); err != nil {
+		return errors.Wrapf(err, "error generating the JUnit report %q", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "error reading the JUnit report %q", path)
+	}
+
+	suites := &reporters.JUnitTestSuites{}
+	if err := xml.Unmarshal(data, suites); err != nil {
+		return errors.Wrapf(err, "error parsing the JUnit report %q", path)
+	}
+
+	for i := range suites.TestSuites {
+		suites.TestSuites[i].Properties.Properties = append(suites.TestSuites[i].Properties.Properties,
+			fingerprint.junitProperties()...)
+	}
+
+	data, err = xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "error marshaling the JUnit report %q", path)
+	}
+
+	return writeFile(path, append([]byte(xml.Header), data...))
+}
+
+// String formats the fingerprint for logging.
+func (e *EnvironmentFingerprint) String() string {
+	lines := make([]string, len(e.Clusters))
+
+	for i := range e.Clusters {
+		c := &e.Clusters[i]
+		lines[i] = fmt.Sprintf("%s: kubernetes %s, %d node(s), provider %q, cable driver %q, Globalnet %t, FIPS %t",
+			c.ID, c.ServerVersion, c.NumNodes, c.Provider, c.CableDriver, c.GlobalnetEnabled, c.FIPSEnabled)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,211 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/framework/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/testing"
+)
+
+var _ = Describe("FingerprintEnvironment", func() {
+	var (
+		env        *fake.Environment
+		fipsChecks int
+	)
+
+	BeforeEach(func(ctx context.Context) {
+		env = fake.NewEnvironment(
+			fake.ClusterConfig{
+				ID:              "east",
+				GatewayNodes:    []string{"east-gw"},
+				NonGatewayNodes: []string{"east-worker"},
+				GlobalCIDR:      "242.0.0.0/16",
+				Provider:        "kind",
+				KubeObjects: []runtime.Object{
+					submarinerPod("gateway-1", "submariner-gateway", "quay.io/submariner/submariner-gateway:0.20.0"),
+					submarinerPod("gateway-2", "submariner-gateway", "quay.io/submariner/submariner-gateway:0.19.0"),
+					submarinerPod("route-agent", "submariner-routeagent", "quay.io/submariner/submariner-route-agent:0.20.0"),
+				},
+			},
+			fake.ClusterConfig{ID: "west", GatewayNodes: []string{"west-gw"}, Provider: "aws"},
+		)
+		DeferCleanup(env.Install())
+		DeferCleanup(framework.SetClusterInfos, []framework.ClusterInfo(nil))
+		DeferCleanup(framework.SetEnvironmentFingerprint, (*framework.EnvironmentFingerprint)(nil))
+
+		Expect(framework.BeforeSuiteOrError(ctx)).To(Succeed())
+
+		fipsChecks = 0
+
+		for i := range env.Clusters {
+			env.Clusters[i].KubeClient.PrependReactor("get", "configmaps", func(_ testing.Action) (bool, runtime.Object, error) {
+				fipsChecks++
+				return false, nil, nil
+			})
+		}
+	})
+
+	It("should describe each cluster, using the information discovered by BeforeSuite", func(ctx context.Context) {
+		fingerprint, err := framework.FingerprintEnvironmentOrError(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fingerprint.Clusters).To(Equal([]framework.ClusterFingerprint{
+			{
+				ID:               "east",
+				ServerVersion:    "v1.31.0",
+				NumNodes:         2,
+				GlobalnetEnabled: true,
+				Provider:         "kind",
+				CableDriver:      "libreswan",
+				Images: map[string]string{
+					"submariner-gateway":    "quay.io/submariner/submariner-gateway:0.19.0,quay.io/submariner/submariner-gateway:0.20.0",
+					"submariner-routeagent": "quay.io/submariner/submariner-route-agent:0.20.0",
+				},
+			},
+			{
+				ID:            "west",
+				ServerVersion: "v1.31.0",
+				NumNodes:      1,
+				Provider:      "aws",
+				CableDriver:   "libreswan",
+				Images:        map[string]string{},
+			},
+		}))
+
+		Expect(fipsChecks).To(BeZero())
+	})
+
+	It("should detect the clusters' information if BeforeSuite didn't discover it", func(ctx context.Context) {
+		framework.SetClusterInfos(nil)
+
+		fingerprint, err := framework.FingerprintEnvironmentOrError(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fingerprint.Clusters[framework.ClusterB].Provider).To(Equal("aws"))
+		Expect(fipsChecks).To(Equal(2))
+	})
+})
+
+var _ = Describe("ReportEnvironment", func() {
+	var (
+		dir    string
+		report types.Report
+	)
+
+	fingerprint := &framework.EnvironmentFingerprint{Clusters: []framework.ClusterFingerprint{{
+		ID:            "east",
+		ServerVersion: "v1.31.0",
+		NumNodes:      2,
+		FIPSEnabled:   true,
+		Provider:      "kind",
+		CableDriver:   "libreswan",
+		Images:        map[string]string{"submariner-routeagent": "route-agent:0.20", "submariner-gateway": "gateway:0.20"},
+	}}}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		report = types.Report{SuiteDescription: "E2E Suite", SuiteSucceeded: true}
+
+		framework.SetEnvironmentFingerprint(fingerprint)
+		DeferCleanup(framework.SetEnvironmentFingerprint, (*framework.EnvironmentFingerprint)(nil))
+	})
+
+	readSidecar := func(path string) *framework.EnvironmentFingerprint {
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		sidecar := &framework.EnvironmentFingerprint{}
+		Expect(json.Unmarshal(data, sidecar)).To(Succeed())
+
+		return sidecar
+	}
+
+	It("should add the fingerprint to the JUnit report properties and write the sidecar next to it", func() {
+		junitReport := filepath.Join(dir, "junit.xml")
+		Expect(framework.ReportEnvironmentOrError(report, junitReport)).To(Succeed())
+
+		data, err := os.ReadFile(junitReport)
+		Expect(err).NotTo(HaveOccurred())
+
+		suites := &reporters.JUnitTestSuites{}
+		Expect(xml.Unmarshal(data, suites)).To(Succeed())
+		Expect(suites.TestSuites).To(HaveLen(1))
+
+		properties := map[string]string{}
+		for _, property := range suites.TestSuites[0].Properties.Properties {
+			properties[property.Name] = property.Value
+		}
+
+		Expect(properties).To(HaveKeyWithValue("SuiteSucceeded", "true"))
+		Expect(properties).To(HaveKeyWithValue("cluster.east.serverVersion", "v1.31.0"))
+		Expect(properties).To(HaveKeyWithValue("cluster.east.numNodes", "2"))
+		Expect(properties).To(HaveKeyWithValue("cluster.east.globalnetEnabled", "false"))
+		Expect(properties).To(HaveKeyWithValue("cluster.east.fipsEnabled", "true"))
+		Expect(properties).To(HaveKeyWithValue("cluster.east.provider", "kind"))
+		Expect(properties).To(HaveKeyWithValue("cluster.east.cableDriver", "libreswan"))
+		Expect(properties).To(HaveKeyWithValue("cluster.east.image.submariner-gateway", "gateway:0.20"))
+		Expect(properties).To(HaveKeyWithValue("cluster.east.image.submariner-routeagent", "route-agent:0.20"))
+
+		Expect(readSidecar(filepath.Join(dir, "junit-environment.json"))).To(Equal(fingerprint))
+	})
+
+	It("should write the sidecar to the artifacts directory without a JUnit report", func() {
+		framework.TestContext.ArtifactsDir = filepath.Join(dir, "artifacts")
+
+		Expect(framework.ReportEnvironmentOrError(report, "")).To(Succeed())
+		Expect(readSidecar(filepath.Join(dir, "artifacts", "environment.json"))).To(Equal(fingerprint))
+	})
+
+	It("should write an empty fingerprint if the environment wasn't fingerprinted", func() {
+		framework.SetEnvironmentFingerprint(nil)
+		framework.TestContext.ArtifactsDir = dir
+
+		Expect(framework.ReportEnvironmentOrError(report, "")).To(Succeed())
+		Expect(readSidecar(filepath.Join(dir, "environment.json"))).To(Equal(&framework.EnvironmentFingerprint{
+			Clusters: []framework.ClusterFingerprint{},
+		}))
+	})
+
+	It("should write nothing without a JUnit report or artifacts directory", func() {
+		framework.TestContext.ArtifactsDir = ""
+
+		Expect(framework.ReportEnvironmentOrError(report, "")).To(Succeed())
+		Expect(os.ReadDir(dir)).To(BeEmpty())
+	})
+})
+
+func submarinerPod(name, container, image string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "submariner"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: container, Image: image}}},
+	}
+}
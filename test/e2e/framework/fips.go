@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -33,8 +34,8 @@ const (
 	fipsConfigMapName = "cluster-config-v1"
 )
 
-func DetectFIPSConfig(cluster ClusterIndex) (bool, error) {
-	configMap, err := KubeClients[cluster].CoreV1().ConfigMaps(fipsNamespace).Get(context.TODO(), fipsConfigMapName, metav1.GetOptions{})
+func DetectFIPSConfig(ctx context.Context, cluster ClusterIndex) (bool, error) {
+	configMap, err := KubeClients[cluster].CoreV1().ConfigMaps(fipsNamespace).Get(ctx, fipsConfigMapName, metav1.GetOptions{})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			return false, nil
@@ -46,9 +47,9 @@ func DetectFIPSConfig(cluster ClusterIndex) (bool, error) {
 	return strings.Contains(configMap.Data["install-config"], "fips: true"), nil
 }
 
-func (f *Framework) FindFIPSEnabledCluster() ClusterIndex {
+func (f *Framework) FindFIPSEnabledCluster(ctx context.Context) ClusterIndex {
 	for idx := range TestContext.ClusterIDs {
-		fipsEnabled, err := DetectFIPSConfig(ClusterIndex(idx))
+		fipsEnabled, err := DetectFIPSConfig(ctx, ClusterIndex(idx))
 		Expect(err).NotTo(HaveOccurred())
 
 		if fipsEnabled {
@@ -64,10 +65,16 @@ func verifyFIPSOutput(data string) bool {
 		strings.Contains(strings.ToLower(data), "fips mode enabled for pluto daemon")
 }
 
-func (f *Framework) TestGatewayNodeFIPSMode(cluster ClusterIndex, gwPod string) {
+func (f *Framework) TestGatewayNodeFIPSMode(ctx context.Context, cluster ClusterIndex, gwPod string) {
+	if err := f.TestGatewayNodeFIPSModeOrError(ctx, cluster, gwPod); err != nil {
+		Fail(err.Error())
+	}
+}
+
+// TestGatewayNodeFIPSModeOrError is like TestGatewayNodeFIPSMode but returns an error instead of failing.
+func (f *Framework) TestGatewayNodeFIPSModeOrError(ctx context.Context, cluster ClusterIndex, gwPod string) error {
 	By(fmt.Sprintf("Verify FIPS mode is enabled on gateway pod %q", gwPod))
 
-	ctx := context.TODO()
 	cmd := []string{"ipsec", "pluto", "--selftest"}
 
 	stdOut, stdErr, err := f.ExecWithOptions(ctx, &ExecOptions{
@@ -78,10 +85,12 @@ func (f *Framework) TestGatewayNodeFIPSMode(cluster ClusterIndex, gwPod string)
 		CaptureStdout: true,
 		CaptureStderr: true,
 	}, cluster)
-	Expect(err).To(Succeed())
+	if err != nil {
+		return errors.Wrapf(err, "error running %q on gateway pod %q", cmd, gwPod)
+	}
 
 	if stdOut == "" && stdErr == "" {
-		Fail(fmt.Sprintf("No output received from command %q", cmd))
+		return fmt.Errorf("no output received from command %q", cmd)
 	}
 
 	// The output of the "ipsec pluto --selftest" command could be written to stdout or stderr.
@@ -91,8 +100,8 @@ func (f *Framework) TestGatewayNodeFIPSMode(cluster ClusterIndex, gwPod string)
 
 	if fipsStdOutResult || fipsStdErrResult {
 		By(fmt.Sprintf("FIPS mode is enabled on gateway pod %q", gwPod))
-		return
+		return nil
 	}
 
-	Fail(fmt.Sprintf("FIPS mode is not enabled on gateway pod %q", gwPod))
+	return fmt.Errorf("FIPS mode is not enabled on gateway pod %q", gwPod)
 }
@@ -22,6 +22,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -50,6 +51,12 @@ import (
 const (
 	// Polling interval while trying to create objects.
 	PollInterval = 100 * time.Millisecond
+
+	// Default timeout when waiting for namespaces to be deleted.
+	DefaultNamespaceDeletionTimeout = 5 * time.Minute
+
+	// Polling interval while waiting for namespaces to be deleted.
+	namespaceDeletionPollInterval = 2 * time.Second
 )
 
 type ClusterIndex int
@@ -67,6 +74,9 @@ const (
 	ActiveGatewayLabel = "gateway.submariner.io/status=active"
 	TestNonGWNodeLabel = "test.submariner.io/non-gateway-node=true"
 	BasicTestLabel     = "basic"
+
+	// Label set on the namespaces created by the framework, with the framework's base name as value.
+	e2eFrameworkLabel = "e2e-framework"
 )
 
 type PatchFunc func(pt types.PatchType, payload []byte) error
@@ -108,35 +118,54 @@ type Framework struct {
 	// we install a Cleanup action before each test and clear it after.  If we
 	// should abort, the AfterSuite hook should run all Cleanup actions.
 	cleanupHandle CleanupActionHandle
+
+	eventWatcher *eventWatcher
+	events       []CapturedEvent
+	eventErrors  []string
+	// eventWriter is where the captured events are logged as they occur.
+	eventWriter io.Writer
+
+	// dnsClientPods caches the pods used to run DNS queries, per cluster, until the end of the test.
+	dnsClientPods map[ClusterIndex]*NetworkPod
 }
 
 var (
-	beforeSuiteFuncs []func()
+	beforeSuiteFuncs []func(context.Context)
 
 	RestConfigs []*rest.Config
-	KubeClients []*kubeclientset.Clientset
+	KubeClients []kubeclientset.Interface
 	DynClients  []dynamic.Interface
 
 	podSecurityContext *corev1.SecurityContext
 )
 
-// NewBareFramework creates a test framework, without ginkgo dependencies.
+// NewBareFramework creates a test framework, without ginkgo dependencies. Callers that don't install a Gomega fail handler
+// should use the OrError variants of the framework operations, which return errors instead of failing.
 func NewBareFramework(baseName string) *Framework {
 	return &Framework{
-		BaseName:            baseName,
-		namespacesToDelete:  map[string]bool{},
-		gatewayNodesToReset: map[int][]string{},
+		BaseName:                 baseName,
+		namespacesToDelete:       map[string]bool{},
+		gatewayNodesToReset:      map[int][]string{},
+		NamespaceDeletionTimeout: DefaultNamespaceDeletionTimeout,
+		dnsClientPods:            map[ClusterIndex]*NetworkPod{},
+		eventWriter:              os.Stdout,
 	}
 }
 
-func AddBeforeSuite(beforeSuite func()) {
+// AddBeforeSuite registers a function to run at the end of BeforeSuite. With parallel Ginkgo nodes, BeforeSuite only
+// runs on the first node and only the state in SuiteState is passed to the others by RestoreSuiteState, so any state
+// set by the function isn't available to the specs running on the other nodes. The function should therefore be
+// limited to acting on the clusters, or store its state where every node can retrieve it, e.g. in the clusters.
+func AddBeforeSuite(beforeSuite func(context.Context)) {
 	beforeSuiteFuncs = append(beforeSuiteFuncs, beforeSuite)
 }
 
 var (
-	By                func(string, ...func())
-	Fail              func(string, ...int)
-	userAgentFunction func() string
+	By                 func(string, ...func())
+	Fail               func(string, ...int)
+	userAgentFunction  func() string
+	specFailedFunction func() bool
+	specNameFunction   func() string
 )
 
 func SetStatusFunction(by func(string, ...func())) {
@@ -147,6 +176,17 @@ func SetFailFunction(fail func(string, ...int)) {
 	Fail = fail
 }
 
+// SetSpecFailedFunction sets the function used to determine whether the current spec failed, e.g. to preserve its
+// namespaces according to TestContext.DeleteNamespaceOnFailure.
+func SetSpecFailedFunction(specFailed func() bool) {
+	specFailedFunction = specFailed
+}
+
+// SetSpecNameFunction sets the function returning the name of the current spec, e.g. to name its artifacts directory.
+func SetSpecNameFunction(specName func() string) {
+	specNameFunction = specName
+}
+
 func SetUserAgentFunction(uaf func() string) {
 	userAgentFunction = uaf
 }
@@ -165,33 +205,61 @@ func init() {
 	userAgentFunction = func() string {
 		return "shipyard-framework-agent"
 	}
+	specFailedFunction = func() bool {
+		return false
+	}
+	specNameFunction = func() string {
+		return "spec"
+	}
 }
 
-func BeforeSuite() {
-	By("Creating kubernetes clients")
+// BeforeSuite creates the kubernetes clients and initializes the TestContext from the clusters.
+func BeforeSuite(ctx context.Context) {
+	Expect(BeforeSuiteOrError(ctx)).To(Succeed())
+}
 
-	if len(RestConfigs) == 0 {
-		if TestContext.KubeConfig != "" {
-			Expect(TestContext.KubeConfigs).To(BeEmpty(),
-				"Either KubeConfig or KubeConfigs must be specified but not both")
+// BeforeSuiteOrError is like BeforeSuite but returns an error instead of failing via Gomega. If KubeClients and DynClients
+// have already been populated, for example with fake clients, they're used as is instead of being created from RestConfigs.
+func BeforeSuiteOrError(ctx context.Context) error {
+	if len(KubeClients) == 0 || len(KubeClients) != len(DynClients) {
+		if err := initClients(); err != nil {
+			return err
+		}
+	}
 
-			for _, ctx := range TestContext.KubeContexts {
-				RestConfigs = append(RestConfigs, createRestConfig(TestContext.KubeConfig, ctx))
-			}
+	if err := fetchClusterIDs(ctx); err != nil {
+		return err
+	}
 
-			// if cluster IDs are not provided we assume that cluster-id == context
-			if len(TestContext.ClusterIDs) == 0 {
-				TestContext.ClusterIDs = TestContext.KubeContexts
-			}
-		} else if len(TestContext.KubeConfigs) > 0 {
-			Expect(TestContext.KubeConfigs).To(HaveLen(len(TestContext.ClusterIDs)),
-				"One ClusterID must be provided for each item in the KubeConfigs")
+	if err := initClusterInfos(ctx); err != nil {
+		return err
+	}
 
-			for _, kubeConfig := range TestContext.KubeConfigs {
-				RestConfigs = append(RestConfigs, createRestConfig(kubeConfig, ""))
-			}
-		} else {
-			Fail("One of KubeConfig or KubeConfigs must be specified")
+	if _, err := ReapStaleNamespacesOrError(ctx); err != nil {
+		return err
+	}
+
+	if err := addToScheme(); err != nil {
+		return err
+	}
+
+	for _, beforeSuite := range beforeSuiteFuncs {
+		beforeSuite(ctx)
+	}
+
+	return initPodSecurityContext()
+}
+
+func addToScheme() error {
+	return errors.Wrap(mcsv1a1.AddToScheme(scheme.Scheme), "error adding the MCS API to the scheme")
+}
+
+func initClients() error {
+	By("Creating kubernetes clients")
+
+	if len(RestConfigs) == 0 {
+		if err := initRestConfigs(); err != nil {
+			return err
 		}
 	}
 
@@ -199,24 +267,76 @@ func BeforeSuite() {
 	DynClients = nil
 
 	for _, restConfig := range RestConfigs {
-		KubeClients = append(KubeClients, createKubernetesClient(restConfig))
-		DynClients = append(DynClients, createDynamicClient(restConfig))
+		kubeClient, err := createKubernetesClient(restConfig)
+		if err != nil {
+			return err
+		}
+
+		dynClient, err := createDynamicClient(restConfig)
+		if err != nil {
+			return err
+		}
+
+		KubeClients = append(KubeClients, kubeClient)
+		DynClients = append(DynClients, dynClient)
 	}
 
-	fetchClusterIDs()
+	return nil
+}
 
-	err := mcsv1a1.AddToScheme(scheme.Scheme)
-	Expect(err).NotTo(HaveOccurred())
+func initRestConfigs() error {
+	switch {
+	case TestContext.KubeConfig != "":
+		if len(TestContext.KubeConfigs) > 0 {
+			return errors.New("either KubeConfig or KubeConfigs must be specified but not both")
+		}
 
-	for _, beforeSuite := range beforeSuiteFuncs {
-		beforeSuite()
+		for _, kubeContext := range TestContext.KubeContexts {
+			restConfig, err := createRestConfig(TestContext.KubeConfig, kubeContext)
+			if err != nil {
+				return err
+			}
+
+			RestConfigs = append(RestConfigs, restConfig)
+		}
+
+		// if cluster IDs are not provided we assume that cluster-id == context
+		if len(TestContext.ClusterIDs) == 0 {
+			TestContext.ClusterIDs = TestContext.KubeContexts
+		}
+	case len(TestContext.KubeConfigs) > 0:
+		if len(TestContext.KubeConfigs) != len(TestContext.ClusterIDs) {
+			return errors.New("one ClusterID must be provided for each item in the KubeConfigs")
+		}
+
+		for _, kubeConfig := range TestContext.KubeConfigs {
+			restConfig, err := createRestConfig(kubeConfig, "")
+			if err != nil {
+				return err
+			}
+
+			RestConfigs = append(RestConfigs, restConfig)
+		}
+	default:
+		return errors.New("one of KubeConfig or KubeConfigs must be specified")
 	}
 
-	initPodSecurityContext()
+	return nil
 }
 
-func initPodSecurityContext() {
-	podSecurityContext = &corev1.SecurityContext{
+func initPodSecurityContext() error {
+	useSeccompProfile, err := supportsSeccompProfile()
+	if err != nil {
+		return err
+	}
+
+	podSecurityContext = newPodSecurityContext(useSeccompProfile)
+
+	return nil
+}
+
+func newPodSecurityContext(useSeccompProfile bool) *corev1.SecurityContext {
+	securityContext := &corev1.SecurityContext{
 		AllowPrivilegeEscalation: ptr.To(false),
 		Capabilities: &corev1.Capabilities{
 			Drop: []corev1.Capability{
@@ -227,68 +347,103 @@ func initPodSecurityContext() {
 		RunAsUser:    ptr.To(int64(10000)), // We need to set some user ID other than 0.
 	}
 
+	if useSeccompProfile {
+		securityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+
+	return securityContext
+}
+
+func supportsSeccompProfile() (bool, error) {
 	serverVersion, err := KubeClients[0].Discovery().ServerVersion()
-	Expect(err).To(Succeed())
+	if err != nil {
+		return false, errors.Wrap(err, "error retrieving the server version")
+	}
 
 	major, err := strconv.Atoi(serverVersion.Major)
-	Expect(err).To(Succeed())
+	if err != nil {
+		return false, errors.Wrapf(err, "error parsing server major version %q", serverVersion.Major)
+	}
 
-	var minor int
-	if strings.HasSuffix(serverVersion.Minor, "+") {
-		minor, err = strconv.Atoi(serverVersion.Minor[0 : len(serverVersion.Minor)-1])
-	} else {
-		minor, err = strconv.Atoi(serverVersion.Minor)
+	minor, err := strconv.Atoi(strings.TrimSuffix(serverVersion.Minor, "+"))
+	if err != nil {
+		return false, errors.Wrapf(err, "error parsing server minor version %q", serverVersion.Minor)
 	}
 
-	Expect(err).To(Succeed())
+	return major > 1 || minor >= 24, nil
+}
 
-	if major > 1 || minor >= 24 {
-		podSecurityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
-	}
+func (f *Framework) BeforeEach(ctx context.Context) {
+	Expect(f.BeforeEachOrError(ctx)).To(Succeed())
 }
 
-func (f *Framework) BeforeEach() {
+// BeforeEachOrError is like BeforeEach but returns an error instead of failing via Gomega.
+func (f *Framework) BeforeEachOrError(ctx context.Context) error {
 	// workaround for a bug in ginkgo.
 	// https://github.com/onsi/ginkgo/issues/222
-	f.cleanupHandle = AddCleanupAction(f.AfterEach)
+	f.cleanupHandle = AddScopedCleanupAction(CleanupAction{
+		Name:  fmt.Sprintf("clean up after %q", f.BaseName),
+		Scope: SuiteScope,
+		// Waiting for the namespaces' deletion may take up to the NamespaceDeletionTimeout on each cluster.
+		Timeout: DefaultCleanupTimeout + time.Duration(len(KubeClients))*f.namespaceDeletionTimeout(),
+		Run: func(ctx context.Context) error {
+			f.AfterEach(ctx)
+			return nil
+		},
+	})
 
-	if !f.SkipNamespaceCreation {
-		By(fmt.Sprintf("Creating namespace objects with basename %q", f.BaseName))
+	if f.SkipNamespaceCreation {
+		f.UniqueName = string(uuid.NewUUID())
+		f.startWatchingEvents(ctx)
 
-		namespaceLabels := map[string]string{
-			"e2e-framework":                                  f.BaseName,
-			"pod-security.kubernetes.io/enforce":             "privileged",
-			"security.openshift.io/scc.podSecurityLabelSync": "false",
-		}
+		return nil
+	}
+
+	By(fmt.Sprintf("Creating namespace objects with basename %q", f.BaseName))
 
-		for idx, clientSet := range KubeClients {
-			if ClusterIndex(idx) == ClusterA {
-				// On the first cluster we let k8s generate a name for the namespace
-				namespace := generateNamespace(clientSet, f.BaseName, namespaceLabels)
-				f.Namespace = namespace.GetName()
-				f.UniqueName = namespace.GetName()
-				f.AddNamespacesToDelete(namespace)
-				By(fmt.Sprintf("Generated namespace %q in cluster %q to execute the tests in", f.Namespace, TestContext.ClusterIDs[idx]))
-			} else {
-				// On the other clusters we use the same name to make tracing easier
-				By(fmt.Sprintf("Creating namespace %q in cluster %q", f.Namespace, TestContext.ClusterIDs[idx]))
-				f.CreateNamespace(clientSet, f.Namespace, namespaceLabels)
+	namespaceLabels := map[string]string{
+		e2eFrameworkLabel:                                f.BaseName,
+		"pod-security.kubernetes.io/enforce":             "privileged",
+		"security.openshift.io/scc.podSecurityLabelSync": "false",
+	}
+
+	for idx, clientSet := range KubeClients {
+		if ClusterIndex(idx) == ClusterA {
+			// On the first cluster we let k8s generate a name for the namespace
+			namespace, err := generateNamespace(ctx, clientSet, f.BaseName, namespaceLabels)
+			if err != nil {
+				return err
+			}
+
+			f.Namespace = namespace.GetName()
+			f.UniqueName = namespace.GetName()
+			f.AddNamespacesToDelete(namespace)
+			By(fmt.Sprintf("Generated namespace %q in cluster %q to execute the tests in", f.Namespace, TestContext.ClusterIDs[idx]))
+		} else {
+			// On the other clusters we use the same name to make tracing easier
+			By(fmt.Sprintf("Creating namespace %q in cluster %q", f.Namespace, TestContext.ClusterIDs[idx]))
+
+			if _, err := f.CreateNamespaceOrError(ctx, clientSet, f.Namespace, namespaceLabels); err != nil {
+				return err
 			}
 		}
-	} else {
-		f.UniqueName = string(uuid.NewUUID())
 	}
+
+	f.startWatchingEvents(ctx)
+
+	return nil
 }
 
-func DetectGlobalnet() {
-	clusters := DynClients[ClusterA].Resource(schema.GroupVersionResource{
-		Group:    "submariner.io",
-		Version:  "v1",
-		Resource: "clusters",
-	}).Namespace(TestContext.SubmarinerNamespace)
+func DetectGlobalnet(ctx context.Context) {
+	Expect(DetectGlobalnetOrError(ctx)).To(Succeed())
+}
 
-	AwaitUntil("find Clusters to detect if Globalnet is enabled", func() (interface{}, error) {
-		return clusters.List(context.TODO(), metav1.ListOptions{})
+// DetectGlobalnetOrError is like DetectGlobalnet but returns an error instead of failing via Gomega.
+func DetectGlobalnetOrError(ctx context.Context) error {
+	clusters := DynClients[ClusterA].Resource(*clusterGVR).Namespace(TestContext.SubmarinerNamespace)
+
+	_, err := awaitUntilOrError(ctx, "find Clusters to detect if Globalnet is enabled", func() (interface{}, error) {
+		return clusters.List(ctx, metav1.ListOptions{})
 	}, func(result interface{}) (bool, string, error) {
 		clusterList := result.(*unstructured.UnstructuredList)
 		if clusterList == nil || len(clusterList.Items) == 0 {
@@ -308,13 +463,15 @@ func DetectGlobalnet() {
 
 		return true, "", nil
 	})
+
+	return err
 }
 
-func InitNumClusterNodes() error {
+func InitNumClusterNodes(ctx context.Context) error {
 	TestContext.NumNodesInCluster = map[ClusterIndex]int{}
 
 	for i := range KubeClients {
-		nodes, err := KubeClients[i].CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+		nodes, err := KubeClients[i].CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 		if err != nil {
 			return err
 		}
@@ -325,28 +482,38 @@ func InitNumClusterNodes() error {
 	return nil
 }
 
-func fetchClusterIDs() {
+func fetchClusterIDs(ctx context.Context) error {
 	for i := range KubeClients {
-		gatewayNodes := FindGatewayNodes(ClusterIndex(i))
+		gatewayNodes, err := listGatewayNodes(ctx, ClusterIndex(i))
+		if err != nil {
+			return err
+		}
+
 		if len(gatewayNodes) == 0 {
 			continue
 		}
 
 		name := "submariner-gateway"
-		daemonSet := AwaitUntil(fmt.Sprintf("find %s DaemonSet for %q", name, TestContext.ClusterIDs[i]), func() (interface{}, error) {
-			ds, err := KubeClients[i].AppsV1().DaemonSets(TestContext.SubmarinerNamespace).Get(context.TODO(), name, metav1.GetOptions{})
-			if apierrors.IsNotFound(err) {
-				return nil, nil //nolint:nilnil // We want to repeat but let the checker known that nothing was found.
-			}
+		result, err := awaitUntilOrError(ctx, fmt.Sprintf("find %s DaemonSet for %q", name, TestContext.ClusterIDs[i]),
+			func() (interface{}, error) {
+				ds, err := KubeClients[i].AppsV1().DaemonSets(TestContext.SubmarinerNamespace).Get(ctx, name, metav1.GetOptions{})
+				if apierrors.IsNotFound(err) {
+					return nil, nil //nolint:nilnil // We want to repeat but let the checker known that nothing was found.
+				}
 
-			return ds, err
-		}, func(result interface{}) (bool, string, error) {
-			if result == nil {
-				return false, "No DaemonSet found", nil
-			}
+				return ds, err
+			}, func(result interface{}) (bool, string, error) {
+				if result == nil {
+					return false, "No DaemonSet found", nil
+				}
 
-			return true, "", nil
-		}).(*appsv1.DaemonSet)
+				return true, "", nil
+			})
+		if err != nil {
+			return err
+		}
+
+		daemonSet := result.(*appsv1.DaemonSet)
 
 		const envVarName = "SUBMARINER_CLUSTERID"
 		found := false
@@ -364,14 +531,20 @@ func fetchClusterIDs() {
 			}
 		}
 
-		Expect(found).To(BeTrue(), "Expected %q env var not found in DaemonSet %#v for kube context %q",
-			envVarName, daemonSet, TestContext.ClusterIDs[i])
+		if !found {
+			return fmt.Errorf("expected %q env var not found in DaemonSet %q for kube context %q",
+				envVarName, daemonSet.Name, TestContext.ClusterIDs[i])
+		}
 	}
+
+	return nil
 }
 
-func createKubernetesClient(restConfig *rest.Config) *kubeclientset.Clientset {
+func createKubernetesClient(restConfig *rest.Config) (*kubeclientset.Clientset, error) {
 	clientSet, err := kubeclientset.NewForConfig(restConfig)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the kubernetes client")
+	}
 
 	// create scales getter, set GroupVersion and NegotiatedSerializer to default values
 	// as they are required when creating a REST client.
@@ -383,24 +556,19 @@ func createKubernetesClient(restConfig *rest.Config) *kubeclientset.Clientset {
 		restConfig.NegotiatedSerializer = scheme.Codecs
 	}
 
-	return clientSet
+	return clientSet, nil
 }
 
-func createDynamicClient(restConfig *rest.Config) dynamic.Interface {
+func createDynamicClient(restConfig *rest.Config) (dynamic.Interface, error) {
 	clientSet, err := dynamic.NewForConfig(restConfig)
-	Expect(err).NotTo(HaveOccurred())
 
-	return clientSet
+	return clientSet, errors.Wrap(err, "error creating the dynamic client")
 }
 
-func createRestConfig(kubeConfig, kubeContext string) *rest.Config {
+func createRestConfig(kubeConfig, kubeContext string) (*rest.Config, error) {
 	restConfig, err := loadConfig(kubeConfig, kubeContext)
 	if err != nil {
-		Errorf("Unable to load kubeconfig file %s for context %s, this is a non-recoverable error",
-			TestContext.KubeConfig, kubeContext)
-		Errorf("loadConfig err: %s", err.Error())
-		fmt.Printf("Non-recoverable error: %s", err.Error())
-		os.Exit(1)
+		return nil, errors.Wrapf(err, "unable to load kubeconfig file %s for context %s", kubeConfig, kubeContext)
 	}
 
 	restConfig.UserAgent = userAgentFunction()
@@ -412,25 +580,41 @@ func createRestConfig(kubeConfig, kubeContext string) *rest.Config {
 		restConfig.GroupVersion = TestContext.GroupVersion
 	}
 
-	return restConfig
+	return restConfig, nil
+}
+
+func deleteNamespace(ctx context.Context, client kubeclientset.Interface, namespaceName string) error {
+	return client.CoreV1().Namespaces().Delete(ctx, namespaceName, metav1.DeleteOptions{})
 }
 
-func deleteNamespace(client kubeclientset.Interface, namespaceName string) error {
-	return client.CoreV1().Namespaces().Delete(context.TODO(), namespaceName, metav1.DeleteOptions{})
+// AfterEach stops capturing events, collects artifacts if the spec failed and deletes the namespaces as per the
+// TestContext's namespace deletion policies.
+func (f *Framework) AfterEach(ctx context.Context) {
+	// if we had errors deleting, report them now.
+	if err := f.AfterEachOrError(ctx); err != nil {
+		Errorf(err.Error())
+	}
 }
 
-// AfterEach deletes the namespace, after reading its events.
-func (f *Framework) AfterEach() {
+// AfterEachOrError is like AfterEach but returns the namespace deletion errors instead of logging them.
+func (f *Framework) AfterEachOrError(ctx context.Context) error {
 	f.stopped = true
 	RemoveCleanupAction(f.cleanupHandle)
 
+	f.stopWatchingEvents()
+	f.collectArtifactsOnFailure(ctx)
+
 	var nsDeletionErrors []error
 
 	// Whether to delete namespace is determined by 3 factors: delete-namespace flag, delete-namespace-on-failure flag and the test result
 	// if delete-namespace set to false, namespace will always be preserved.
 	// if delete-namespace is true and delete-namespace-on-failure is false, namespace will be preserved if test failed.
+	deleteNamespaces := TestContext.DeleteNamespace && (TestContext.DeleteNamespaceOnFailure || !specFailedFunction())
+
 	for ns := range f.namespacesToDelete {
-		if err := f.deleteNamespaceFromAllClusters(ns); err != nil {
+		if !deleteNamespaces {
+			By(fmt.Sprintf("Preserving namespace %q on all clusters", ns))
+		} else if err := f.deleteNamespaceFromAllClusters(ctx, ns); err != nil {
 			nsDeletionErrors = append(nsDeletionErrors, err)
 		}
 
@@ -439,20 +623,18 @@ func (f *Framework) AfterEach() {
 
 	// Paranoia-- prevent reuse!
 	f.Namespace = ""
+	clear(f.dnsClientPods)
 
-	// if we had errors deleting, report them now.
-	if len(nsDeletionErrors) != 0 {
-		Errorf(k8serrors.NewAggregate(nsDeletionErrors).Error())
-	}
+	return k8serrors.NewAggregate(nsDeletionErrors)
 }
 
-func (f *Framework) deleteNamespaceFromAllClusters(ns string) error {
+func (f *Framework) deleteNamespaceFromAllClusters(ctx context.Context, ns string) error {
 	var errs []error
 
 	for i, clientSet := range KubeClients {
 		By(fmt.Sprintf("Deleting namespace %q on cluster %q", ns, TestContext.ClusterIDs[i]))
 
-		if err := deleteNamespace(clientSet, ns); err != nil {
+		if err := deleteNamespace(ctx, clientSet, ns); err != nil {
 			switch {
 			case apierrors.IsNotFound(err):
 				Logf("Namespace %q was already deleted", ns)
@@ -464,19 +646,67 @@ func (f *Framework) deleteNamespaceFromAllClusters(ns string) error {
 		}
 	}
 
+	if len(errs) > 0 || !TestContext.WaitForNamespaceDeletion {
+		return k8serrors.NewAggregate(errs)
+	}
+
+	for i, clientSet := range KubeClients {
+		if err := f.awaitNamespaceDeleted(ctx, clientSet, ns); err != nil {
+			errs = append(errs, errors.WithMessagef(err, "Namespace %q on cluster %q was not deleted", ns, TestContext.ClusterIDs[i]))
+		}
+	}
+
 	return k8serrors.NewAggregate(errs)
 }
 
+// awaitNamespaceDeleted waits until the given namespace no longer exists, within the NamespaceDeletionTimeout.
+func (f *Framework) awaitNamespaceDeleted(ctx context.Context, client kubeclientset.Interface, namespaceName string) error {
+	timeout := f.namespaceDeletionTimeout()
+
+	err := wait.PollUntilContextTimeout(ctx, namespaceDeletionPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		_, err := client.CoreV1().Namespaces().Get(ctx, namespaceName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, err
+	})
+
+	return errors.Wrapf(err, "error waiting for the deletion of namespace %q", namespaceName)
+}
+
+func (f *Framework) namespaceDeletionTimeout() time.Duration {
+	if f.NamespaceDeletionTimeout == 0 {
+		return DefaultNamespaceDeletionTimeout
+	}
+
+	return f.NamespaceDeletionTimeout
+}
+
 // CreateNamespace creates a namespace for e2e testing.
-func (f *Framework) CreateNamespace(clientSet *kubeclientset.Clientset,
+func (f *Framework) CreateNamespace(ctx context.Context, clientSet kubeclientset.Interface,
 	baseName string, labels map[string]string,
 ) *corev1.Namespace {
-	ns := createTestNamespace(clientSet, baseName, labels)
-	f.AddNamespacesToDelete(ns)
+	ns, err := f.CreateNamespaceOrError(ctx, clientSet, baseName, labels)
+	Expect(err).NotTo(HaveOccurred())
 
 	return ns
 }
 
+// CreateNamespaceOrError is like CreateNamespace but returns an error instead of failing via Gomega.
+func (f *Framework) CreateNamespaceOrError(ctx context.Context, clientSet kubeclientset.Interface,
+	baseName string, labels map[string]string,
+) (*corev1.Namespace, error) {
+	ns, err := createTestNamespace(ctx, clientSet, baseName, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	f.AddNamespacesToDelete(ns)
+
+	return ns, nil
+}
+
 func (f *Framework) AddNamespacesToDelete(namespaces ...*corev1.Namespace) {
 	for _, ns := range namespaces {
 		if ns == nil {
@@ -487,7 +717,8 @@ func (f *Framework) AddNamespacesToDelete(namespaces ...*corev1.Namespace) {
 	}
 }
 
-func generateNamespace(client kubeclientset.Interface, baseName string, labels map[string]string) *corev1.Namespace {
+func generateNamespace(ctx context.Context, client kubeclientset.Interface, baseName string, labels map[string]string,
+) (*corev1.Namespace, error) {
 	namespaceObj := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: fmt.Sprintf("e2e-tests-%v-", baseName),
@@ -495,18 +726,18 @@ func generateNamespace(client kubeclientset.Interface, baseName string, labels m
 		},
 	}
 
-	namespace, err := client.CoreV1().Namespaces().Create(context.TODO(), namespaceObj, metav1.CreateOptions{})
-	Expect(err).NotTo(HaveOccurred(), "Error generating namespace %v", namespaceObj)
+	namespace, err := client.CoreV1().Namespaces().Create(ctx, namespaceObj, metav1.CreateOptions{})
 
-	return namespace
+	return namespace, errors.Wrapf(err, "error generating namespace with base name %q", baseName)
 }
 
-func createTestNamespace(client kubeclientset.Interface, name string, labels map[string]string) *corev1.Namespace {
-	namespace := createNamespace(client, name, labels)
-	return namespace
+func createTestNamespace(ctx context.Context, client kubeclientset.Interface, name string, labels map[string]string,
+) (*corev1.Namespace, error) {
+	return createNamespace(ctx, client, name, labels)
 }
 
-func createNamespace(client kubeclientset.Interface, name string, labels map[string]string) *corev1.Namespace {
+func createNamespace(ctx context.Context, client kubeclientset.Interface, name string, labels map[string]string,
+) (*corev1.Namespace, error) {
 	namespaceObj := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   name,
@@ -514,41 +745,48 @@ func createNamespace(client kubeclientset.Interface, name string, labels map[str
 		},
 	}
 
-	namespace, err := client.CoreV1().Namespaces().Create(context.TODO(), namespaceObj, metav1.CreateOptions{})
-	Expect(err).NotTo(HaveOccurred(), "Error creating namespace %v", namespaceObj)
+	namespace, err := client.CoreV1().Namespaces().Create(ctx, namespaceObj, metav1.CreateOptions{})
 
-	return namespace
+	return namespace, errors.Wrapf(err, "error creating namespace %q", name)
 }
 
 // PatchString performs a REST patch operation for the given path and string value.
-func PatchString(path, value string, patchFunc PatchFunc) {
+func PatchString(ctx context.Context, path, value string, patchFunc PatchFunc) {
+	Expect(patchString(ctx, path, value, patchFunc)).To(Succeed())
+}
+
+func patchString(ctx context.Context, path, value string, patchFunc PatchFunc) error {
 	payload := []PatchStringValue{{
 		Op:    "add",
 		Path:  path,
 		Value: value,
 	}}
 
-	doPatchOperation(payload, patchFunc)
+	return doPatchOperation(ctx, payload, patchFunc)
 }
 
 // PatchInt performs a REST patch operation for the given path and int value.
-func PatchInt(path string, value uint32, patchFunc PatchFunc) {
+func PatchInt(ctx context.Context, path string, value uint32, patchFunc PatchFunc) {
 	payload := []PatchUInt32Value{{
 		Op:    "add",
 		Path:  path,
 		Value: value,
 	}}
 
-	doPatchOperation(payload, patchFunc)
+	Expect(doPatchOperation(ctx, payload, patchFunc)).To(Succeed())
 }
 
-func doPatchOperation(payload interface{}, patchFunc PatchFunc) {
+func doPatchOperation(ctx context.Context, payload interface{}, patchFunc PatchFunc) error {
 	payloadBytes, err := json.Marshal(payload)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "error marshaling the patch payload")
+	}
 
-	AwaitUntil("perform patch operation", func() (interface{}, error) {
+	_, err = awaitUntilOrError(ctx, "perform patch operation", func() (interface{}, error) {
 		return nil, patchFunc(types.JSONPatchType, payloadBytes)
 	}, NoopCheckResult)
+
+	return err
 }
 
 func NoopCheckResult(interface{}) (bool, string, error) {
@@ -556,18 +794,32 @@ func NoopCheckResult(interface{}) (bool, string, error) {
 }
 
 // AwaitUntil periodically performs the given operation until the given CheckResultFunc returns true, an error, or a
-// timeout is reached.
-func AwaitUntil(opMsg string, doOperation DoOperationFunc, checkResult CheckResultFunc) interface{} {
-	result, errMsg, err := AwaitResultOrError(opMsg, doOperation, checkResult)
+// timeout is reached. Polling stops early if the given context is cancelled or its deadline expires.
+func AwaitUntil(ctx context.Context, opMsg string, doOperation DoOperationFunc, checkResult CheckResultFunc) interface{} {
+	result, errMsg, err := AwaitResultOrError(ctx, opMsg, doOperation, checkResult)
 	Expect(err).NotTo(HaveOccurred(), errMsg)
 
 	return result
 }
 
-func AwaitResultOrError(opMsg string, doOperation DoOperationFunc, checkResult CheckResultFunc) (interface{}, string, error) {
+// awaitUntilOrError is like AwaitUntil but returns an error, annotated with the last check message, instead of failing via
+// Gomega.
+func awaitUntilOrError(ctx context.Context, opMsg string, doOperation DoOperationFunc, checkResult CheckResultFunc,
+) (interface{}, error) {
+	result, errMsg, err := AwaitResultOrError(ctx, opMsg, doOperation, checkResult)
+	if err != nil {
+		return nil, errors.WithMessage(err, errMsg)
+	}
+
+	return result, nil
+}
+
+func AwaitResultOrError(ctx context.Context, opMsg string, doOperation DoOperationFunc,
+	checkResult CheckResultFunc,
+) (interface{}, string, error) {
 	var finalResult interface{}
 	var lastMsg string
-	err := wait.PollUntilContextTimeout(context.Background(), 500*time.Millisecond, TestContext.OperationTimeoutToDuration(), true,
+	err := wait.PollUntilContextTimeout(ctx, 500*time.Millisecond, TestContext.OperationTimeoutToDuration(), true,
 		func(_ context.Context) (bool, error) {
 			result, err := doOperation()
 			if err != nil {
@@ -604,6 +856,19 @@ func AwaitResultOrError(opMsg string, doOperation DoOperationFunc, checkResult C
 	return finalResult, errMsg, err
 }
 
+// sleepWithContext pauses for the given duration or until the context is done, returning false in the latter case.
+func sleepWithContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
 func NestedString(obj map[string]interface{}, fields ...string) string {
 	str, _, err := unstructured.NestedString(obj, fields...)
 	Expect(err).To(Succeed())
@@ -612,8 +877,18 @@ func NestedString(obj map[string]interface{}, fields ...string) string {
 }
 
 func DetectProvider(ctx context.Context, cluster ClusterIndex, nodeName string) string {
-	node, err := KubeClients[cluster].CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	provider, err := DetectProviderOrError(ctx, cluster, nodeName)
 	Expect(err).NotTo(HaveOccurred())
 
-	return strings.Split(node.Spec.ProviderID, ":")[0]
+	return provider
+}
+
+// DetectProviderOrError is like DetectProvider but returns an error instead of failing via Gomega.
+func DetectProviderOrError(ctx context.Context, cluster ClusterIndex, nodeName string) (string, error) {
+	node, err := KubeClients[cluster].CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "error retrieving node %q", nodeName)
+	}
+
+	return strings.Split(node.Spec.ProviderID, ":")[0], nil
 }
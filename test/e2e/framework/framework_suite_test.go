@@ -0,0 +1,45 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+)
+
+func TestFramework(t *testing.T) {
+	RegisterFailHandler(Fail)
+	framework.SetStatusFunction(By)
+	RunSpecs(t, "Framework Suite")
+}
+
+var _ = BeforeEach(func() {
+	// Specs may change the TestContext, which must not leak into the next ones.
+	testContext := *framework.TestContext
+	DeferCleanup(func() {
+		*framework.TestContext = testContext
+	})
+
+	framework.TestContext.OperationTimeout = 1
+	framework.TestContext.GlobalnetEnabled = false
+	framework.TestContext.WatchEvents = false
+})
@@ -0,0 +1,191 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/framework/fake"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/testing"
+)
+
+var _ = Describe("fetchClusterIDs", func() {
+	var env *fake.Environment
+
+	BeforeEach(func() {
+		env = fake.NewEnvironment(
+			fake.ClusterConfig{ID: "east", GatewayNodes: []string{"east-gw"}},
+			fake.ClusterConfig{ID: "west"},
+		)
+		DeferCleanup(env.Install())
+
+		framework.TestContext.ClusterIDs = []string{"east-context", "west-context"}
+	})
+
+	It("should set the cluster IDs from the gateway DaemonSets", func(ctx context.Context) {
+		Expect(framework.FetchClusterIDs(ctx)).To(Succeed())
+		Expect(framework.TestContext.ClusterIDs).To(Equal([]string{"east", "west-context"}))
+	})
+
+	When("the gateway DaemonSet has no cluster ID", func() {
+		BeforeEach(func(ctx context.Context) {
+			daemonSets := env.Cluster(framework.ClusterA).KubeClient.AppsV1().DaemonSets(framework.TestContext.SubmarinerNamespace)
+
+			daemonSet, err := daemonSets.Get(ctx, framework.SubmarinerGateway, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			daemonSet.Spec.Template.Spec.Containers[0].Env = nil
+
+			_, err = daemonSets.Update(ctx, daemonSet, metav1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return an error", func(ctx context.Context) {
+			Expect(framework.FetchClusterIDs(ctx)).To(MatchError(ContainSubstring("SUBMARINER_CLUSTERID")))
+		})
+	})
+})
+
+var _ = Describe("DetectGlobalnet", func() {
+	var globalCIDR string
+
+	BeforeEach(func() {
+		globalCIDR = ""
+	})
+
+	JustBeforeEach(func() {
+		DeferCleanup(fake.NewEnvironment(
+			fake.ClusterConfig{ID: "east", GatewayNodes: []string{"east-gw"}, GlobalCIDR: globalCIDR},
+			fake.ClusterConfig{ID: "west", GatewayNodes: []string{"west-gw"}},
+		).Install())
+	})
+
+	When("the Cluster has a global CIDR", func() {
+		BeforeEach(func() {
+			globalCIDR = "242.0.0.0/16"
+		})
+
+		It("should enable Globalnet", func(ctx context.Context) {
+			framework.DetectGlobalnet(ctx)
+			Expect(framework.TestContext.GlobalnetEnabled).To(BeTrue())
+		})
+	})
+
+	When("the Cluster has no global CIDR", func() {
+		It("should not enable Globalnet", func(ctx context.Context) {
+			framework.DetectGlobalnet(ctx)
+			Expect(framework.TestContext.GlobalnetEnabled).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("AfterEachOrError", func() {
+	var (
+		env        *fake.Environment
+		f          *framework.Framework
+		specFailed bool
+	)
+
+	BeforeEach(func(ctx context.Context) {
+		env = fake.NewEnvironment(
+			fake.ClusterConfig{ID: "east", GatewayNodes: []string{"east-gw"}},
+			fake.ClusterConfig{ID: "west", GatewayNodes: []string{"west-gw"}},
+		)
+		DeferCleanup(env.Install())
+
+		specFailed = false
+
+		framework.SetSpecFailedFunction(func() bool {
+			return specFailed
+		})
+		DeferCleanup(framework.SetSpecFailedFunction, func() bool {
+			return false
+		})
+
+		f = framework.NewBareFramework("policy")
+		Expect(f.BeforeEachOrError(ctx)).To(Succeed())
+	})
+
+	namespaceExists := func(ctx context.Context, cluster framework.ClusterIndex, name string) bool {
+		_, err := env.Cluster(cluster).KubeClient.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+
+		Expect(err).NotTo(HaveOccurred())
+
+		return true
+	}
+
+	DescribeTable("the namespace deletion policy",
+		func(ctx context.Context, deleteNamespace, deleteNamespaceOnFailure, failed, expectDeleted bool) {
+			framework.TestContext.DeleteNamespace = deleteNamespace
+			framework.TestContext.DeleteNamespaceOnFailure = deleteNamespaceOnFailure
+			specFailed = failed
+
+			namespace := f.Namespace
+			Expect(f.AfterEachOrError(ctx)).To(Succeed())
+
+			Expect(namespaceExists(ctx, framework.ClusterA, namespace)).To(Equal(!expectDeleted))
+			Expect(namespaceExists(ctx, framework.ClusterB, namespace)).To(Equal(!expectDeleted))
+			Expect(f.Namespace).To(BeEmpty())
+		},
+		Entry("deleted after a success", true, false, false, true),
+		Entry("preserved after a failure", true, false, true, false),
+		Entry("deleted after a failure if requested", true, true, true, true),
+		Entry("deleted after a success with delete-namespace-on-failure", true, true, false, true),
+		Entry("always preserved without delete-namespace", false, true, false, false),
+		Entry("preserved after a failure without delete-namespace", false, true, true, false),
+	)
+
+	When("the namespace deletion doesn't complete", func() {
+		BeforeEach(func() {
+			framework.TestContext.DeleteNamespace = true
+
+			// The namespace is left in place, as if it were terminating.
+			env.Cluster(framework.ClusterB).KubeClient.PrependReactor("delete", "namespaces",
+				func(_ testing.Action) (bool, runtime.Object, error) {
+					return true, nil, nil
+				})
+
+			f.NamespaceDeletionTimeout = 100 * time.Millisecond
+		})
+
+		It("should return an error if waiting for the deletion", func(ctx context.Context) {
+			framework.TestContext.WaitForNamespaceDeletion = true
+
+			err := f.AfterEachOrError(ctx)
+			Expect(err).To(MatchError(ContainSubstring(`on cluster "west" was not deleted`)))
+			Expect(err).NotTo(MatchError(ContainSubstring(`"east"`)))
+		})
+
+		It("should not wait for the deletion otherwise", func(ctx context.Context) {
+			framework.TestContext.WaitForNamespaceDeletion = false
+
+			Expect(f.AfterEachOrError(ctx)).To(Succeed())
+		})
+	})
+})
@@ -24,10 +24,12 @@ import (
 	"strings"
 
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8serrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/dynamic"
 )
 
@@ -37,22 +39,31 @@ var gatewayGVR = &schema.GroupVersionResource{
 	Resource: "gateways",
 }
 
-func findGateway(cluster ClusterIndex, name string) (*unstructured.Unstructured, error) {
+func findGateway(ctx context.Context, cluster ClusterIndex, name string) (*unstructured.Unstructured, error) {
 	gwClient := gatewayClient(cluster)
-	resGw, err := gwClient.Get(context.TODO(), name, metav1.GetOptions{})
+	resGw, err := gwClient.Get(ctx, name, metav1.GetOptions{})
 
 	if apierrors.IsNotFound(err) {
 		// Some environments sets a node in Gateway resource without a suffix
-		resGw, err = gwClient.Get(context.TODO(), strings.Split(name, ".")[0], metav1.GetOptions{})
+		resGw, err = gwClient.Get(ctx, strings.Split(name, ".")[0], metav1.GetOptions{})
 	}
 
 	return resGw, err
 }
 
-func (f *Framework) AwaitGatewayWithStatus(cluster ClusterIndex, name, status string) *unstructured.Unstructured {
-	obj := AwaitUntil(fmt.Sprintf("await Gateway on %q with status %q", name, status),
+func (f *Framework) AwaitGatewayWithStatus(ctx context.Context, cluster ClusterIndex, name, status string) *unstructured.Unstructured {
+	gw, err := f.AwaitGatewayWithStatusOrError(ctx, cluster, name, status)
+	Expect(err).NotTo(HaveOccurred())
+
+	return gw
+}
+
+// AwaitGatewayWithStatusOrError is like AwaitGatewayWithStatus but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitGatewayWithStatusOrError(ctx context.Context, cluster ClusterIndex, name, status string,
+) (*unstructured.Unstructured, error) {
+	obj, err := awaitUntilOrError(ctx, fmt.Sprintf("await Gateway on %q with status %q", name, status),
 		func() (interface{}, error) {
-			gw, err := findGateway(cluster, name)
+			gw, err := findGateway(ctx, cluster, name)
 			if apierrors.IsNotFound(err) {
 				return nil, nil //nolint:nilnil // We want to repeat but let the checker known that nothing was found.
 			}
@@ -65,7 +76,11 @@ func (f *Framework) AwaitGatewayWithStatus(cluster ClusterIndex, name, status st
 			}
 
 			gw := result.(*unstructured.Unstructured)
-			haStatus := NestedString(gw.Object, "status", "haStatus")
+
+			haStatus, _, err := unstructured.NestedString(gw.Object, "status", "haStatus")
+			if err != nil {
+				return false, "", err
+			}
 
 			if haStatus != status {
 				return false, fmt.Sprintf("gateway %q exists but has wrong status %q, expected %q", gw.GetName(), haStatus, status), nil
@@ -73,18 +88,30 @@ func (f *Framework) AwaitGatewayWithStatus(cluster ClusterIndex, name, status st
 
 			return true, "", nil
 		})
+	if err != nil {
+		return nil, err
+	}
 
-	return obj.(*unstructured.Unstructured)
+	return obj.(*unstructured.Unstructured), nil
 }
 
 func gatewayClient(cluster ClusterIndex) dynamic.ResourceInterface {
 	return DynClients[cluster].Resource(*gatewayGVR).Namespace(TestContext.SubmarinerNamespace)
 }
 
-func (f *Framework) AwaitGatewaysWithStatus(cluster ClusterIndex, status string) []unstructured.Unstructured {
-	gwList := AwaitUntil(fmt.Sprintf("await Gateways with status %q", status),
+func (f *Framework) AwaitGatewaysWithStatus(ctx context.Context, cluster ClusterIndex, status string) []unstructured.Unstructured {
+	gateways, err := f.AwaitGatewaysWithStatusOrError(ctx, cluster, status)
+	Expect(err).NotTo(HaveOccurred())
+
+	return gateways
+}
+
+// AwaitGatewaysWithStatusOrError is like AwaitGatewaysWithStatus but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitGatewaysWithStatusOrError(ctx context.Context, cluster ClusterIndex, status string,
+) ([]unstructured.Unstructured, error) {
+	gwList, err := awaitUntilOrError(ctx, fmt.Sprintf("await Gateways with status %q", status),
 		func() (interface{}, error) {
-			return f.GetGatewaysWithHAStatus(cluster, status), nil
+			return f.GetGatewaysWithHAStatusOrError(ctx, cluster, status)
 		},
 		func(result interface{}) (bool, string, error) {
 			gateways := result.([]unstructured.Unstructured)
@@ -94,16 +121,24 @@ func (f *Framework) AwaitGatewaysWithStatus(cluster ClusterIndex, status string)
 
 			return true, "", nil
 		})
+	if err != nil {
+		return nil, err
+	}
+
+	return gwList.([]unstructured.Unstructured), nil
+}
 
-	return gwList.([]unstructured.Unstructured)
+func (f *Framework) AwaitGatewayRemoved(ctx context.Context, cluster ClusterIndex, name string) {
+	Expect(f.AwaitGatewayRemovedOrError(ctx, cluster, name)).To(Succeed())
 }
 
-func (f *Framework) AwaitGatewayRemoved(cluster ClusterIndex, name string) {
+// AwaitGatewayRemovedOrError is like AwaitGatewayRemoved but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitGatewayRemovedOrError(ctx context.Context, cluster ClusterIndex, name string) error {
 	gwClient := gatewayClient(cluster)
 
-	AwaitUntil(fmt.Sprintf("await Gateway on %q removed", name),
+	_, err := awaitUntilOrError(ctx, fmt.Sprintf("await Gateway on %q removed", name),
 		func() (interface{}, error) {
-			_, err := gwClient.Get(context.TODO(), name, metav1.GetOptions{})
+			_, err := gwClient.Get(ctx, name, metav1.GetOptions{})
 			if apierrors.IsNotFound(err) {
 				return true, nil
 			}
@@ -114,12 +149,23 @@ func (f *Framework) AwaitGatewayRemoved(cluster ClusterIndex, name string) {
 			gone := result.(bool)
 			return gone, "", nil
 		})
+
+	return err
 }
 
-func (f *Framework) AwaitGatewayFullyConnected(cluster ClusterIndex, name string) *unstructured.Unstructured {
-	obj := AwaitUntil(fmt.Sprintf("await Gateway on %q with status active and connections UP", name),
+func (f *Framework) AwaitGatewayFullyConnected(ctx context.Context, cluster ClusterIndex, name string) *unstructured.Unstructured {
+	gw, err := f.AwaitGatewayFullyConnectedOrError(ctx, cluster, name)
+	Expect(err).NotTo(HaveOccurred())
+
+	return gw
+}
+
+// AwaitGatewayFullyConnectedOrError is like AwaitGatewayFullyConnected but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitGatewayFullyConnectedOrError(ctx context.Context, cluster ClusterIndex, name string,
+) (*unstructured.Unstructured, error) {
+	obj, err := awaitUntilOrError(ctx, fmt.Sprintf("await Gateway on %q with status active and connections UP", name),
 		func() (interface{}, error) {
-			gw, err := findGateway(cluster, name)
+			gw, err := findGateway(ctx, cluster, name)
 			if apierrors.IsNotFound(err) {
 				return nil, nil //nolint:nilnil // We want to repeat but let the checker known that nothing was found.
 			}
@@ -131,63 +177,91 @@ func (f *Framework) AwaitGatewayFullyConnected(cluster ClusterIndex, name string
 				return false, "gateway not found yet", nil
 			}
 
-			gw := result.(*unstructured.Unstructured)
-			haStatus := NestedString(gw.Object, "status", "haStatus")
+			return checkGatewayFullyConnected(result.(*unstructured.Unstructured))
+		})
+	if err != nil {
+		return nil, err
+	}
 
-			if haStatus != "active" {
-				return false, fmt.Sprintf("Gateway %q exists but not active yet",
-					gw.GetName()), nil
-			}
+	return obj.(*unstructured.Unstructured), nil
+}
 
-			connections, _, _ := unstructured.NestedSlice(gw.Object, "status", "connections")
-			if len(connections) == 0 {
-				return false, fmt.Sprintf("Gateway %q is active but has no connections yet", name), nil
-			}
+func checkGatewayFullyConnected(gw *unstructured.Unstructured) (bool, string, error) {
+	haStatus, _, err := unstructured.NestedString(gw.Object, "status", "haStatus")
+	if err != nil {
+		return false, "", err
+	}
 
-			for _, o := range connections {
-				conn := o.(map[string]interface{})
-				status, _, _ := unstructured.NestedString(conn, "status")
+	if haStatus != "active" {
+		return false, fmt.Sprintf("Gateway %q exists but not active yet",
+			gw.GetName()), nil
+	}
 
-				if status != "connected" {
-					return false, fmt.Sprintf("Gateway %q is active but cluster %q is not connected: Status: %q, Message: %q",
-						name, NestedString(conn, "endpoint", "cluster_id"), status, NestedString(conn, "statusMessage")), nil
-				}
-			}
+	connections, _, _ := unstructured.NestedSlice(gw.Object, "status", "connections")
+	if len(connections) == 0 {
+		return false, fmt.Sprintf("Gateway %q is active but has no connections yet", gw.GetName()), nil
+	}
 
-			return true, "", nil
-		})
+	for _, o := range connections {
+		conn := o.(map[string]interface{})
+		status, _, _ := unstructured.NestedString(conn, "status")
+
+		if status != "connected" {
+			clusterID, _, _ := unstructured.NestedString(conn, "endpoint", "cluster_id")
+			statusMessage, _, _ := unstructured.NestedString(conn, "statusMessage")
+
+			return false, fmt.Sprintf("Gateway %q is active but cluster %q is not connected: Status: %q, Message: %q",
+				gw.GetName(), clusterID, status, statusMessage), nil
+		}
+	}
 
-	return obj.(*unstructured.Unstructured)
+	return true, "", nil
 }
 
 func (f *Framework) GetGatewaysWithHAStatus(
-	cluster ClusterIndex, status string,
+	ctx context.Context, cluster ClusterIndex, status string,
 ) []unstructured.Unstructured {
+	gateways, err := f.GetGatewaysWithHAStatusOrError(ctx, cluster, status)
+	Expect(err).NotTo(HaveOccurred())
+
+	return gateways
+}
+
+// GetGatewaysWithHAStatusOrError is like GetGatewaysWithHAStatus but returns an error instead of failing via Gomega.
+func (f *Framework) GetGatewaysWithHAStatusOrError(
+	ctx context.Context, cluster ClusterIndex, status string,
+) ([]unstructured.Unstructured, error) {
 	gwClient := gatewayClient(cluster)
-	gwList, err := gwClient.List(context.TODO(), metav1.ListOptions{})
+	gwList, err := gwClient.List(ctx, metav1.ListOptions{})
 
 	filteredGateways := []unstructured.Unstructured{}
 
 	// List will return "NotFound" if the CRD is not registered in the specific cluster (broker-only)
 	if apierrors.IsNotFound(err) {
-		return filteredGateways
+		return filteredGateways, nil
 	}
 
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing Gateways on cluster %q", TestContext.ClusterIDs[cluster])
+	}
 
 	for _, gw := range gwList.Items {
-		haStatus := NestedString(gw.Object, "status", "haStatus")
+		haStatus, _, err := unstructured.NestedString(gw.Object, "status", "haStatus")
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading the HA status of Gateway %q", gw.GetName())
+		}
+
 		if haStatus == status {
 			filteredGateways = append(filteredGateways, gw)
 		}
 	}
 
-	return filteredGateways
+	return filteredGateways, nil
 }
 
-func (f *Framework) DeleteGateway(cluster ClusterIndex, name string) {
-	AwaitUntil("delete gateway", func() (interface{}, error) {
-		err := gatewayClient(cluster).Delete(context.TODO(), name, metav1.DeleteOptions{})
+func (f *Framework) DeleteGateway(ctx context.Context, cluster ClusterIndex, name string) {
+	AwaitUntil(ctx, "delete gateway", func() (interface{}, error) {
+		err := gatewayClient(cluster).Delete(ctx, name, metav1.DeleteOptions{})
 		if apierrors.IsNotFound(err) {
 			return nil, nil //nolint:nilnil // We want to repeat but let the checker known that nothing was found.
 		}
@@ -196,50 +270,83 @@ func (f *Framework) DeleteGateway(cluster ClusterIndex, name string) {
 	}, NoopCheckResult)
 }
 
+// SaveGatewayNode records a gateway node to restore at the end of the current spec, via a SpecScope cleanup action.
 func (f *Framework) SaveGatewayNode(cluster ClusterIndex, gwNode string) {
+	if len(f.gatewayNodesToReset) == 0 {
+		AddScopedCleanupAction(CleanupAction{
+			Name:  "restore gateway nodes",
+			Scope: SpecScope,
+			Run:   f.GatewayCleanupOrError,
+		})
+	}
+
 	f.gatewayNodesToReset[int(cluster)] = append(f.gatewayNodesToReset[int(cluster)], gwNode)
 }
 
 // GatewayCleanup will be executed only on kind environment.
 // It will restore the gateway nodes to its initial state.
 // Other environments do not need any gw cleanup as MachineSet is responsible to keeping the gw nodes in active states.
-func (f *Framework) GatewayCleanup() {
-	ctx := context.TODO()
+func (f *Framework) GatewayCleanup(ctx context.Context) {
+	Expect(f.GatewayCleanupOrError(ctx)).To(Succeed())
+}
+
+// GatewayCleanupOrError is like GatewayCleanup but returns an error instead of failing via Gomega. All the gateway
+// nodes are restored even if some fail.
+func (f *Framework) GatewayCleanupOrError(ctx context.Context) error {
+	var errs []error
 
 	for cluster := range f.gatewayNodesToReset {
 		for _, gnode := range f.gatewayNodesToReset[cluster] {
 			By(fmt.Sprintf("Restoring gateway %q on cluster %q", gnode, TestContext.ClusterIDs[cluster]))
-			f.SetGatewayLabelOnNode(ctx, ClusterIndex(cluster), gnode, true)
+
+			if err := f.SetGatewayLabelOnNodeOrError(ctx, ClusterIndex(cluster), gnode, true); err != nil {
+				errs = append(errs, errors.Wrapf(err, "error restoring gateway %q on cluster %q", gnode,
+					TestContext.ClusterIDs[cluster]))
+			}
 		}
+
+		delete(f.gatewayNodesToReset, cluster)
 	}
+
+	return k8serrors.NewAggregate(errs)
 }
 
 // Perform a gateway failover.
 // The failover for the real environment will crash the gateway node.
 // The failover for the kind environment will set the submariner.io/gateway label to "false" on the gw node.
 func (f *Framework) DoFailover(ctx context.Context, cluster ClusterIndex, gwNode, gwPod string) {
-	provider := DetectProvider(ctx, cluster, gwNode)
+	Expect(f.DoFailoverOrError(ctx, cluster, gwNode, gwPod)).To(Succeed())
+}
+
+// DoFailoverOrError is like DoFailover but returns an error instead of failing via Gomega.
+func (f *Framework) DoFailoverOrError(ctx context.Context, cluster ClusterIndex, gwNode, gwPod string) error {
+	provider, err := DetectProviderOrError(ctx, cluster, gwNode)
+	if err != nil {
+		return err
+	}
 
 	if provider == "kind" {
 		f.SaveGatewayNode(cluster, gwNode)
-		f.SetGatewayLabelOnNode(ctx, cluster, gwNode, false)
-	} else {
-		cmd := []string{"sh", "-c", "echo 1 > /proc/sys/kernel/sysrq && echo b > /proc/sysrq-trigger"}
-
-		_, _, err := f.ExecWithOptions(ctx, &ExecOptions{
-			Command:       cmd,
-			Namespace:     TestContext.SubmarinerNamespace,
-			PodName:       gwPod,
-			ContainerName: SubmarinerGateway,
-			CaptureStdout: false,
-			CaptureStderr: true,
-		}, cluster)
-		if err != nil {
-			if strings.Contains(err.Error(), "unable to upgrade connection: container not found") {
-				By(fmt.Sprintf("Successfully crashed gateway node %q", gwNode))
-			} else {
-				Expect(err).NotTo(HaveOccurred())
-			}
+		return f.SetGatewayLabelOnNodeOrError(ctx, cluster, gwNode, false)
+	}
+
+	cmd := []string{"sh", "-c", "echo 1 > /proc/sys/kernel/sysrq && echo b > /proc/sysrq-trigger"}
+
+	_, _, err = f.ExecWithOptions(ctx, &ExecOptions{
+		Command:       cmd,
+		Namespace:     TestContext.SubmarinerNamespace,
+		PodName:       gwPod,
+		ContainerName: SubmarinerGateway,
+		CaptureStdout: false,
+		CaptureStderr: true,
+	}, cluster)
+	if err != nil {
+		if !strings.Contains(err.Error(), "unable to upgrade connection: container not found") {
+			return errors.Wrapf(err, "error crashing gateway node %q", gwNode)
 		}
+
+		By(fmt.Sprintf("Successfully crashed gateway node %q", gwNode))
 	}
+
+	return nil
 }
@@ -0,0 +1,106 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/framework/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("AwaitGatewayFullyConnected", func() {
+	var (
+		env *fake.Environment
+		f   *framework.Framework
+	)
+
+	BeforeEach(func() {
+		env = fake.NewEnvironment(fake.ClusterConfig{ID: "east", GatewayNodes: []string{"east-gw"}})
+		DeferCleanup(env.Install())
+
+		f = framework.NewBareFramework("test")
+	})
+
+	setGatewayStatus := func(ctx context.Context, haStatus string, connectionStatuses ...string) {
+		connections := []interface{}{}
+		for _, status := range connectionStatuses {
+			connections = append(connections, map[string]interface{}{
+				"status":        status,
+				"statusMessage": "",
+				"endpoint":      map[string]interface{}{"cluster_id": "west"},
+			})
+		}
+
+		_, err := env.Cluster(framework.ClusterA).DynClient.Resource(fake.GatewayGVR).Namespace(
+			framework.TestContext.SubmarinerNamespace).Update(ctx, fake.NewUnstructured("submariner.io/v1", "Gateway",
+			framework.TestContext.SubmarinerNamespace, "east-gw", map[string]interface{}{
+				"status": map[string]interface{}{"haStatus": haStatus, "connections": connections},
+			}), metav1.UpdateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	When("the gateway is active and all its connections are connected", func() {
+		It("should return the Gateway", func(ctx context.Context) {
+			setGatewayStatus(ctx, "active", "connected")
+
+			gw := f.AwaitGatewayFullyConnected(ctx, framework.ClusterA, "east-gw")
+			Expect(gw.GetName()).To(Equal("east-gw"))
+		})
+	})
+
+	When("the gateway is only found by its short name", func() {
+		It("should return the Gateway", func(ctx context.Context) {
+			setGatewayStatus(ctx, "active", "connected")
+
+			gw := f.AwaitGatewayFullyConnected(ctx, framework.ClusterA, "east-gw.example.com")
+			Expect(gw.GetName()).To(Equal("east-gw"))
+		})
+	})
+
+	When("the gateway is passive", func() {
+		It("should time out", func(ctx context.Context) {
+			setGatewayStatus(ctx, "passive")
+
+			_, err := f.AwaitGatewayFullyConnectedOrError(ctx, framework.ClusterA, "east-gw")
+			Expect(err).To(MatchError(ContainSubstring("not active yet")))
+		})
+	})
+
+	When("a connection isn't connected", func() {
+		It("should time out", func(ctx context.Context) {
+			setGatewayStatus(ctx, "active", "connected", "error")
+
+			_, err := f.AwaitGatewayFullyConnectedOrError(ctx, framework.ClusterA, "east-gw")
+			Expect(err).To(MatchError(ContainSubstring(`cluster "west" is not connected`)))
+		})
+	})
+
+	When("the gateway has no connections", func() {
+		It("should time out", func(ctx context.Context) {
+			setGatewayStatus(ctx, "active")
+
+			_, err := f.AwaitGatewayFullyConnectedOrError(ctx, framework.ClusterA, "east-gw")
+			Expect(err).To(MatchError(ContainSubstring("has no connections yet")))
+		})
+	})
+})
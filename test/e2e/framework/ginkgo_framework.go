@@ -23,11 +23,28 @@ import "github.com/onsi/ginkgo/v2"
 // NewFramework creates a test framework, under ginkgo.
 func NewFramework(baseName string) *Framework {
 	f := NewBareFramework(baseName)
+	// GinkgoWriter, unlike By, may be used from other goroutines.
+	f.eventWriter = ginkgo.GinkgoWriter
+
+	ginkgo.BeforeEach(func() {
+		skipOnUnmetRequirements(ginkgo.CurrentSpecReport().Labels())
+	})
 
 	ginkgo.BeforeEach(f.BeforeEach)
 	ginkgo.AfterEach(f.AfterEach)
-
-	AddCleanupAction(f.GatewayCleanup)
+	ginkgo.AfterEach(func() {
+		if report := f.EventsReport(); report != "" {
+			ginkgo.AddReportEntry("Kubernetes events", report, ginkgo.ReportEntryVisibilityFailureOrVerbose)
+		}
+	})
+
+	// Deferred from a BeforeEach, the SpecScope cleanup actions run after the AfterEach nodes, including the namespace
+	// deletion, and their failures fail the spec.
+	ginkgo.BeforeEach(func() {
+		ginkgo.DeferCleanup(func(ctx ginkgo.SpecContext) error {
+			return RunCleanupActionsOrError(ctx, SpecScope)
+		})
+	})
 
 	return f
 }
@@ -38,11 +38,11 @@ func globalEgressIPClient(cluster ClusterIndex, namespace string) dynamic.Resour
 	return DynClients[cluster].Resource(*globalEgressIPGVR).Namespace(namespace)
 }
 
-func CreateGlobalEgressIP(cluster ClusterIndex, obj *unstructured.Unstructured) error {
+func CreateGlobalEgressIP(ctx context.Context, cluster ClusterIndex, obj *unstructured.Unstructured) error {
 	geipClient := globalEgressIPClient(cluster, obj.GetNamespace())
 
-	AwaitUntil("create GlobalEgressIP", func() (interface{}, error) {
-		egressIP, err := geipClient.Create(context.TODO(), obj, metav1.CreateOptions{})
+	_, err := awaitUntilOrError(ctx, "create GlobalEgressIP", func() (interface{}, error) {
+		egressIP, err := geipClient.Create(ctx, obj, metav1.CreateOptions{})
 		if apierrors.IsAlreadyExists(err) {
 			err = nil
 		}
@@ -50,11 +50,18 @@ func CreateGlobalEgressIP(cluster ClusterIndex, obj *unstructured.Unstructured)
 		return egressIP, err
 	}, NoopCheckResult)
 
-	return nil
+	return err
 }
 
-func AwaitGlobalEgressIPs(cluster ClusterIndex, name, namespace string) []string {
+func AwaitGlobalEgressIPs(ctx context.Context, cluster ClusterIndex, name, namespace string) []string {
 	gipClient := globalEgressIPClient(cluster, namespace)
 
-	return AwaitAllocatedEgressIPs(gipClient, name)
+	return AwaitAllocatedEgressIPs(ctx, gipClient, name)
+}
+
+// AwaitGlobalEgressIPsOrError is like AwaitGlobalEgressIPs but returns an error instead of failing via Gomega.
+func AwaitGlobalEgressIPsOrError(ctx context.Context, cluster ClusterIndex, name, namespace string) ([]string, error) {
+	gipClient := globalEgressIPClient(cluster, namespace)
+
+	return AwaitAllocatedEgressIPsOrError(ctx, gipClient, name)
 }
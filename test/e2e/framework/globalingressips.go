@@ -22,6 +22,7 @@ import (
 	"context"
 	"fmt"
 
+	. "github.com/onsi/gomega"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -35,12 +36,20 @@ var globalIngressIPGVR = &schema.GroupVersionResource{
 	Resource: "globalingressips",
 }
 
-func (f *Framework) AwaitGlobalIngressIP(cluster ClusterIndex, name, namespace string) string {
+func (f *Framework) AwaitGlobalIngressIP(ctx context.Context, cluster ClusterIndex, name, namespace string) string {
+	globalIP, err := f.AwaitGlobalIngressIPOrError(ctx, cluster, name, namespace)
+	Expect(err).NotTo(HaveOccurred())
+
+	return globalIP
+}
+
+// AwaitGlobalIngressIPOrError is like AwaitGlobalIngressIP but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitGlobalIngressIPOrError(ctx context.Context, cluster ClusterIndex, name, namespace string) (string, error) {
 	if TestContext.GlobalnetEnabled {
 		gipClient := globalIngressIPClient(cluster, namespace)
-		obj := AwaitUntil(fmt.Sprintf("await GlobalIngressIP %s/%s", namespace, name),
+		obj, err := awaitUntilOrError(ctx, fmt.Sprintf("await GlobalIngressIP %s/%s", namespace, name),
 			func() (interface{}, error) {
-				resGip, err := gipClient.Get(context.TODO(), name, metav1.GetOptions{})
+				resGip, err := gipClient.Get(ctx, name, metav1.GetOptions{})
 				if apierrors.IsNotFound(err) {
 					return nil, nil //nolint:nilnil // We want to repeat but let the checker known that nothing was found.
 				}
@@ -60,18 +69,26 @@ func (f *Framework) AwaitGlobalIngressIP(cluster ClusterIndex, name, namespace s
 
 				return true, "", nil
 			})
+		if err != nil {
+			return "", err
+		}
 
-		return getGlobalIP(obj.(*unstructured.Unstructured))
+		return getGlobalIP(obj.(*unstructured.Unstructured)), nil
 	}
 
-	return ""
+	return "", nil
 }
 
-func (f *Framework) AwaitGlobalIngressIPRemoved(cluster ClusterIndex, name, namespace string) {
+func (f *Framework) AwaitGlobalIngressIPRemoved(ctx context.Context, cluster ClusterIndex, name, namespace string) {
+	Expect(f.AwaitGlobalIngressIPRemovedOrError(ctx, cluster, name, namespace)).To(Succeed())
+}
+
+// AwaitGlobalIngressIPRemovedOrError is like AwaitGlobalIngressIPRemoved but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitGlobalIngressIPRemovedOrError(ctx context.Context, cluster ClusterIndex, name, namespace string) error {
 	gipClient := globalIngressIPClient(cluster, namespace)
-	AwaitUntil(fmt.Sprintf("await GlobalIngressIP %s/%s removed", namespace, name),
+	_, err := awaitUntilOrError(ctx, fmt.Sprintf("await GlobalIngressIP %s/%s removed", namespace, name),
 		func() (interface{}, error) {
-			_, err := gipClient.Get(context.TODO(), name, metav1.GetOptions{})
+			_, err := gipClient.Get(ctx, name, metav1.GetOptions{})
 			if apierrors.IsNotFound(err) {
 				return true, nil
 			}
@@ -82,6 +99,8 @@ func (f *Framework) AwaitGlobalIngressIPRemoved(cluster ClusterIndex, name, name
 			gone := result.(bool)
 			return gone, "", nil
 		})
+
+	return err
 }
 
 func globalIngressIPClient(cluster ClusterIndex, namespace string) dynamic.ResourceInterface {
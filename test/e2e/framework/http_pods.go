@@ -0,0 +1,269 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	httpResultPrefix = "[http]"
+
+	nginxDemoName = "nginx-demo"
+	nginxDemoPort = 8080
+)
+
+// HTTPProbeResult is the outcome of the HTTP request sent by an HTTPClientPod.
+type HTTPProbeResult struct {
+	// StatusCode is the HTTP status code of the response, 0 if no response was received.
+	StatusCode int
+	Body       string
+	// Error is the error reported by the client, if any.
+	Error string
+	// DNSLookup, Connect, FirstByte and Total are the times elapsed from the start of the request until the name was
+	// resolved, the connection was established, the first byte of the response was received and the response was
+	// complete.
+	DNSLookup time.Duration
+	Connect   time.Duration
+	FirstByte time.Duration
+	Total     time.Duration
+}
+
+// HTTPProbeResult parses the result of the HTTP request sent by an HTTPClientPod, once it has finished.
+func (np *NetworkPod) HTTPProbeResult() (*HTTPProbeResult, error) {
+	return parseHTTPProbeResult(np.TerminationMessage)
+}
+
+// The output of the HTTP client pod is the response body, followed by a line with the status code and timings, followed
+// by the client's errors.
+func parseHTTPProbeResult(output string) (*HTTPProbeResult, error) {
+	index := strings.LastIndex(output, httpResultPrefix)
+	if index < 0 {
+		return nil, fmt.Errorf("the HTTP client output doesn't contain a result: %q", output)
+	}
+
+	resultLine, errorText, _ := strings.Cut(output[index+len(httpResultPrefix):], "\n")
+
+	fields := strings.Fields(resultLine)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid HTTP client result %q", resultLine)
+	}
+
+	result := &HTTPProbeResult{
+		Body:  strings.TrimSuffix(output[:index], "\n"),
+		Error: strings.TrimSpace(errorText),
+	}
+
+	var err error
+
+	result.StatusCode, err = strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid HTTP status code %q", fields[0])
+	}
+
+	durations := []*time.Duration{&result.DNSLookup, &result.Connect, &result.FirstByte, &result.Total}
+
+	for i, duration := range durations {
+		seconds, err := strconv.ParseFloat(fields[i+1], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid HTTP timing %q", fields[i+1])
+		}
+
+		*duration = time.Duration(seconds * float64(time.Second))
+	}
+
+	return result, nil
+}
+
+// httpURL returns the URL of the HTTP server at the given host, which is an IP or a host name, and port.
+func httpURL(host string, port int32) string {
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(int(port))) + "/"
+}
+
+// create a test pod inside the current test namespace on the specified cluster.
+// The pod will serve HTTP requests on TestPort, responding with sendString, until
+// it's deleted. sendString is the pod's Data.
+func (np *NetworkPod) buildHTTPServerPod(ctx context.Context) error {
+	affinity, err := np.nodeAffinity(ctx, np.Config.Scheduling)
+	if err != nil {
+		return err
+	}
+
+	httpServerPod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "http-server",
+			Labels: map[string]string{
+				TestAppLabel: "http-server",
+			},
+		},
+		Spec: v1.PodSpec{
+			Affinity:      affinity,
+			RestartPolicy: v1.RestartPolicyNever,
+			Containers: []v1.Container{
+				{
+					Name:    "http-server",
+					Image:   TestContext.NettestImageURL,
+					Command: []string{"/app/simpleserver"},
+					Env: []v1.EnvVar{
+						{Name: "PORT", Value: strconv.FormatInt(int64(np.Config.Port), 10)},
+						{Name: "LISTEN_ADDRESS", Value: anyAddressForFamily(np.Config.IPFamily)},
+						{Name: "BODY", Value: np.Config.Data},
+					},
+					SecurityContext: podSecurityContext,
+				},
+			},
+			Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
+		},
+	}
+
+	if err := np.create(ctx, &httpServerPod); err != nil {
+		return err
+	}
+
+	return np.AwaitReadyOrError(ctx)
+}
+
+// create a test pod inside the current test namespace on the specified cluster.
+// The pod will send an HTTP GET request to remoteIP:TestPort, where remoteIP may
+// also be a host name, until it gets a successful response or the attempts are
+// exhausted, and write the response body, status code and timings in the pod
+// termination log, then exit with 0 status.
+func (np *NetworkPod) buildHTTPClientPod(ctx context.Context) error {
+	affinity, err := np.nodeAffinity(ctx, np.Config.Scheduling)
+	if err != nil {
+		return err
+	}
+
+	httpClientPod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "http-client",
+			Labels: map[string]string{
+				TestAppLabel: "http-client",
+			},
+		},
+		Spec: v1.PodSpec{
+			Affinity:      affinity,
+			RestartPolicy: v1.RestartPolicyNever,
+			HostNetwork:   bool(np.Config.Networking),
+			Containers: []v1.Container{
+				{
+					Name:  "http-client",
+					Image: TestContext.NettestImageURL,
+					Command: []string{
+						"sh",
+						"-c",
+						"for i in $(seq $CONN_TRIES);" +
+							" do rm -f /tmp/body;" +
+							" result=$(curl -sS -g --max-time $CONN_TIMEOUT -o /tmp/body" +
+							" -w '%{http_code} %{time_namelookup} %{time_connect} %{time_starttransfer} %{time_total}'" +
+							" $URL 2>/tmp/error);" +
+							" case $result in 2*|3*) break;; esac;" +
+							" sleep $RETRY_SLEEP;" +
+							" done;" +
+							" (cat /tmp/body; echo; echo " + httpResultPrefix + " $result; cat /tmp/error) >/dev/termination-log 2>&1",
+					},
+					Env: []v1.EnvVar{
+						{Name: "URL", Value: httpURL(np.Config.RemoteIP, np.Config.Port)},
+						{Name: "CONN_TRIES", Value: strconv.FormatUint(uint64(np.Config.ConnectionAttempts), 10)},
+						{Name: "CONN_TIMEOUT", Value: strconv.FormatUint(uint64(np.Config.ConnectionTimeout), 10)},
+						{Name: "RETRY_SLEEP", Value: strconv.FormatUint(uint64(np.Config.ConnectionTimeout/2), 10)},
+					},
+					SecurityContext: podSecurityContext,
+				},
+			},
+			Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
+		},
+	}
+
+	return np.create(ctx, &httpClientPod)
+}
+
+// NewNginxDeployment creates the "nginx-demo" deployment, serving HTTP on the target port of the service created by
+// NewNginxService, and waits for its replicas to be available.
+func (f *Framework) NewNginxDeployment(ctx context.Context, cluster ClusterIndex, replicas int32) *appsv1.Deployment {
+	deployment, err := f.NewNginxDeploymentOrError(ctx, cluster, replicas)
+	Expect(err).NotTo(HaveOccurred())
+
+	return deployment
+}
+
+// NewNginxDeploymentOrError is like NewNginxDeployment but returns an error instead of failing via Gomega.
+func (f *Framework) NewNginxDeploymentOrError(ctx context.Context, cluster ClusterIndex, replicas int32) (*appsv1.Deployment, error) {
+	labels := map[string]string{"app": nginxDemoName}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   nginxDemoName,
+			Labels: labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(replicas),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Name:            nginxDemoName,
+							Image:           TestContext.NettestImageURL,
+							Command:         []string{"/app/simpleserver"},
+							Ports:           []v1.ContainerPort{{ContainerPort: nginxDemoPort}},
+							SecurityContext: podSecurityContext,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	deployments := KubeClients[cluster].AppsV1().Deployments(f.Namespace)
+
+	_, err := deployments.Create(ctx, deployment, metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating deployment %q on cluster %q", nginxDemoName, TestContext.ClusterIDs[cluster])
+	}
+
+	result, err := awaitUntilOrError(ctx, fmt.Sprintf("await deployment %q available", nginxDemoName), func() (interface{}, error) {
+		return deployments.Get(ctx, nginxDemoName, metav1.GetOptions{})
+	}, func(result interface{}) (bool, string, error) {
+		deployment := result.(*appsv1.Deployment)
+		if deployment.Status.AvailableReplicas < replicas {
+			return false, fmt.Sprintf("%d of %d replicas are available", deployment.Status.AvailableReplicas, replicas), nil
+		}
+
+		return true, "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*appsv1.Deployment), nil
+}
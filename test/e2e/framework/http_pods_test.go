@@ -0,0 +1,87 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+)
+
+var _ = DescribeTable("parseHTTPProbeResult",
+	func(output string, expected *framework.HTTPProbeResult) {
+		Expect(framework.ParseHTTPProbeResult(output)).To(Equal(expected))
+	},
+	Entry("successful response",
+		"5f6b1c1e-43a7-4f1d-9b0e-2f8a7c3d1e90\n\n[http] 200 0.000021 0.000412 0.001873 0.001921\n",
+		&framework.HTTPProbeResult{
+			StatusCode: 200,
+			Body:       "5f6b1c1e-43a7-4f1d-9b0e-2f8a7c3d1e90\n",
+			DNSLookup:  21 * time.Microsecond,
+			Connect:    412 * time.Microsecond,
+			FirstByte:  1873 * time.Microsecond,
+			Total:      1921 * time.Microsecond,
+		}),
+	Entry("response to a clusterset name",
+		"5f6b1c1e\n[http] 200 0.004518 0.005102 0.007342 0.007406\n",
+		&framework.HTTPProbeResult{
+			StatusCode: 200,
+			Body:       "5f6b1c1e",
+			DNSLookup:  4518 * time.Microsecond,
+			Connect:    5102 * time.Microsecond,
+			FirstByte:  7342 * time.Microsecond,
+			Total:      7406 * time.Microsecond,
+		}),
+	Entry("error response",
+		"<html><body><h1>503 Service Unavailable</h1></body></html>\n\n[http] 503 0.000019 0.000388 0.001202 0.001240\n",
+		&framework.HTTPProbeResult{
+			StatusCode: 503,
+			Body:       "<html><body><h1>503 Service Unavailable</h1></body></html>\n",
+			DNSLookup:  19 * time.Microsecond,
+			Connect:    388 * time.Microsecond,
+			FirstByte:  1202 * time.Microsecond,
+			Total:      1240 * time.Microsecond,
+		}),
+	Entry("connection timeout",
+		"\n[http] 000 0.000000 0.000000 0.000000 5.001377\ncurl: (28) Connection timed out after 5001 milliseconds\n",
+		&framework.HTTPProbeResult{
+			Error: "curl: (28) Connection timed out after 5001 milliseconds",
+			Total: 5001377 * time.Microsecond,
+		}),
+	Entry("unresolved name",
+		"\n[http] 000 0.000000 0.000000 0.000000 0.012031\n"+
+			"curl: (6) Could not resolve host: test-svc-http-server.e2e-tests-dataplane.svc.clusterset.local\n",
+		&framework.HTTPProbeResult{
+			Error: "curl: (6) Could not resolve host: test-svc-http-server.e2e-tests-dataplane.svc.clusterset.local",
+			Total: 12031 * time.Microsecond,
+		}),
+)
+
+var _ = DescribeTable("parseHTTPProbeResult errors",
+	func(output string) {
+		_, err := framework.ParseHTTPProbeResult(output)
+		Expect(err).To(HaveOccurred())
+	},
+	Entry("no result line", "sh: curl: not found\n"),
+	Entry("truncated result line", "\n[http] 200 0.000021\n"),
+	Entry("invalid status code", "\n[http] OK 0.000021 0.000412 0.001873 0.001921\n"),
+	Entry("invalid timing", "\n[http] 200 0.000021 0.000412 slow 0.001921\n"),
+)
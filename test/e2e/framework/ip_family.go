@@ -0,0 +1,86 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IPFamilyOf returns the IP family of the given IP, or an empty family if it isn't a valid IP.
+func IPFamilyOf(ip string) corev1.IPFamily {
+	parsed := net.ParseIP(ip)
+
+	switch {
+	case parsed == nil:
+		return ""
+	case parsed.To4() != nil:
+		return corev1.IPv4Protocol
+	default:
+		return corev1.IPv6Protocol
+	}
+}
+
+// IPForFamily returns the first of the given IPs belonging to the given IP family, or the first IP if the family is
+// empty. An empty string is returned if there is no such IP.
+func IPForFamily(ips []string, family corev1.IPFamily) string {
+	for _, ip := range ips {
+		if family == "" || IPFamilyOf(ip) == family {
+			return ip
+		}
+	}
+
+	return ""
+}
+
+// PodIPForFamily returns the IP of the given pod belonging to the given IP family, or its primary IP if the family is
+// empty.
+func PodIPForFamily(pod *corev1.Pod, family corev1.IPFamily) string {
+	ips := []string{}
+	for _, podIP := range pod.Status.PodIPs {
+		ips = append(ips, podIP.IP)
+	}
+
+	if len(ips) == 0 {
+		ips = append(ips, pod.Status.PodIP)
+	}
+
+	return IPForFamily(ips, family)
+}
+
+// ServiceIPForFamily returns the ClusterIP of the given service belonging to the given IP family, or its primary
+// ClusterIP if the family is empty.
+func ServiceIPForFamily(service *corev1.Service, family corev1.IPFamily) string {
+	ips := service.Spec.ClusterIPs
+	if len(ips) == 0 {
+		ips = []string{service.Spec.ClusterIP}
+	}
+
+	return IPForFamily(ips, family)
+}
+
+// anyAddressForFamily returns the address to bind listeners to for the given IP family.
+func anyAddressForFamily(family corev1.IPFamily) string {
+	if family == corev1.IPv6Protocol {
+		return "::"
+	}
+
+	return "0.0.0.0"
+}
@@ -0,0 +1,288 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gcustom"
+	"github.com/onsi/gomega/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// latencyOutputSelectors are the netperf output selectors requested by the LatencyClientPod, in the order of the
+	// LatencyResult fields they're parsed into. The output is kept as it always was for the consumers reading it, with
+	// the percentiles appended only if requested.
+	latencyOutputSelectors           = "min_latency,mean_latency,max_latency,stddev_latency,transaction_rate"
+	latencyPercentileOutputSelectors = "p50_latency,p90_latency,p99_latency"
+)
+
+// LatencyResult is the outcome of the netperf TCP_RR test run by a LatencyClientPod.
+type LatencyResult struct {
+	Min    time.Duration
+	Mean   time.Duration
+	Max    time.Duration
+	StdDev time.Duration
+	// P50, P90 and P99 are only measured if requested by NetworkPodConfig.LatencyPercentiles, zero otherwise.
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	// TransactionRate is the number of request/response transactions per second.
+	TransactionRate float64
+}
+
+// LatencyThresholds are the limits a LatencyResult is expected to be within. Zero values aren't checked.
+type LatencyThresholds struct {
+	MaxMean            time.Duration
+	MaxP50             time.Duration
+	MaxP90             time.Duration
+	MaxP99             time.Duration
+	MinTransactionRate float64
+}
+
+type LatencyTestParams struct {
+	Framework             *Framework
+	FromCluster           ClusterIndex
+	FromClusterScheduling NetworkPodScheduling
+	ToCluster             ClusterIndex
+	ToClusterScheduling   NetworkPodScheduling
+	// Percentiles requests the P50, P90 and P99 latencies. They're always requested if Thresholds has percentile limits.
+	Percentiles bool
+	// Thresholds, if set, are checked against the result.
+	Thresholds LatencyThresholds
+}
+
+// LatencyResult parses the result of the latency test run by this LatencyClientPod, once it has finished.
+func (np *NetworkPod) LatencyResult() *LatencyResult {
+	result, err := np.LatencyResultOrError()
+	Expect(err).NotTo(HaveOccurred())
+
+	return result
+}
+
+// LatencyResultOrError is like LatencyResult but returns an error instead of failing via Gomega.
+func (np *NetworkPod) LatencyResultOrError() (*LatencyResult, error) {
+	return parseLatencyResult(np.TerminationMessage)
+}
+
+// netperfLatencyCommand returns the netperf command run by a LatencyClientPod. netperf only keeps the histogram the
+// percentiles are computed from with the global -j option, it reports them as -1 otherwise.
+func netperfLatencyCommand(percentiles bool) string {
+	globalOptions, selectors := "", latencyOutputSelectors
+	if percentiles {
+		globalOptions, selectors = "-j ", selectors+","+latencyPercentileOutputSelectors
+	}
+
+	return "netperf " + globalOptions + "-H $TARGET_IP -t TCP_RR  -- -o " + selectors
+}
+
+// parseLatencyResult parses the netperf output, whose last line holds the comma-separated values of the
+// latencyOutputSelectors, optionally followed by those of the latencyPercentileOutputSelectors, e.g.
+//
+//	MIGRATED TCP REQUEST/RESPONSE TEST from 0.0.0.0 (0.0.0.0) port 0 AF_INET to 10.1.0.12 () port 0 AF_INET : first burst 0
+//	Minimum Latency Microseconds,Mean Latency Microseconds,...
+//	52,98.33,2305,31.57,10156.67,91,121,203
+func parseLatencyResult(output string) (*LatencyResult, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	values := strings.Split(strings.TrimSpace(lines[len(lines)-1]), ",")
+
+	numSelectors := strings.Count(latencyOutputSelectors, ",") + 1
+	numWithPercentiles := numSelectors + strings.Count(latencyPercentileOutputSelectors, ",") + 1
+
+	if len(values) != numSelectors && len(values) != numWithPercentiles {
+		return nil, fmt.Errorf("the netperf output doesn't end with %d or %d values: %q", numSelectors, numWithPercentiles, output)
+	}
+
+	selectors := strings.Split(latencyOutputSelectors+","+latencyPercentileOutputSelectors, ",")
+	numbers := make([]float64, len(values))
+
+	for i := range values {
+		var err error
+
+		numbers[i], err = strconv.ParseFloat(strings.TrimSpace(values[i]), 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid netperf value %q", values[i])
+		}
+
+		// netperf reports the values it didn't measure as -1.
+		if numbers[i] < 0 {
+			return nil, fmt.Errorf("netperf didn't measure %s, its value is %q", selectors[i], values[i])
+		}
+	}
+
+	microseconds := func(value float64) time.Duration {
+		return time.Duration(value * float64(time.Microsecond))
+	}
+
+	result := &LatencyResult{
+		Min:             microseconds(numbers[0]),
+		Mean:            microseconds(numbers[1]),
+		Max:             microseconds(numbers[2]),
+		StdDev:          microseconds(numbers[3]),
+		TransactionRate: numbers[4],
+	}
+
+	if len(numbers) == numWithPercentiles {
+		result.P50 = microseconds(numbers[5])
+		result.P90 = microseconds(numbers[6])
+		result.P99 = microseconds(numbers[7])
+	}
+
+	return result, nil
+}
+
+// hasPercentiles returns whether the percentiles were measured.
+func (r *LatencyResult) hasPercentiles() bool {
+	return r.P50 != 0 || r.P90 != 0 || r.P99 != 0
+}
+
+// String formats the result for logging.
+func (r *LatencyResult) String() string {
+	percentiles := ""
+	if r.hasPercentiles() {
+		percentiles = fmt.Sprintf(", p50 %v, p90 %v, p99 %v", r.P50, r.P90, r.P99)
+	}
+
+	return fmt.Sprintf("min %v, mean %v, max %v, stddev %v%s, %.2f transactions/s", r.Min, r.Mean, r.Max, r.StdDev, percentiles,
+		r.TransactionRate)
+}
+
+// hasPercentiles returns whether any percentile is limited.
+func (t *LatencyThresholds) hasPercentiles() bool {
+	return t.MaxP50 > 0 || t.MaxP90 > 0 || t.MaxP99 > 0
+}
+
+// Exceeded returns a description of each threshold the result isn't within, if any. Percentile thresholds aren't met
+// by a result without percentiles.
+func (t *LatencyThresholds) Exceeded(r *LatencyResult) []string {
+	exceeded := []string{}
+
+	check := func(name string, value, limit time.Duration) {
+		if limit > 0 && value > limit {
+			exceeded = append(exceeded, fmt.Sprintf("%s latency %v exceeds %v", name, value, limit))
+		}
+	}
+
+	check("mean", r.Mean, t.MaxMean)
+
+	if t.hasPercentiles() && !r.hasPercentiles() {
+		exceeded = append(exceeded, "the percentile latencies weren't measured")
+	}
+
+	check("p50", r.P50, t.MaxP50)
+	check("p90", r.P90, t.MaxP90)
+	check("p99", r.P99, t.MaxP99)
+
+	if t.MinTransactionRate > 0 && r.TransactionRate < t.MinTransactionRate {
+		exceeded = append(exceeded, fmt.Sprintf("transaction rate %.2f/s is below %.2f/s", r.TransactionRate, t.MinTransactionRate))
+	}
+
+	return exceeded
+}
+
+// BeWithinLatencyThresholds succeeds if the actual *LatencyResult is within the given thresholds, e.g.
+//
+//	Expect(result).To(BeWithinLatencyThresholds(LatencyThresholds{MaxP99: 5 * time.Millisecond}))
+func BeWithinLatencyThresholds(thresholds LatencyThresholds) types.GomegaMatcher {
+	return gcustom.MakeMatcher(func(result *LatencyResult) (bool, error) {
+		if result == nil {
+			return false, errors.New("the latency result is nil")
+		}
+
+		return len(thresholds.Exceeded(result)) == 0, nil
+	}).WithTemplate("Expected latency\n{{.FormattedActual}}\n{{.To}} be within the thresholds\n{{format .Data 1}}", thresholds)
+}
+
+// RunLatencyTest runs a netperf TCP_RR test from a LatencyClientPod in FromCluster to a LatencyServerPod in ToCluster and
+// returns the result, after verifying it's within the given thresholds. With Globalnet, the server pod is reached via
+// its global IP when the clusters differ.
+func RunLatencyTest(ctx context.Context, p LatencyTestParams) *LatencyResult {
+	result, err := RunLatencyTestOrError(ctx, p)
+	Expect(err).NotTo(HaveOccurred())
+
+	return result
+}
+
+// RunLatencyTestOrError is like RunLatencyTest but returns an error instead of failing via Gomega. The result is returned
+// even if it isn't within the thresholds.
+func RunLatencyTestOrError(ctx context.Context, p LatencyTestParams) (*LatencyResult, error) {
+	By(fmt.Sprintf("Creating a latency server pod in cluster %q", TestContext.ClusterIDs[p.ToCluster]))
+
+	serverPod, err := p.Framework.NewNetworkPodOrError(ctx, &NetworkPodConfig{
+		Type:       LatencyServerPod,
+		Cluster:    p.ToCluster,
+		Scheduling: p.ToClusterScheduling,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	remoteIP := serverPod.IP()
+
+	if TestContext.GlobalnetEnabled && p.FromCluster != p.ToCluster {
+		By(fmt.Sprintf("Exporting a headless service backed by the latency server pod in cluster %q", TestContext.ClusterIDs[p.ToCluster]))
+
+		remoteIP, err = serverPod.ExportPodOrError(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	By(fmt.Sprintf("Creating a latency client pod in cluster %q, which will run the test against %s",
+		TestContext.ClusterIDs[p.FromCluster], remoteIP))
+
+	clientPod, err := p.Framework.NewNetworkPodOrError(ctx, &NetworkPodConfig{
+		Type:               LatencyClientPod,
+		Cluster:            p.FromCluster,
+		Scheduling:         p.FromClusterScheduling,
+		RemoteIP:           remoteIP,
+		LatencyPercentiles: p.Percentiles || p.Thresholds.hasPercentiles(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	By(fmt.Sprintf("Waiting for the latency client pod %q to exit, returning the result", clientPod.Pod.Name))
+	clientPod.AwaitFinish(ctx)
+
+	if err := clientPod.CheckSuccessfulFinishOrError(); err != nil {
+		return nil, err
+	}
+
+	result, err := clientPod.LatencyResultOrError()
+	if err != nil {
+		return nil, err
+	}
+
+	Logf("Latency from cluster %q to cluster %q: %s", TestContext.ClusterIDs[p.FromCluster], TestContext.ClusterIDs[p.ToCluster],
+		result)
+
+	if exceeded := p.Thresholds.Exceeded(result); len(exceeded) > 0 {
+		return result, fmt.Errorf("the latency from cluster %q to cluster %q isn't within the thresholds: %s",
+			TestContext.ClusterIDs[p.FromCluster], TestContext.ClusterIDs[p.ToCluster], strings.Join(exceeded, ", "))
+	}
+
+	return result, nil
+}
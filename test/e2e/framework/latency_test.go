@@ -0,0 +1,101 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+)
+
+var _ = Describe("parseLatencyResult", func() {
+	It("should parse the netperf output", func() {
+		Expect(framework.ParseLatencyResult(readTestData("netperf.txt"))).To(Equal(&framework.LatencyResult{
+			Min:             52 * time.Microsecond,
+			Mean:            98330 * time.Nanosecond,
+			Max:             2305 * time.Microsecond,
+			StdDev:          31570 * time.Nanosecond,
+			TransactionRate: 10156.67,
+		}))
+	})
+
+	It("should parse the netperf output with the percentiles", func() {
+		Expect(framework.ParseLatencyResult(readTestData("netperf-percentiles.txt"))).To(Equal(&framework.LatencyResult{
+			Min:             241 * time.Microsecond,
+			Mean:            412870 * time.Nanosecond,
+			Max:             8815 * time.Microsecond,
+			StdDev:          163040 * time.Nanosecond,
+			P50:             377 * time.Microsecond,
+			P90:             519 * time.Microsecond,
+			P99:             1021 * time.Microsecond,
+			TransactionRate: 2421.617,
+		}))
+	})
+
+	DescribeTable("should return an error",
+		func(output, expected string) {
+			_, err := framework.ParseLatencyResult(output)
+			Expect(err).To(MatchError(ContainSubstring(expected)))
+		},
+		Entry("if netperf failed", readTestData("netperf-error.txt"), "doesn't end with 5 or 8 values"),
+		Entry("if the output is empty", "", "doesn't end with 5 or 8 values"),
+		Entry("if a value isn't a number", "52,98.33,2305,-nan,10156.670\n", `invalid netperf value "-nan"`),
+		Entry("if the percentiles weren't measured, without the -j option", "52,98.33,2305,31.57,10156.670,-1,-1,-1\n",
+			`netperf didn't measure p50_latency, its value is "-1"`),
+		Entry("if a value is negative", "52,-98.33,2305,31.57,10156.670\n", `netperf didn't measure mean_latency, its value is "-98.33"`),
+	)
+})
+
+var _ = DescribeTable("netperfLatencyCommand",
+	func(percentiles bool, expected string) {
+		Expect(framework.NetperfLatencyCommand(percentiles)).To(Equal(expected))
+	},
+	Entry("without the percentiles", false,
+		"netperf -H $TARGET_IP -t TCP_RR  -- -o min_latency,mean_latency,max_latency,stddev_latency,transaction_rate"),
+	Entry("with the percentiles, which require the histogram", true,
+		"netperf -j -H $TARGET_IP -t TCP_RR  -- -o min_latency,mean_latency,max_latency,stddev_latency,transaction_rate,"+
+			"p50_latency,p90_latency,p99_latency"),
+)
+
+var _ = Describe("LatencyThresholds", func() {
+	result := &framework.LatencyResult{
+		Mean:            100 * time.Microsecond,
+		P50:             90 * time.Microsecond,
+		P90:             120 * time.Microsecond,
+		P99:             200 * time.Microsecond,
+		TransactionRate: 10000,
+	}
+
+	DescribeTable("Exceeded",
+		func(thresholds framework.LatencyThresholds, result *framework.LatencyResult, expected []string) {
+			Expect(thresholds.Exceeded(result)).To(Equal(expected))
+		},
+		Entry("with no thresholds", framework.LatencyThresholds{}, result, []string{}),
+		Entry("within the thresholds", framework.LatencyThresholds{
+			MaxMean: 100 * time.Microsecond, MaxP99: 200 * time.Microsecond, MinTransactionRate: 10000,
+		}, result, []string{}),
+		Entry("exceeding the thresholds", framework.LatencyThresholds{
+			MaxMean: 99 * time.Microsecond, MaxP90: 100 * time.Microsecond,
+		}, result, []string{"mean latency 100µs exceeds 99µs", "p90 latency 120µs exceeds 100µs"}),
+		Entry("with percentile thresholds but no measured percentiles", framework.LatencyThresholds{MaxP50: time.Millisecond},
+			&framework.LatencyResult{Mean: 100 * time.Microsecond}, []string{"the percentile latencies weren't measured"}),
+	)
+})
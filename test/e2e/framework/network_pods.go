@@ -28,11 +28,13 @@ import (
 	"strings"
 
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/kubernetes/scheme"
-	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/utils/ptr"
 )
 
 type NetworkingType bool
@@ -54,6 +56,31 @@ const (
 	LatencyClientPod
 	LatencyServerPod
 	CustomPod
+	UDPListenerPod
+	UDPConnectorPod
+	SCTPListenerPod
+	SCTPConnectorPod
+	HTTPServerPod
+	HTTPClientPod
+)
+
+// ErrSCTPNotSupported is returned when creating an SCTP listener pod on a node whose kernel doesn't support SCTP.
+var ErrSCTPNotSupported = errors.New("SCTP is not supported")
+
+const sctpNotSupportedMessage = "[dataplane] SCTP is not supported"
+
+// EndpointType is the kind of address at which a network pod is reached by the pods connecting to it.
+type EndpointType int
+
+const (
+	PodIPEndpoint EndpointType = iota
+	ServiceIPEndpoint
+	// GlobalServiceIPEndpoint is the Globalnet IP allocated to the exported service pointing to the pod.
+	GlobalServiceIPEndpoint
+	// GlobalPodIPEndpoint is the Globalnet IP allocated to the pod, as a backend of an exported headless service.
+	GlobalPodIPEndpoint
+	// ClustersetServiceNameEndpoint is the clusterset DNS name of the exported service pointing to the pod.
+	ClustersetServiceNameEndpoint
 )
 
 type NetworkPodScheduling int
@@ -78,6 +105,12 @@ type NetworkPodConfig struct {
 	ContainerName      string
 	ImageName          string
 	Command            []string
+	// IPFamily is the IP family used by the pod to communicate, its primary family if empty. It determines the address
+	// listeners bind to and the addresses returned by IP and used by CreateService.
+	IPFamily v1.IPFamily
+	// LatencyPercentiles makes a LatencyClientPod also measure the P50, P90 and P99 latencies, which are appended to its
+	// output.
+	LatencyPercentiles bool
 	// TODO: namespace, once https://github.com/submariner-io/submariner/pull/141 is merged
 }
 
@@ -95,10 +128,23 @@ const (
 	TestPort = 1234
 )
 
-func (f *Framework) NewNetworkPod(config *NetworkPodConfig) *NetworkPod {
+func (f *Framework) NewNetworkPod(ctx context.Context, config *NetworkPodConfig) *NetworkPod {
+	networkPod, err := f.NewNetworkPodOrError(ctx, config)
+	Expect(err).NotTo(HaveOccurred())
+
+	return networkPod
+}
+
+// NewNetworkPodOrError is like NewNetworkPod but returns an error instead of failing via Gomega.
+func (f *Framework) NewNetworkPodOrError(ctx context.Context, config *NetworkPodConfig) (*NetworkPod, error) {
 	// check if all necessary details are provided
-	Expect(config.Scheduling).ShouldNot(Equal(InvalidScheduling))
-	Expect(config.Type).ShouldNot(Equal(InvalidPodType))
+	if config.Scheduling == InvalidScheduling {
+		return nil, errors.New("the network pod Scheduling must be specified")
+	}
+
+	if config.Type == InvalidPodType {
+		return nil, errors.New("the network pod Type must be specified")
+	}
 
 	// setup unset defaults
 	if config.Port == 0 {
@@ -117,33 +163,56 @@ func (f *Framework) NewNetworkPod(config *NetworkPodConfig) *NetworkPod {
 
 	networkPod := &NetworkPod{Config: config, framework: f, TerminationCode: -1}
 
+	var err error
+
 	switch config.Type {
 	case ListenerPod:
-		networkPod.buildTCPCheckListenerPod()
+		err = networkPod.buildTCPCheckListenerPod(ctx)
 	case ConnectorPod:
-		networkPod.buildTCPCheckConnectorPod()
+		err = networkPod.buildTCPCheckConnectorPod(ctx)
 	case ThroughputClientPod:
-		networkPod.buildThroughputClientPod()
+		err = networkPod.buildThroughputClientPod(ctx)
 	case ThroughputServerPod:
-		networkPod.buildThroughputServerPod()
+		err = networkPod.buildThroughputServerPod(ctx)
 	case LatencyClientPod:
-		networkPod.buildLatencyClientPod()
+		err = networkPod.buildLatencyClientPod(ctx)
 	case LatencyServerPod:
-		networkPod.buildLatencyServerPod()
+		err = networkPod.buildLatencyServerPod(ctx)
 	case CustomPod:
-		networkPod.buildCustomPod()
+		err = networkPod.buildCustomPod(ctx)
+	case UDPListenerPod:
+		err = networkPod.buildUDPCheckListenerPod(ctx)
+	case UDPConnectorPod:
+		err = networkPod.buildUDPCheckConnectorPod(ctx)
+	case SCTPListenerPod:
+		err = networkPod.buildSCTPCheckListenerPod(ctx)
+	case SCTPConnectorPod:
+		err = networkPod.buildSCTPCheckConnectorPod(ctx)
+	case HTTPServerPod:
+		err = networkPod.buildHTTPServerPod(ctx)
+	case HTTPClientPod:
+		err = networkPod.buildHTTPClientPod(ctx)
 	case InvalidPodType:
 		panic("config.Type can't equal InvalidPodType here, we checked above")
 	}
 
-	return networkPod
+	if err != nil {
+		return nil, err
+	}
+
+	return networkPod, nil
 }
 
-func (np *NetworkPod) AwaitReady() {
+func (np *NetworkPod) AwaitReady(ctx context.Context) {
+	Expect(np.AwaitReadyOrError(ctx)).To(Succeed())
+}
+
+// AwaitReadyOrError is like AwaitReady but returns an error instead of failing via Gomega.
+func (np *NetworkPod) AwaitReadyOrError(ctx context.Context) error {
 	pods := KubeClients[np.Config.Cluster].CoreV1().Pods(np.framework.Namespace)
 
-	np.Pod = AwaitUntil("await pod ready", func() (interface{}, error) {
-		return pods.Get(context.TODO(), np.Pod.Name, metav1.GetOptions{})
+	result, err := awaitUntilOrError(ctx, "await pod ready", func() (interface{}, error) {
+		return pods.Get(ctx, np.Pod.Name, metav1.GetOptions{})
 	}, func(result interface{}) (bool, string, error) {
 		pod := result.(*v1.Pod)
 		if pod.Status.Phase != v1.PodRunning {
@@ -157,19 +226,26 @@ func (np *NetworkPod) AwaitReady() {
 		}
 
 		return true, "", nil // pod is running
-	}).(*v1.Pod)
+	})
+	if err != nil {
+		return err
+	}
+
+	np.Pod = result.(*v1.Pod)
+
+	return nil
 }
 
-func (np *NetworkPod) AwaitFinish() {
-	np.AwaitFinishVerbose(true)
+func (np *NetworkPod) AwaitFinish(ctx context.Context) {
+	np.AwaitFinishVerbose(ctx, true)
 }
 
-func (np *NetworkPod) AwaitFinishVerbose(verbose bool) {
+func (np *NetworkPod) AwaitFinishVerbose(ctx context.Context, verbose bool) {
 	pods := KubeClients[np.Config.Cluster].CoreV1().Pods(np.framework.Namespace)
 
-	_, np.TerminationErrorMsg, np.TerminationError = AwaitResultOrError(fmt.Sprintf("await pod %q finished", np.Pod.Name),
+	_, np.TerminationErrorMsg, np.TerminationError = AwaitResultOrError(ctx, fmt.Sprintf("await pod %q finished", np.Pod.Name),
 		func() (interface{}, error) {
-			return pods.Get(context.TODO(), np.Pod.Name, metav1.GetOptions{})
+			return pods.Get(ctx, np.Pod.Name, metav1.GetOptions{})
 		}, func(result interface{}) (bool, string, error) {
 			np.Pod = result.(*v1.Pod)
 
@@ -196,12 +272,225 @@ func (np *NetworkPod) CheckSuccessfulFinish() {
 	Expect(np.TerminationCode).To(Equal(int32(0)))
 }
 
-func (np *NetworkPod) CreateService() *v1.Service {
-	return np.framework.CreateTCPService(np.Config.Cluster, np.Pod.Labels[TestAppLabel], np.Config.Port)
+// CheckSuccessfulFinishOrError is like CheckSuccessfulFinish but returns an error instead of failing via Gomega.
+func (np *NetworkPod) CheckSuccessfulFinishOrError() error {
+	if np.TerminationError != nil {
+		return errors.WithMessage(np.TerminationError, np.TerminationErrorMsg)
+	}
+
+	if np.TerminationCode != 0 {
+		return fmt.Errorf("pod %q terminated with exit code %d", np.Pod.Name, np.TerminationCode)
+	}
+
+	return nil
+}
+
+// IP returns the IP of the pod belonging to the configured IP family, or its primary IP if no family is configured.
+func (np *NetworkPod) IP() string {
+	return PodIPForFamily(np.Pod, np.Config.IPFamily)
+}
+
+func (np *NetworkPod) CreateService(ctx context.Context) *v1.Service {
+	service, err := np.CreateServiceOrError(ctx)
+	Expect(err).NotTo(HaveOccurred())
+
+	return service
+}
+
+// CreateServiceOrError is like CreateService but returns an error instead of failing via Gomega.
+func (np *NetworkPod) CreateServiceOrError(ctx context.Context) (*v1.Service, error) {
+	return np.framework.createTestAppServiceOrError(ctx, np.Config.Cluster, np.Pod.Labels[TestAppLabel], np.Config.Port,
+		np.protocol(), np.Config.IPFamily, false)
+}
+
+// ExportServiceOrError creates a service pointing to the pod, exports it and returns the Globalnet ingress IP allocated
+// to the service, or an empty string if Globalnet isn't enabled.
+func (np *NetworkPod) ExportServiceOrError(ctx context.Context) (string, error) {
+	service, err := np.CreateServiceOrError(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if err := np.framework.CreateServiceExportOrError(ctx, np.Config.Cluster, service.Name); err != nil {
+		return "", err
+	}
+
+	return np.framework.AwaitGlobalIngressIPOrError(ctx, np.Config.Cluster, service.Name, service.Namespace)
+}
+
+// ExportPodOrError creates a headless service backed by the pod, exports it and returns the Globalnet ingress IP
+// allocated to the pod, or an empty string if Globalnet isn't enabled.
+func (np *NetworkPod) ExportPodOrError(ctx context.Context) (string, error) {
+	service, err := np.framework.createTestAppServiceOrError(ctx, np.Config.Cluster, np.Pod.Labels[TestAppLabel], np.Config.Port,
+		np.protocol(), np.Config.IPFamily, true)
+	if err != nil {
+		return "", err
+	}
+
+	if err := np.framework.CreateServiceExportOrError(ctx, np.Config.Cluster, service.Name); err != nil {
+		return "", err
+	}
+
+	// Globalnet allocates an ingress IP named after each backend pod of an exported headless service.
+	return np.framework.AwaitGlobalIngressIPOrError(ctx, np.Config.Cluster, "pod-"+np.Pod.Name, np.Pod.Namespace)
+}
+
+// RemoteAddressOrError creates the resources needed to reach the pod at the given type of endpoint, e.g. a service, and
+// returns the address to connect to, in the pod's configured IP family.
+func (np *NetworkPod) RemoteAddressOrError(ctx context.Context, endpointType EndpointType) (string, error) {
+	cluster := TestContext.ClusterIDs[np.Config.Cluster]
+
+	var (
+		address string
+		err     error
+	)
+
+	switch endpointType {
+	case PodIPEndpoint:
+		address = np.IP()
+	case ServiceIPEndpoint:
+		By(fmt.Sprintf("Pointing a service ClusterIP to pod %q in cluster %q", np.Pod.Name, cluster))
+
+		var service *v1.Service
+
+		service, err = np.CreateServiceOrError(ctx)
+		if err == nil {
+			address = ServiceIPForFamily(service, np.Config.IPFamily)
+		}
+	case GlobalServiceIPEndpoint:
+		By(fmt.Sprintf("Exporting a service pointing to pod %q in cluster %q", np.Pod.Name, cluster))
+
+		address, err = np.ExportServiceOrError(ctx)
+	case GlobalPodIPEndpoint:
+		By(fmt.Sprintf("Exporting a headless service backed by pod %q in cluster %q", np.Pod.Name, cluster))
+
+		address, err = np.ExportPodOrError(ctx)
+	case ClustersetServiceNameEndpoint:
+		By(fmt.Sprintf("Exporting a service pointing to pod %q in cluster %q", np.Pod.Name, cluster))
+
+		var service *v1.Service
+
+		service, err = np.CreateServiceOrError(ctx)
+		if err == nil {
+			err = np.framework.CreateServiceExportOrError(ctx, np.Config.Cluster, service.Name)
+			address = ClustersetServiceDNSName(service.Name, service.Namespace)
+		}
+	default:
+		return "", fmt.Errorf("unsupported endpoint type %d", endpointType)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if address == "" {
+		return "", fmt.Errorf("no %s address to connect to was found for pod %q", np.Config.IPFamily, np.Pod.Name)
+	}
+
+	return address, nil
+}
+
+// VerifyDataExchangeOrError verifies that the given listener and connector pods finished successfully after receiving
+// each other's data and, if verifySourceIP is true, that the listener saw the connector's IP as the source IP.
+func VerifyDataExchangeOrError(listenerPod, connectorPod *NetworkPod, verifySourceIP bool) error {
+	if err := listenerPod.CheckSuccessfulFinishOrError(); err != nil {
+		return err
+	}
+
+	if err := connectorPod.CheckSuccessfulFinishOrError(); err != nil {
+		return err
+	}
+
+	By("Verifying that the listener got the connector's data and the connector got the listener's data")
+
+	if !strings.Contains(listenerPod.TerminationMessage, connectorPod.Config.Data) {
+		return fmt.Errorf("listener pod %q did not receive the connector's data %q", listenerPod.Pod.Name, connectorPod.Config.Data)
+	}
+
+	if !strings.Contains(connectorPod.TerminationMessage, listenerPod.Config.Data) {
+		return fmt.Errorf("connector pod %q did not receive the listener's data %q", connectorPod.Pod.Name, listenerPod.Config.Data)
+	}
+
+	if verifySourceIP {
+		By("Verifying the output of listener pod which must contain the source IP")
+
+		if !strings.Contains(listenerPod.TerminationMessage, connectorPod.IP()) {
+			return fmt.Errorf("listener pod %q output does not contain the connector's source IP %q", listenerPod.Pod.Name,
+				connectorPod.IP())
+		}
+	}
+
+	return nil
+}
+
+// Delete deletes the pod, along with the service and the ServiceExport created to reach it, if any, and waits for the pod
+// to be gone. Pods of the same type share their labels, so this prevents the services of the pods created afterwards
+// from selecting this one.
+func (np *NetworkPod) Delete(ctx context.Context) {
+	Expect(np.DeleteOrError(ctx)).To(Succeed())
+}
+
+// DeleteOrError is like Delete but returns an error instead of failing via Gomega.
+func (np *NetworkPod) DeleteOrError(ctx context.Context) error {
+	cluster := TestContext.ClusterIDs[np.Config.Cluster]
+	serviceName := testAppServiceName(np.Pod.Labels[TestAppLabel])
+
+	err := DynClients[np.Config.Cluster].Resource(gvr).Namespace(np.framework.Namespace).Delete(ctx, serviceName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "error deleting ServiceExport %q on cluster %q", serviceName, cluster)
+	}
+
+	err = KubeClients[np.Config.Cluster].CoreV1().Services(np.framework.Namespace).Delete(ctx, serviceName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "error deleting service %q on cluster %q", serviceName, cluster)
+	}
+
+	pods := KubeClients[np.Config.Cluster].CoreV1().Pods(np.framework.Namespace)
+
+	err = pods.Delete(ctx, np.Pod.Name, metav1.DeleteOptions{GracePeriodSeconds: ptr.To(int64(0))})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "error deleting pod %q on cluster %q", np.Pod.Name, cluster)
+	}
+
+	_, err = awaitUntilOrError(ctx, fmt.Sprintf("await pod %q deleted on cluster %q", np.Pod.Name, cluster), func() (interface{}, error) {
+		_, err := pods.Get(ctx, np.Pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return err == nil, err
+	}, func(result interface{}) (bool, string, error) {
+		if result.(bool) {
+			return false, "Pod still exists", nil
+		}
+
+		return true, "", nil
+	})
+
+	return err
+}
+
+func (np *NetworkPod) protocol() v1.Protocol {
+	switch np.Config.Type {
+	case UDPListenerPod, UDPConnectorPod:
+		return v1.ProtocolUDP
+	case SCTPListenerPod, SCTPConnectorPod:
+		return v1.ProtocolSCTP
+	default:
+		return v1.ProtocolTCP
+	}
 }
 
 // RunCommand run the specified command in this NetworkPod.
 func (np *NetworkPod) RunCommand(ctx context.Context, cmd []string) (string, string) {
+	stdout, stderr, err := np.RunCommandOrError(ctx, cmd)
+	Expect(err).NotTo(HaveOccurred())
+
+	return stdout, stderr
+}
+
+// RunCommandOrError is like RunCommand but returns an error instead of failing via Gomega.
+func (np *NetworkPod) RunCommandOrError(ctx context.Context, cmd []string) (string, string, error) {
 	req := KubeClients[np.Config.Cluster].CoreV1().RESTClient().Post().
 		Resource("pods").Name(np.Pod.Name).Namespace(np.Pod.Namespace).
 		SubResource("exec").Param("container", np.Config.ContainerName)
@@ -215,47 +504,52 @@ func (np *NetworkPod) RunCommand(ctx context.Context, cmd []string) (string, str
 		TTY:       false,
 	}, scheme.ParameterCodec)
 
-	Expect(req.Error()).NotTo(HaveOccurred())
+	if err := req.Error(); err != nil {
+		return "", "", errors.Wrap(err, "error building the exec request")
+	}
 
-	config := RestConfigs[np.Config.Cluster]
+	var stdout, stderr bytes.Buffer
 
-	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
-	Expect(err).NotTo(HaveOccurred())
+	err := execute(ctx, "POST", req.URL(), RestConfigs[np.Config.Cluster], nil, &stdout, &stderr, false)
 
-	var stdout, stderr bytes.Buffer
+	return stdout.String(), stderr.String(), errors.Wrapf(err, "error running command %v in pod %q", cmd, np.Pod.Name)
+}
 
-	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
-		Stdin:  nil,
-		Stdout: &stdout,
-		Stderr: &stderr,
-		Tty:    false,
-	})
+// GetLog returns container log from this NetworkPod.
+func (np *NetworkPod) GetLog(ctx context.Context) string {
+	out, err := np.GetLogOrError(ctx)
 	Expect(err).NotTo(HaveOccurred())
 
-	return stdout.String(), stderr.String()
+	return out
 }
 
-// GetLog returns container log from this NetworkPod.
-func (np *NetworkPod) GetLog() string {
+// GetLogOrError is like GetLog but returns an error instead of failing via Gomega.
+func (np *NetworkPod) GetLogOrError(ctx context.Context) (string, error) {
 	req := KubeClients[np.Config.Cluster].CoreV1().Pods(np.Pod.Namespace).GetLogs(np.Pod.Name, &v1.PodLogOptions{})
 
-	closer, err := req.Stream(context.TODO())
-	Expect(err).NotTo(HaveOccurred())
+	closer, err := req.Stream(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "error streaming the log of pod %q", np.Pod.Name)
+	}
 
 	defer closer.Close()
 
 	out := new(strings.Builder)
 
 	_, err = io.Copy(out, closer)
-	Expect(err).NotTo(HaveOccurred())
 
-	return out.String()
+	return out.String(), errors.Wrapf(err, "error reading the log of pod %q", np.Pod.Name)
 }
 
 // create a test pod inside the current test namespace on the specified cluster.
 // The pod will listen on TestPort over TCP, send sendString over the connection,
 // and write the network response in the pod  termination log, then exit with 0 status.
-func (np *NetworkPod) buildTCPCheckListenerPod() {
+func (np *NetworkPod) buildTCPCheckListenerPod(ctx context.Context) error {
+	affinity, err := np.nodeAffinity(ctx, np.Config.Scheduling)
+	if err != nil {
+		return err
+	}
+
 	tcpCheckListenerPod := v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "tcp-check-listener",
@@ -264,7 +558,7 @@ func (np *NetworkPod) buildTCPCheckListenerPod() {
 			},
 		},
 		Spec: v1.PodSpec{
-			Affinity:      np.nodeAffinity(np.Config.Scheduling),
+			Affinity:      affinity,
 			RestartPolicy: v1.RestartPolicyNever,
 			Containers: []v1.Container{
 				{
@@ -278,10 +572,11 @@ func (np *NetworkPod) buildTCPCheckListenerPod() {
 						"for i in $(seq 1 $BUFS_NUM);" +
 							" do echo [dataplane] listener says $SEND_STRING;" +
 							" done" +
-							" | nc -w $CONN_TIMEOUT -l -v -p $LISTEN_PORT -s 0.0.0.0 >/dev/termination-log 2>&1",
+							" | busybox nc -w $CONN_TIMEOUT -l -v -p $LISTEN_PORT -s $LISTEN_ADDRESS >/dev/termination-log 2>&1",
 					},
 					Env: []v1.EnvVar{
 						{Name: "LISTEN_PORT", Value: strconv.FormatInt(int64(np.Config.Port), 10)},
+						{Name: "LISTEN_ADDRESS", Value: anyAddressForFamily(np.Config.IPFamily)},
 						{Name: "SEND_STRING", Value: np.Config.Data},
 						{Name: "CONN_TIMEOUT", Value: strconv.FormatUint(uint64(np.Config.ConnectionTimeout*np.Config.ConnectionAttempts), 10)},
 						{Name: "BUFS_NUM", Value: strconv.FormatUint(uint64(np.Config.NumOfDataBufs), 10)},
@@ -293,18 +588,23 @@ func (np *NetworkPod) buildTCPCheckListenerPod() {
 		},
 	}
 
-	pc := KubeClients[np.Config.Cluster].CoreV1().Pods(np.framework.Namespace)
-	var err error
-	np.Pod, err = pc.Create(context.TODO(), &tcpCheckListenerPod, metav1.CreateOptions{})
-	Expect(err).NotTo(HaveOccurred())
-	np.AwaitReady()
+	if err := np.create(ctx, &tcpCheckListenerPod); err != nil {
+		return err
+	}
+
+	return np.AwaitReadyOrError(ctx)
 }
 
 // create a test pod inside the current test namespace on the specified cluster.
 // The pod will connect to remoteIP:TestPort over TCP, send sendString over the
 // connection, and write the network response in the pod termination log, then
 // exit with 0 status.
-func (np *NetworkPod) buildTCPCheckConnectorPod() {
+func (np *NetworkPod) buildTCPCheckConnectorPod(ctx context.Context) error {
+	affinity, err := np.nodeAffinity(ctx, np.Config.Scheduling)
+	if err != nil {
+		return err
+	}
+
 	tcpCheckConnectorPod := v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "tcp-check-pod",
@@ -313,7 +613,7 @@ func (np *NetworkPod) buildTCPCheckConnectorPod() {
 			},
 		},
 		Spec: v1.PodSpec{
-			Affinity:      np.nodeAffinity(np.Config.Scheduling),
+			Affinity:      affinity,
 			RestartPolicy: v1.RestartPolicyNever,
 			HostNetwork:   bool(np.Config.Networking),
 			Containers: []v1.Container{
@@ -328,7 +628,7 @@ func (np *NetworkPod) buildTCPCheckConnectorPod() {
 						"for in in $(seq 1 $BUFS_NUM);" +
 							" do echo [dataplane] connector says $SEND_STRING; done" +
 							" | for i in $(seq $CONN_TRIES);" +
-							" do if nc -v $REMOTE_IP $REMOTE_PORT -w $CONN_TIMEOUT;" +
+							" do if busybox nc -v $REMOTE_IP $REMOTE_PORT -w $CONN_TIMEOUT;" +
 							" then break;" +
 							" else sleep $RETRY_SLEEP;" +
 							" fi; done >/dev/termination-log 2>&1",
@@ -349,17 +649,278 @@ func (np *NetworkPod) buildTCPCheckConnectorPod() {
 		},
 	}
 
-	pc := KubeClients[np.Config.Cluster].CoreV1().Pods(np.framework.Namespace)
-	var err error
-	np.Pod, err = pc.Create(context.TODO(), &tcpCheckConnectorPod, metav1.CreateOptions{})
-	Expect(err).NotTo(HaveOccurred())
+	return np.create(ctx, &tcpCheckConnectorPod)
 }
 
 // create a test pod inside the current test namespace on the specified cluster.
-// The pod will initiate iperf3 throughput test to remoteIP and write the test
-// response in the pod termination log, then
+// The pod will listen on TestPort over UDP, send sendString to the first peer that
+// sends it a datagram, and write the network response in the pod termination log,
+// then exit with 0 status.
+func (np *NetworkPod) buildUDPCheckListenerPod(ctx context.Context) error {
+	affinity, err := np.nodeAffinity(ctx, np.Config.Scheduling)
+	if err != nil {
+		return err
+	}
+
+	udpCheckListenerPod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "udp-check-listener",
+			Labels: map[string]string{
+				TestAppLabel: "udp-check-listener",
+			},
+		},
+		Spec: v1.PodSpec{
+			Affinity:      affinity,
+			RestartPolicy: v1.RestartPolicyNever,
+			Containers: []v1.Container{
+				{
+					Name:  "udp-check-listener",
+					Image: TestContext.NettestImageURL,
+					// Each line is sent in its own datagram, so sending the string several times makes it likely that
+					// at least some of the data gets through.
+					Command: []string{
+						"sh",
+						"-c",
+						"for i in $(seq 1 $BUFS_NUM);" +
+							" do echo [dataplane] listener says $SEND_STRING;" +
+							" done" +
+							" | busybox nc -u -w $CONN_TIMEOUT -l -v -p $LISTEN_PORT -s $LISTEN_ADDRESS >/dev/termination-log 2>&1",
+					},
+					Env: []v1.EnvVar{
+						{Name: "LISTEN_PORT", Value: strconv.FormatInt(int64(np.Config.Port), 10)},
+						{Name: "LISTEN_ADDRESS", Value: anyAddressForFamily(np.Config.IPFamily)},
+						{Name: "SEND_STRING", Value: np.Config.Data},
+						{Name: "CONN_TIMEOUT", Value: strconv.FormatUint(uint64(np.Config.ConnectionTimeout*np.Config.ConnectionAttempts), 10)},
+						{Name: "BUFS_NUM", Value: strconv.FormatUint(uint64(np.Config.NumOfDataBufs), 10)},
+					},
+					SecurityContext: podSecurityContext,
+				},
+			},
+			Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
+		},
+	}
+
+	if err := np.create(ctx, &udpCheckListenerPod); err != nil {
+		return err
+	}
+
+	return np.AwaitReadyOrError(ctx)
+}
+
+// create a test pod inside the current test namespace on the specified cluster.
+// The pod will send sendString to remoteIP:TestPort over UDP, and write the network
+// response in the pod termination log, then exit with 0 status. Since UDP doesn't
+// report connection failures, the datagrams are sent again until a response is
+// received or the attempts are exhausted.
+func (np *NetworkPod) buildUDPCheckConnectorPod(ctx context.Context) error {
+	affinity, err := np.nodeAffinity(ctx, np.Config.Scheduling)
+	if err != nil {
+		return err
+	}
+
+	udpCheckConnectorPod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "udp-check-pod",
+			Labels: map[string]string{
+				TestAppLabel: "udp-check-pod",
+			},
+		},
+		Spec: v1.PodSpec{
+			Affinity:      affinity,
+			RestartPolicy: v1.RestartPolicyNever,
+			HostNetwork:   bool(np.Config.Networking),
+			Containers: []v1.Container{
+				{
+					Name:  "udp-check-connector",
+					Image: TestContext.NettestImageURL,
+					Command: []string{
+						"sh",
+						"-c",
+						"for i in $(seq $CONN_TRIES);" +
+							" do for j in $(seq 1 $BUFS_NUM);" +
+							" do echo [dataplane] connector says $SEND_STRING; done" +
+							" | busybox nc -u -v -w $CONN_TIMEOUT $REMOTE_IP $REMOTE_PORT >/tmp/udp-check.log 2>&1;" +
+							" cat /tmp/udp-check.log >>/dev/termination-log;" +
+							" if grep -q 'listener says' /tmp/udp-check.log;" +
+							" then break;" +
+							" else sleep $RETRY_SLEEP;" +
+							" fi; done",
+					},
+					Env: []v1.EnvVar{
+						{Name: "REMOTE_PORT", Value: strconv.FormatInt(int64(np.Config.Port), 10)},
+						{Name: "SEND_STRING", Value: np.Config.Data},
+						{Name: "REMOTE_IP", Value: np.Config.RemoteIP},
+						{Name: "CONN_TRIES", Value: strconv.FormatUint(uint64(np.Config.ConnectionAttempts), 10)},
+						{Name: "CONN_TIMEOUT", Value: strconv.FormatUint(uint64(np.Config.ConnectionTimeout), 10)},
+						{Name: "RETRY_SLEEP", Value: strconv.FormatUint(uint64(np.Config.ConnectionTimeout/2), 10)},
+						{Name: "BUFS_NUM", Value: strconv.FormatUint(uint64(np.Config.NumOfDataBufs), 10)},
+					},
+					SecurityContext: podSecurityContext,
+				},
+			},
+			Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
+		},
+	}
+
+	return np.create(ctx, &udpCheckConnectorPod)
+}
+
+// create a test pod inside the current test namespace on the specified cluster.
+// The pod will listen on TestPort over SCTP, send sendString over the association,
+// and write the network response in the pod termination log, then exit with 0 status.
+// ErrSCTPNotSupported is returned if the node doesn't support SCTP.
+func (np *NetworkPod) buildSCTPCheckListenerPod(ctx context.Context) error {
+	affinity, err := np.nodeAffinity(ctx, np.Config.Scheduling)
+	if err != nil {
+		return err
+	}
+
+	sctpCheckListenerPod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "sctp-check-listener",
+			Labels: map[string]string{
+				TestAppLabel: "sctp-check-listener",
+			},
+		},
+		Spec: v1.PodSpec{
+			Affinity:      affinity,
+			RestartPolicy: v1.RestartPolicyNever,
+			Containers: []v1.Container{
+				{
+					Name:  "sctp-check-listener",
+					Image: TestContext.NettestImageURL,
+					Command: []string{
+						"sh",
+						"-c",
+						"for i in $(seq 1 $BUFS_NUM);" +
+							" do echo [dataplane] listener says $SEND_STRING;" +
+							" done" +
+							" | timeout $CONN_TIMEOUT ncat --sctp -l -v $LISTEN_ADDRESS $LISTEN_PORT >/dev/termination-log 2>&1;" +
+							" rc=$?;" +
+							" if grep -q 'Protocol not supported' /dev/termination-log;" +
+							" then echo $NOT_SUPPORTED >>/dev/termination-log;" +
+							" fi; exit $rc",
+					},
+					Env: []v1.EnvVar{
+						{Name: "LISTEN_PORT", Value: strconv.FormatInt(int64(np.Config.Port), 10)},
+						{Name: "LISTEN_ADDRESS", Value: anyAddressForFamily(np.Config.IPFamily)},
+						{Name: "SEND_STRING", Value: np.Config.Data},
+						{Name: "CONN_TIMEOUT", Value: strconv.FormatUint(uint64(np.Config.ConnectionTimeout*np.Config.ConnectionAttempts), 10)},
+						{Name: "BUFS_NUM", Value: strconv.FormatUint(uint64(np.Config.NumOfDataBufs), 10)},
+						{Name: "NOT_SUPPORTED", Value: sctpNotSupportedMessage},
+					},
+					SecurityContext: podSecurityContext,
+				},
+			},
+			Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
+		},
+	}
+
+	if err := np.create(ctx, &sctpCheckListenerPod); err != nil {
+		return err
+	}
+
+	err = np.AwaitReadyOrError(ctx)
+	if err != nil && np.sctpNotSupported(ctx) {
+		return np.sctpNotSupportedError()
+	}
+
+	return err
+}
+
+// sctpNotSupported returns true if the pod terminated because SCTP isn't supported.
+func (np *NetworkPod) sctpNotSupported(ctx context.Context) bool {
+	pod, err := KubeClients[np.Config.Cluster].CoreV1().Pods(np.framework.Namespace).Get(ctx, np.Pod.Name, metav1.GetOptions{})
+	if err != nil || len(pod.Status.ContainerStatuses) == 0 || pod.Status.ContainerStatuses[0].State.Terminated == nil {
+		return false
+	}
+
+	return strings.Contains(pod.Status.ContainerStatuses[0].State.Terminated.Message, sctpNotSupportedMessage)
+}
+
+// SCTPNotSupportedError returns ErrSCTPNotSupported, wrapped with the cluster, if the SCTP listener pod terminated
+// because its node doesn't support SCTP, nil otherwise. The listener may only fail to listen after it became ready,
+// so this must be checked once the pod finished, before checking that it finished successfully.
+func (np *NetworkPod) SCTPNotSupportedError() error {
+	if strings.Contains(np.TerminationMessage, sctpNotSupportedMessage) {
+		return np.sctpNotSupportedError()
+	}
+
+	return nil
+}
+
+func (np *NetworkPod) sctpNotSupportedError() error {
+	return errors.Wrapf(ErrSCTPNotSupported, "on cluster %q", TestContext.ClusterIDs[np.Config.Cluster])
+}
+
+// create a test pod inside the current test namespace on the specified cluster.
+// The pod will connect to remoteIP:TestPort over SCTP, send sendString over the
+// association, and write the network response in the pod termination log, then
 // exit with 0 status.
-func (np *NetworkPod) buildThroughputClientPod() {
+func (np *NetworkPod) buildSCTPCheckConnectorPod(ctx context.Context) error {
+	affinity, err := np.nodeAffinity(ctx, np.Config.Scheduling)
+	if err != nil {
+		return err
+	}
+
+	sctpCheckConnectorPod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "sctp-check-pod",
+			Labels: map[string]string{
+				TestAppLabel: "sctp-check-pod",
+			},
+		},
+		Spec: v1.PodSpec{
+			Affinity:      affinity,
+			RestartPolicy: v1.RestartPolicyNever,
+			HostNetwork:   bool(np.Config.Networking),
+			Containers: []v1.Container{
+				{
+					Name:  "sctp-check-connector",
+					Image: TestContext.NettestImageURL,
+					// SCTP doesn't support half-closed associations, so the input is held open for a little while to
+					// give the listener's data time to arrive.
+					Command: []string{
+						"sh",
+						"-c",
+						"(for j in $(seq 1 $BUFS_NUM);" +
+							" do echo [dataplane] connector says $SEND_STRING; done; sleep 2)" +
+							" | for i in $(seq $CONN_TRIES);" +
+							" do if ncat --sctp -v -w $CONN_TIMEOUT $REMOTE_IP $REMOTE_PORT;" +
+							" then break;" +
+							" else sleep $RETRY_SLEEP;" +
+							" fi; done >/dev/termination-log 2>&1",
+					},
+					Env: []v1.EnvVar{
+						{Name: "REMOTE_PORT", Value: strconv.FormatInt(int64(np.Config.Port), 10)},
+						{Name: "SEND_STRING", Value: np.Config.Data},
+						{Name: "REMOTE_IP", Value: np.Config.RemoteIP},
+						{Name: "CONN_TRIES", Value: strconv.FormatUint(uint64(np.Config.ConnectionAttempts), 10)},
+						{Name: "CONN_TIMEOUT", Value: strconv.FormatUint(uint64(np.Config.ConnectionTimeout), 10)},
+						{Name: "RETRY_SLEEP", Value: strconv.FormatUint(uint64(np.Config.ConnectionTimeout/2), 10)},
+						{Name: "BUFS_NUM", Value: strconv.FormatUint(uint64(np.Config.NumOfDataBufs), 10)},
+					},
+					SecurityContext: podSecurityContext,
+				},
+			},
+			Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
+		},
+	}
+
+	return np.create(ctx, &sctpCheckConnectorPod)
+}
+
+// create a test pod inside the current test namespace on the specified cluster.
+// The pod will initiate iperf3 throughput test to remoteIP, write the number of
+// attempts and a summary of the test response in the pod termination log and the
+// JSON test response in the pod log, then exit with 0 status. The response is
+// parsed by ThroughputResult.
+func (np *NetworkPod) buildThroughputClientPod(ctx context.Context) error {
+	affinity, err := np.nodeAffinity(ctx, np.Config.Scheduling)
+	if err != nil {
+		return err
+	}
+
 	nettestPod := v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "nettest-client-pod",
@@ -368,8 +929,9 @@ func (np *NetworkPod) buildThroughputClientPod() {
 			},
 		},
 		Spec: v1.PodSpec{
-			Affinity:      np.nodeAffinity(np.Config.Scheduling),
+			Affinity:      affinity,
 			RestartPolicy: v1.RestartPolicyNever,
+			HostNetwork:   bool(np.Config.Networking),
 			Containers: []v1.Container{
 				{
 					Name:            "nettest-client-pod",
@@ -377,10 +939,11 @@ func (np *NetworkPod) buildThroughputClientPod() {
 					ImagePullPolicy: v1.PullAlways,
 					Command: []string{
 						"sh", "-c", "for i in $(seq $CONN_TRIES);" +
-							" do if iperf3 -w 256K --connect-timeout $CONN_TIMEOUT -P 10 -p $TARGET_PORT -c $TARGET_IP;" +
+							" do if iperf3 -J -w 256K --connect-timeout $CONN_TIMEOUT -P 10 -p $TARGET_PORT -c $TARGET_IP >/tmp/iperf3.json;" +
 							" then break;" +
-							" else echo [going to retry]; sleep $RETRY_SLEEP;" +
-							" fi; done >/dev/termination-log 2>&1",
+							" else echo [going to retry] >>/dev/termination-log; sleep $RETRY_SLEEP;" +
+							" fi; done; echo " + throughputAttemptsPrefix + " $i >>/dev/termination-log;" +
+							" jq -r \"$SUMMARY_FILTER\" /tmp/iperf3.json >>/dev/termination-log 2>&1; cat /tmp/iperf3.json",
 					},
 					Env: []v1.EnvVar{
 						{Name: "TARGET_IP", Value: np.Config.RemoteIP},
@@ -388,6 +951,7 @@ func (np *NetworkPod) buildThroughputClientPod() {
 						{Name: "CONN_TRIES", Value: strconv.FormatUint(uint64(np.Config.ConnectionAttempts), 10)},
 						{Name: "RETRY_SLEEP", Value: strconv.FormatUint(uint64(np.Config.ConnectionTimeout), 10)},
 						{Name: "CONN_TIMEOUT", Value: strconv.FormatUint(uint64(np.Config.ConnectionTimeout*1000), 10)},
+						{Name: "SUMMARY_FILTER", Value: iperf3SummaryFilter},
 					},
 					SecurityContext: podSecurityContext,
 				},
@@ -395,16 +959,21 @@ func (np *NetworkPod) buildThroughputClientPod() {
 			Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
 		},
 	}
-	pc := KubeClients[np.Config.Cluster].CoreV1().Pods(np.framework.Namespace)
-	var err error
-	np.Pod, err = pc.Create(context.TODO(), &nettestPod, metav1.CreateOptions{})
-	Expect(err).NotTo(HaveOccurred())
-	np.AwaitReady()
+	if err := np.create(ctx, &nettestPod); err != nil {
+		return err
+	}
+
+	return np.AwaitReadyOrError(ctx)
 }
 
 // create a test pod inside the current test namespace on the specified cluster.
 // The pod will start iperf3 in server mode.
-func (np *NetworkPod) buildThroughputServerPod() {
+func (np *NetworkPod) buildThroughputServerPod(ctx context.Context) error {
+	affinity, err := np.nodeAffinity(ctx, np.Config.Scheduling)
+	if err != nil {
+		return err
+	}
+
 	nettestPod := v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "nettest-server-pod",
@@ -413,7 +982,7 @@ func (np *NetworkPod) buildThroughputServerPod() {
 			},
 		},
 		Spec: v1.PodSpec{
-			Affinity:      np.nodeAffinity(np.Config.Scheduling),
+			Affinity:      affinity,
 			RestartPolicy: v1.RestartPolicyNever,
 			Containers: []v1.Container{
 				{
@@ -430,18 +999,24 @@ func (np *NetworkPod) buildThroughputServerPod() {
 			Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
 		},
 	}
-	pc := KubeClients[np.Config.Cluster].CoreV1().Pods(np.framework.Namespace)
-	var err error
-	np.Pod, err = pc.Create(context.TODO(), &nettestPod, metav1.CreateOptions{})
-	Expect(err).NotTo(HaveOccurred())
-	np.AwaitReady()
+	if err := np.create(ctx, &nettestPod); err != nil {
+		return err
+	}
+
+	return np.AwaitReadyOrError(ctx)
 }
 
 // create a test pod inside the current test namespace on the specified cluster.
 // The pod will initiate netperf latency test to remoteIP and write the test
 // response in the pod termination log, then
-// exit with 0 status.
-func (np *NetworkPod) buildLatencyClientPod() {
+// exit with 0 status. The response is parsed by LatencyResult, with the
+// percentiles if LatencyPercentiles is set.
+func (np *NetworkPod) buildLatencyClientPod(ctx context.Context) error {
+	affinity, err := np.nodeAffinity(ctx, np.Config.Scheduling)
+	if err != nil {
+		return err
+	}
+
 	nettestPod := v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "latency-client-pod",
@@ -450,8 +1025,9 @@ func (np *NetworkPod) buildLatencyClientPod() {
 			},
 		},
 		Spec: v1.PodSpec{
-			Affinity:      np.nodeAffinity(np.Config.Scheduling),
+			Affinity:      affinity,
 			RestartPolicy: v1.RestartPolicyNever,
+			HostNetwork:   bool(np.Config.Networking),
 			Containers: []v1.Container{
 				{
 					Name:            "latency-client-pod",
@@ -460,8 +1036,7 @@ func (np *NetworkPod) buildLatencyClientPod() {
 					Command: []string{
 						"sh",
 						"-c",
-						"netperf -H $TARGET_IP -t TCP_RR  -- -o min_latency,mean_latency,max_latency,stddev_latency,transaction_rate" +
-							" >/dev/termination-log 2>&1",
+						netperfLatencyCommand(np.Config.LatencyPercentiles) + " >/dev/termination-log 2>&1",
 					},
 					Env: []v1.EnvVar{
 						{Name: "TARGET_IP", Value: np.Config.RemoteIP},
@@ -472,16 +1047,21 @@ func (np *NetworkPod) buildLatencyClientPod() {
 			Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
 		},
 	}
-	pc := KubeClients[np.Config.Cluster].CoreV1().Pods(np.framework.Namespace)
-	var err error
-	np.Pod, err = pc.Create(context.TODO(), &nettestPod, metav1.CreateOptions{})
-	Expect(err).NotTo(HaveOccurred())
-	np.AwaitReady()
+	if err := np.create(ctx, &nettestPod); err != nil {
+		return err
+	}
+
+	return np.AwaitReadyOrError(ctx)
 }
 
 // create a test pod inside the current test namespace on the specified cluster.
 // The pod will start netserver (server of netperf).
-func (np *NetworkPod) buildLatencyServerPod() {
+func (np *NetworkPod) buildLatencyServerPod(ctx context.Context) error {
+	affinity, err := np.nodeAffinity(ctx, np.Config.Scheduling)
+	if err != nil {
+		return err
+	}
+
 	nettestPod := v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "latency-server-pod",
@@ -490,7 +1070,7 @@ func (np *NetworkPod) buildLatencyServerPod() {
 			},
 		},
 		Spec: v1.PodSpec{
-			Affinity:      np.nodeAffinity(np.Config.Scheduling),
+			Affinity:      affinity,
 			RestartPolicy: v1.RestartPolicyNever,
 			Containers: []v1.Container{
 				{
@@ -504,16 +1084,21 @@ func (np *NetworkPod) buildLatencyServerPod() {
 			Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
 		},
 	}
-	pc := KubeClients[np.Config.Cluster].CoreV1().Pods(np.framework.Namespace)
-	var err error
-	np.Pod, err = pc.Create(context.TODO(), &nettestPod, metav1.CreateOptions{})
-	Expect(err).NotTo(HaveOccurred())
-	np.AwaitReady()
+	if err := np.create(ctx, &nettestPod); err != nil {
+		return err
+	}
+
+	return np.AwaitReadyOrError(ctx)
 }
 
 // create a test pod inside the current test namespace on the specified cluster.
 // The pod will use the image specified and run command specified.
-func (np *NetworkPod) buildCustomPod() {
+func (np *NetworkPod) buildCustomPod(ctx context.Context) error {
+	affinity, err := np.nodeAffinity(ctx, np.Config.Scheduling)
+	if err != nil {
+		return err
+	}
+
 	terminationGracePeriodSeconds := int64(5)
 	customPod := v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -523,7 +1108,7 @@ func (np *NetworkPod) buildCustomPod() {
 			},
 		},
 		Spec: v1.PodSpec{
-			Affinity:                      np.nodeAffinity(np.Config.Scheduling),
+			Affinity:                      affinity,
 			RestartPolicy:                 v1.RestartPolicyNever,
 			HostNetwork:                   bool(np.Config.Networking),
 			TerminationGracePeriodSeconds: &terminationGracePeriodSeconds,
@@ -540,38 +1125,45 @@ func (np *NetworkPod) buildCustomPod() {
 		},
 	}
 
-	pc := KubeClients[np.Config.Cluster].CoreV1().Pods(np.framework.Namespace)
-
-	var err error
-	np.Pod, err = pc.Create(context.TODO(), &customPod, metav1.CreateOptions{})
-	Expect(err).NotTo(HaveOccurred())
+	if err := np.create(ctx, &customPod); err != nil {
+		return err
+	}
 
-	np.AwaitReady()
+	return np.AwaitReadyOrError(ctx)
 }
 
-func (np *NetworkPod) nodeAffinity(scheduling NetworkPodScheduling) *v1.Affinity {
-	Expect(scheduling).ShouldNot(Equal(InvalidScheduling))
-
+func (np *NetworkPod) nodeAffinity(ctx context.Context, scheduling NetworkPodScheduling) (*v1.Affinity, error) {
 	var nodeSelTerms []v1.NodeSelectorTerm
 
 	switch scheduling {
 	case GatewayNode:
-		hostname := np.activeGatewayHostname()
+		hostname, err := np.activeGatewayHostname(ctx)
+		if err != nil {
+			return nil, err
+		}
+
 		nodeSelTerms = addNodeSelectorTerm(nodeSelTerms, "kubernetes.io/hostname", v1.NodeSelectorOpIn, []string{hostname})
 
 	case NonGatewayNode:
-		smE2eNonGWLabelledNodeList, err := KubeClients[np.Config.Cluster].CoreV1().Nodes().List(context.TODO(),
+		smE2eNonGWLabelledNodeList, err := KubeClients[np.Config.Cluster].CoreV1().Nodes().List(ctx,
 			metav1.ListOptions{LabelSelector: TestNonGWNodeLabel})
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return nil, errors.Wrapf(err, "error listing nodes with label %q", TestNonGWNodeLabel)
+		}
 
 		nonGWNodes := []string{}
 
 		if len(smE2eNonGWLabelledNodeList.Items) > 0 {
-			activeGWHostname := np.activeGatewayHostname()
+			activeGWHostname, err := np.activeGatewayHostname(ctx)
+			if err != nil {
+				return nil, err
+			}
 
 			for i := range smE2eNonGWLabelledNodeList.Items {
 				hostname := smE2eNonGWLabelledNodeList.Items[i].GetObjectMeta().GetLabels()["kubernetes.io/hostname"]
-				Expect(hostname).NotTo(BeEmpty())
+				if hostname == "" {
+					return nil, fmt.Errorf("node %q has no hostname label", smE2eNonGWLabelledNodeList.Items[i].Name)
+				}
 
 				if hostname != activeGWHostname {
 					nonGWNodes = append(nonGWNodes, hostname)
@@ -586,11 +1178,11 @@ func (np *NetworkPod) nodeAffinity(scheduling NetworkPodScheduling) *v1.Affinity
 			nodeSelTerms = addNodeSelectorTerm(nodeSelTerms, GatewayLabel, v1.NodeSelectorOpDoesNotExist, nil)
 			nodeSelTerms = addNodeSelectorTerm(nodeSelTerms, GatewayLabel, v1.NodeSelectorOpNotIn, []string{"true"})
 		default:
-			Failf("%q label is only present on the active GW node and not on any other nodes", TestNonGWNodeLabel)
+			return nil, fmt.Errorf("%q label is only present on the active GW node and not on any other nodes", TestNonGWNodeLabel)
 		}
 
 	case InvalidScheduling:
-		panic("scheduling can't equal InvalidScheduling here, we checked above")
+		return nil, errors.New("the network pod Scheduling must be specified")
 	}
 
 	return &v1.Affinity{
@@ -599,23 +1191,38 @@ func (np *NetworkPod) nodeAffinity(scheduling NetworkPodScheduling) *v1.Affinity
 				NodeSelectorTerms: nodeSelTerms,
 			},
 		},
-	}
+	}, nil
 }
 
-func (np *NetworkPod) activeGatewayHostname() string {
-	smGWPodList := AwaitUntil("await active gateway Pod",
+func (np *NetworkPod) activeGatewayHostname(ctx context.Context) (string, error) {
+	result, err := awaitUntilOrError(ctx, "await active gateway Pod",
 		func() (interface{}, error) {
-			return KubeClients[np.Config.Cluster].CoreV1().Pods(TestContext.SubmarinerNamespace).List(context.TODO(),
+			return KubeClients[np.Config.Cluster].CoreV1().Pods(TestContext.SubmarinerNamespace).List(ctx,
 				metav1.ListOptions{LabelSelector: ActiveGatewayLabel})
 		},
 		func(result interface{}) (bool, string, error) {
 			return len(result.(*v1.PodList).Items) == 1, "", nil
-		}).(*v1.PodList)
+		})
+	if err != nil {
+		return "", err
+	}
+
+	smGWPod := &result.(*v1.PodList).Items[0]
+
+	hostname := smGWPod.Labels["gateway.submariner.io/node"]
+	if hostname == "" {
+		return "", fmt.Errorf("active gateway pod %q has no node label", smGWPod.Name)
+	}
+
+	return hostname, nil
+}
+
+func (np *NetworkPod) create(ctx context.Context, pod *v1.Pod) error {
+	var err error
 
-	hostname := smGWPodList.Items[0].Labels["gateway.submariner.io/node"]
-	Expect(hostname).NotTo(BeEmpty())
+	np.Pod, err = KubeClients[np.Config.Cluster].CoreV1().Pods(np.framework.Namespace).Create(ctx, pod, metav1.CreateOptions{})
 
-	return hostname
+	return errors.Wrapf(err, "error creating pod %q", pod.GenerateName)
 }
 
 func addNodeSelectorTerm(nodeSelTerms []v1.NodeSelectorTerm, label string,
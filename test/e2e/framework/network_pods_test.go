@@ -0,0 +1,272 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/framework/fake"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/testing"
+)
+
+var _ = Describe("nodeAffinity", func() {
+	var env *fake.Environment
+
+	BeforeEach(func() {
+		env = fake.NewEnvironment(fake.ClusterConfig{
+			ID:              "east",
+			GatewayNodes:    []string{"east-gw"},
+			NonGatewayNodes: []string{"east-worker1", "east-worker2"},
+		})
+		DeferCleanup(env.Install())
+	})
+
+	hostnameTerm := func(hostnames ...string) v1.NodeSelectorTerm {
+		return v1.NodeSelectorTerm{MatchExpressions: []v1.NodeSelectorRequirement{{
+			Key:      "kubernetes.io/hostname",
+			Operator: v1.NodeSelectorOpIn,
+			Values:   hostnames,
+		}}}
+	}
+
+	selectorTerms := func(ctx context.Context, scheduling framework.NetworkPodScheduling) []v1.NodeSelectorTerm {
+		affinity, err := framework.NodeAffinity(ctx, framework.ClusterA, scheduling)
+		Expect(err).NotTo(HaveOccurred())
+
+		return affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	}
+
+	labelNonGatewayNode := func(ctx context.Context, name string) {
+		nodes := env.Cluster(framework.ClusterA).KubeClient.CoreV1().Nodes()
+
+		node, err := nodes.Get(ctx, name, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		node.Labels["test.submariner.io/non-gateway-node"] = "true"
+
+		_, err = nodes.Update(ctx, node, metav1.UpdateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("should select the active gateway node for GatewayNode scheduling", func(ctx context.Context) {
+		Expect(selectorTerms(ctx, framework.GatewayNode)).To(Equal([]v1.NodeSelectorTerm{hostnameTerm("east-gw")}))
+	})
+
+	When("no node is labeled as a test non-gateway node", func() {
+		It("should exclude the gateway nodes for NonGatewayNode scheduling", func(ctx context.Context) {
+			Expect(selectorTerms(ctx, framework.NonGatewayNode)).To(Equal([]v1.NodeSelectorTerm{
+				{MatchExpressions: []v1.NodeSelectorRequirement{{
+					Key:      framework.GatewayLabel,
+					Operator: v1.NodeSelectorOpDoesNotExist,
+				}}},
+				{MatchExpressions: []v1.NodeSelectorRequirement{{
+					Key:      framework.GatewayLabel,
+					Operator: v1.NodeSelectorOpNotIn,
+					Values:   []string{"true"},
+				}}},
+			}))
+		})
+	})
+
+	When("nodes are labeled as test non-gateway nodes", func() {
+		BeforeEach(func(ctx context.Context) {
+			labelNonGatewayNode(ctx, "east-worker2")
+		})
+
+		It("should select them for NonGatewayNode scheduling", func(ctx context.Context) {
+			Expect(selectorTerms(ctx, framework.NonGatewayNode)).To(Equal([]v1.NodeSelectorTerm{hostnameTerm("east-worker2")}))
+		})
+	})
+
+	When("only the active gateway node is labeled as a test non-gateway node", func() {
+		BeforeEach(func(ctx context.Context) {
+			labelNonGatewayNode(ctx, "east-gw")
+		})
+
+		It("should return an error for NonGatewayNode scheduling", func(ctx context.Context) {
+			_, err := framework.NodeAffinity(ctx, framework.ClusterA, framework.NonGatewayNode)
+			Expect(err).To(MatchError(ContainSubstring("only present on the active GW node")))
+		})
+	})
+
+	It("should return an error if the scheduling isn't specified", func(ctx context.Context) {
+		_, err := framework.NodeAffinity(ctx, framework.ClusterA, framework.InvalidScheduling)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("RemoteAddressOrError", func() {
+	var (
+		env *fake.Environment
+		np  *framework.NetworkPod
+	)
+
+	BeforeEach(func() {
+		env = fake.NewEnvironment(fake.ClusterConfig{ID: "east", GatewayNodes: []string{"east-gw"}})
+		DeferCleanup(env.Install())
+
+		f := framework.NewBareFramework("endpoints")
+		f.Namespace = "e2e-tests-endpoints"
+
+		np = framework.NewExistingNetworkPod(f, &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "tcp-check-listener-abcde",
+				Namespace: f.Namespace,
+				Labels:    map[string]string{framework.TestAppLabel: "tcp-check-listener"},
+			},
+			Status: v1.PodStatus{
+				PodIP:  "10.130.0.12",
+				PodIPs: []v1.PodIP{{IP: "10.130.0.12"}},
+			},
+		}, &framework.NetworkPodConfig{Cluster: framework.ClusterA, Port: framework.TestPort})
+	})
+
+	It("should return the pod IP for PodIPEndpoint", func(ctx context.Context) {
+		Expect(np.RemoteAddressOrError(ctx, framework.PodIPEndpoint)).To(Equal("10.130.0.12"))
+	})
+
+	It("should return the ClusterIP of a service pointing to the pod for ServiceIPEndpoint", func(ctx context.Context) {
+		env.Cluster(framework.ClusterA).KubeClient.PrependReactor("create", "services",
+			func(action testing.Action) (bool, runtime.Object, error) {
+				service := action.(testing.CreateAction).GetObject().(*v1.Service)
+				service.Spec.ClusterIP = "100.90.1.7"
+				service.Spec.ClusterIPs = []string{"100.90.1.7"}
+
+				return false, nil, nil
+			})
+
+		Expect(np.RemoteAddressOrError(ctx, framework.ServiceIPEndpoint)).To(Equal("100.90.1.7"))
+	})
+
+	It("should return the clusterset name of the exported service for ClustersetServiceNameEndpoint", func(ctx context.Context) {
+		Expect(np.RemoteAddressOrError(ctx, framework.ClustersetServiceNameEndpoint)).To(
+			Equal("test-svc-tcp-check-listener.e2e-tests-endpoints.svc.clusterset.local"))
+
+		_, err := env.Cluster(framework.ClusterA).DynClient.Resource(fake.ServiceExportGVR).Namespace("e2e-tests-endpoints").Get(ctx,
+			"test-svc-tcp-check-listener", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return an error if the pod has no IP of the configured family", func(ctx context.Context) {
+		np.Config.IPFamily = v1.IPv6Protocol
+
+		_, err := np.RemoteAddressOrError(ctx, framework.PodIPEndpoint)
+		Expect(err).To(MatchError(ContainSubstring("no IPv6 address")))
+	})
+
+	It("should return an error for an unsupported endpoint type", func(ctx context.Context) {
+		_, err := np.RemoteAddressOrError(ctx, framework.EndpointType(42))
+		Expect(err).To(MatchError(ContainSubstring("unsupported endpoint type")))
+	})
+})
+
+var _ = Describe("SCTPNotSupportedError", func() {
+	var np *framework.NetworkPod
+
+	BeforeEach(func() {
+		env := fake.NewEnvironment(fake.ClusterConfig{ID: "east"})
+		DeferCleanup(env.Install())
+
+		np = framework.NewExistingNetworkPod(framework.NewBareFramework("sctp"), &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "sctp-check-listener-abcde"},
+		}, &framework.NetworkPodConfig{Type: framework.SCTPListenerPod, Cluster: framework.ClusterA})
+	})
+
+	It("should return ErrSCTPNotSupported with the cluster if the listener reported it", func() {
+		np.TerminationMessage = "listening on [::]:1234\n[dataplane] SCTP is not supported\n"
+
+		err := np.SCTPNotSupportedError()
+		Expect(err).To(MatchError(framework.ErrSCTPNotSupported))
+		Expect(err).To(MatchError(ContainSubstring(`on cluster "east"`)))
+	})
+
+	It("should return nil if the listener didn't report it", func() {
+		np.TerminationMessage = "listening on [::]:1234\nconnector data\n"
+		Expect(np.SCTPNotSupportedError()).To(Succeed())
+	})
+})
+
+var _ = Describe("NetworkPod DeleteOrError", func() {
+	var (
+		env *fake.Environment
+		np  *framework.NetworkPod
+	)
+
+	const namespace = "e2e-tests-delete"
+
+	BeforeEach(func(ctx context.Context) {
+		env = fake.NewEnvironment(fake.ClusterConfig{ID: "east"})
+		DeferCleanup(env.Install())
+
+		f := framework.NewBareFramework("delete")
+		f.Namespace = namespace
+
+		pod, err := env.Cluster(framework.ClusterA).KubeClient.CoreV1().Pods(namespace).Create(ctx, &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nettest-server-pod-abcde",
+				Namespace: namespace,
+				Labels:    map[string]string{framework.TestAppLabel: "nettest-server-pod"},
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		np = framework.NewExistingNetworkPod(f, pod, &framework.NetworkPodConfig{Cluster: framework.ClusterA, Port: framework.TestPort})
+	})
+
+	It("should delete the pod, its service and its ServiceExport", func(ctx context.Context) {
+		Expect(np.RemoteAddressOrError(ctx, framework.ClustersetServiceNameEndpoint)).To(
+			Equal("test-svc-nettest-server-pod.e2e-tests-delete.svc.clusterset.local"))
+
+		Expect(np.DeleteOrError(ctx)).To(Succeed())
+
+		cluster := env.Cluster(framework.ClusterA)
+
+		_, err := cluster.KubeClient.CoreV1().Pods(namespace).Get(ctx, np.Pod.Name, metav1.GetOptions{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error %v", err)
+
+		_, err = cluster.KubeClient.CoreV1().Services(namespace).Get(ctx, "test-svc-nettest-server-pod", metav1.GetOptions{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error %v", err)
+
+		_, err = cluster.DynClient.Resource(fake.ServiceExportGVR).Namespace(namespace).Get(ctx, "test-svc-nettest-server-pod",
+			metav1.GetOptions{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error %v", err)
+	})
+
+	It("should succeed if the pod has no service nor ServiceExport, or was already deleted", func(ctx context.Context) {
+		Expect(np.DeleteOrError(ctx)).To(Succeed())
+		Expect(np.DeleteOrError(ctx)).To(Succeed())
+	})
+
+	It("should return an error if the pod isn't removed", func(ctx context.Context) {
+		env.Cluster(framework.ClusterA).KubeClient.PrependReactor("delete", "pods",
+			func(_ testing.Action) (bool, runtime.Object, error) {
+				// Like a pod whose deletion is blocked by a finalizer.
+				return true, nil, nil
+			})
+
+		Expect(np.DeleteOrError(ctx)).To(MatchError(ContainSubstring("Pod still exists")))
+	})
+})
@@ -0,0 +1,227 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const requirementLabelPrefix = "requires-"
+
+// Requirement is a capability of the environment required by a spec. Requirements are evaluated against the clusters
+// discovered by BeforeSuite, without querying them again.
+type Requirement struct {
+	name  string
+	value string
+	// unmet returns why the requirement isn't met by the clusters, or an empty string if it is.
+	unmet func(clusters []ClusterInfo) string
+}
+
+// RequireClusters requires at least the given number of clusters.
+func RequireClusters(n int) Requirement {
+	return Requirement{
+		name:  "clusters",
+		value: strconv.Itoa(n),
+		unmet: func(clusters []ClusterInfo) string {
+			if len(clusters) < n {
+				return fmt.Sprintf("at least %d clusters are required, found %d", n, len(clusters))
+			}
+
+			return ""
+		},
+	}
+}
+
+// RequireGlobalnet requires Globalnet to be enabled.
+func RequireGlobalnet() Requirement {
+	return Requirement{
+		name: "globalnet",
+		unmet: func(clusters []ClusterInfo) string {
+			if TestContext.GlobalnetEnabled || slices.ContainsFunc(clusters, func(c ClusterInfo) bool {
+				return c.HasRoles(GlobalnetRole)
+			}) {
+				return ""
+			}
+
+			return "Globalnet is required but isn't enabled"
+		},
+	}
+}
+
+// RequireMultipleGateways requires a cluster with more than one gateway node.
+func RequireMultipleGateways() Requirement {
+	return Requirement{
+		name: "multiple-gateways",
+		unmet: func(clusters []ClusterInfo) string {
+			if slices.ContainsFunc(clusters, func(c ClusterInfo) bool {
+				return c.HasRoles(MultiGatewayRole)
+			}) {
+				return ""
+			}
+
+			return "a cluster with multiple gateway nodes is required but none was found"
+		},
+	}
+}
+
+// RequireFIPS requires a cluster with FIPS enabled.
+func RequireFIPS() Requirement {
+	return Requirement{
+		name: "fips",
+		unmet: func(clusters []ClusterInfo) string {
+			if slices.ContainsFunc(clusters, func(c ClusterInfo) bool {
+				return c.FIPSEnabled
+			}) {
+				return ""
+			}
+
+			return "a cluster with FIPS enabled is required but none was found"
+		},
+	}
+}
+
+// RequireIPFamily requires every cluster to support the given IP family.
+func RequireIPFamily(family corev1.IPFamily) Requirement {
+	return Requirement{
+		name:  "ip-family",
+		value: string(family),
+		unmet: func(clusters []ClusterInfo) string {
+			for i := range clusters {
+				if !slices.Contains(clusters[i].IPFamilies, family) {
+					return fmt.Sprintf("IP family %s is required but cluster %q only supports %v", family, clusters[i].ID,
+						clusters[i].IPFamilies)
+				}
+			}
+
+			return ""
+		},
+	}
+}
+
+// RequireProvider requires every cluster to run on the given provider, e.g. "kind".
+func RequireProvider(provider string) Requirement {
+	return Requirement{
+		name:  "provider",
+		value: provider,
+		unmet: func(clusters []ClusterInfo) string {
+			for i := range clusters {
+				if clusters[i].Provider != provider {
+					return fmt.Sprintf("provider %q is required but cluster %q runs on %q", provider, clusters[i].ID,
+						clusters[i].Provider)
+				}
+			}
+
+			return ""
+		},
+	}
+}
+
+var requirementsByName = map[string]func(value string) (Requirement, error){
+	"clusters": func(value string) (Requirement, error) {
+		n, err := strconv.Atoi(value)
+		return RequireClusters(n), err
+	},
+	"globalnet": func(string) (Requirement, error) {
+		return RequireGlobalnet(), nil
+	},
+	"multiple-gateways": func(string) (Requirement, error) {
+		return RequireMultipleGateways(), nil
+	},
+	"fips": func(string) (Requirement, error) {
+		return RequireFIPS(), nil
+	},
+	"ip-family": func(value string) (Requirement, error) {
+		return RequireIPFamily(corev1.IPFamily(value)), nil
+	},
+	"provider": func(value string) (Requirement, error) {
+		return RequireProvider(value), nil
+	},
+}
+
+// Label returns the Ginkgo label representing the requirement, e.g. "requires-globalnet" or "requires-clusters=3".
+func (r Requirement) Label() string {
+	if r.value == "" {
+		return requirementLabelPrefix + r.name
+	}
+
+	return requirementLabelPrefix + r.name + "=" + r.value
+}
+
+// Requires returns the Ginkgo labels declaring the given requirements, to decorate containers and specs. The specs are
+// skipped, before their namespaces are created, by the frameworks created with NewFramework if the requirements aren't
+// met. The labels also allow selecting or excluding the specs with a Ginkgo label filter, e.g. "!requires-fips".
+func Requires(requirements ...Requirement) ginkgo.Labels {
+	labels := make(ginkgo.Labels, len(requirements))
+	for i := range requirements {
+		labels[i] = requirements[i].Label()
+	}
+
+	return labels
+}
+
+// UnmetRequirements returns why the requirements declared by the given labels, typically those of a spec, aren't met by
+// the discovered clusters. Labels that don't declare requirements are ignored. Suites can use it to list which specs
+// apply to the environment, e.g. from the spec reports.
+func UnmetRequirements(labels []string) []string {
+	reasons := []string{}
+
+	for _, label := range labels {
+		nameValue, found := strings.CutPrefix(label, requirementLabelPrefix)
+		if !found {
+			continue
+		}
+
+		name, value, _ := strings.Cut(nameValue, "=")
+
+		newRequirement, found := requirementsByName[name]
+		if !found {
+			reasons = append(reasons, fmt.Sprintf("unknown requirement %q", label))
+			continue
+		}
+
+		requirement, err := newRequirement(value)
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("invalid requirement %q: %v", label, err))
+			continue
+		}
+
+		if reason := requirement.unmet(clusterInfos); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+
+	return reasons
+}
+
+// Require skips the current spec unless all the given requirements are met.
+func (f *Framework) Require(requirements ...Requirement) {
+	skipOnUnmetRequirements(Requires(requirements...))
+}
+
+func skipOnUnmetRequirements(labels []string) {
+	if reasons := UnmetRequirements(labels); len(reasons) > 0 {
+		Skipf("Unmet requirements: %s", strings.Join(reasons, "; "))
+	}
+}
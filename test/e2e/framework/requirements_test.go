@@ -0,0 +1,91 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = DescribeTable("Requirement labels",
+	func(requirement framework.Requirement, expected string) {
+		Expect(requirement.Label()).To(Equal(expected))
+	},
+	Entry("without a value", framework.RequireGlobalnet(), "requires-globalnet"),
+	Entry("with a number", framework.RequireClusters(3), "requires-clusters=3"),
+	Entry("with a string", framework.RequireProvider("kind"), "requires-provider=kind"),
+	Entry("with an IP family", framework.RequireIPFamily(corev1.IPv6Protocol), "requires-ip-family=IPv6"),
+)
+
+var _ = Describe("UnmetRequirements", func() {
+	BeforeEach(func() {
+		framework.SetClusterInfos([]framework.ClusterInfo{
+			{
+				Index: framework.ClusterA, ID: "east", Provider: "kind", NumGatewayNodes: 2,
+				Roles:      []framework.ClusterRole{framework.BrokerRole, framework.MultiGatewayRole},
+				IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			},
+			{
+				Index: framework.ClusterB, ID: "west", Provider: "kind", NumGatewayNodes: 1, FIPSEnabled: true,
+				IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+			},
+		})
+
+		DeferCleanup(framework.SetClusterInfos, []framework.ClusterInfo(nil))
+	})
+
+	DescribeTable("for the labels declared by Requires",
+		func(requirement framework.Requirement, expected []string) {
+			Expect(framework.UnmetRequirements(framework.Requires(requirement))).To(Equal(expected))
+		},
+		Entry("enough clusters", framework.RequireClusters(2), []string{}),
+		Entry("too few clusters", framework.RequireClusters(3), []string{"at least 3 clusters are required, found 2"}),
+		Entry("Globalnet disabled", framework.RequireGlobalnet(), []string{"Globalnet is required but isn't enabled"}),
+		Entry("multiple gateways", framework.RequireMultipleGateways(), []string{}),
+		Entry("FIPS", framework.RequireFIPS(), []string{}),
+		Entry("matching provider", framework.RequireProvider("kind"), []string{}),
+		Entry("other provider", framework.RequireProvider("aws"),
+			[]string{`provider "aws" is required but cluster "east" runs on "kind"`}),
+		Entry("IP family supported by every cluster", framework.RequireIPFamily(corev1.IPv4Protocol), []string{}),
+		Entry("IP family not supported by a cluster", framework.RequireIPFamily(corev1.IPv6Protocol),
+			[]string{`IP family IPv6 is required but cluster "west" only supports [IPv4]`}),
+	)
+
+	DescribeTable("for raw labels",
+		func(labels []string, expected []string) {
+			Expect(framework.UnmetRequirements(labels)).To(Equal(expected))
+		},
+		Entry("no labels", nil, []string{}),
+		Entry("labels not declaring requirements", []string{"dataplane", "globalnet"}, []string{}),
+		Entry("unknown requirement", []string{"requires-gpu"}, []string{`unknown requirement "requires-gpu"`}),
+		Entry("invalid value", []string{"requires-clusters=many"},
+			[]string{`invalid requirement "requires-clusters=many": strconv.Atoi: parsing "many": invalid syntax`}),
+		Entry("several unmet requirements", []string{"requires-clusters=3", "requires-globalnet", "requires-fips"},
+			[]string{"at least 3 clusters are required, found 2", "Globalnet is required but isn't enabled"}),
+	)
+
+	When("Globalnet is enabled via the TestContext", func() {
+		It("should meet the Globalnet requirement", func() {
+			framework.TestContext.GlobalnetEnabled = true
+			Expect(framework.UnmetRequirements(framework.Requires(framework.RequireGlobalnet()))).To(BeEmpty())
+		})
+	})
+})
@@ -20,11 +20,19 @@ package framework
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
-	"k8s.io/apimachinery/pkg/api/errors"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	mcsv1a1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 )
 
 var gvr = schema.GroupVersionResource{
@@ -33,7 +41,14 @@ var gvr = schema.GroupVersionResource{
 	Resource: "serviceexports",
 }
 
-func (f *Framework) CreateServiceExport(cluster ClusterIndex, name string) {
+// CreateServiceExport exports the service with the given name in the framework namespace on the given cluster. An
+// already existing ServiceExport is left as is.
+func (f *Framework) CreateServiceExport(ctx context.Context, cluster ClusterIndex, name string) {
+	Expect(f.CreateServiceExportOrError(ctx, cluster, name)).To(Succeed())
+}
+
+// CreateServiceExportOrError is like CreateServiceExport but returns an error instead of failing via Gomega.
+func (f *Framework) CreateServiceExportOrError(ctx context.Context, cluster ClusterIndex, name string) error {
 	resourceServiceExport := &unstructured.Unstructured{}
 	resourceServiceExport.SetName(name)
 	resourceServiceExport.SetNamespace(f.Namespace)
@@ -42,17 +57,146 @@ func (f *Framework) CreateServiceExport(cluster ClusterIndex, name string) {
 
 	svcExs := DynClients[cluster].Resource(gvr).Namespace(f.Namespace)
 
-	_ = AwaitUntil("create service export", func() (interface{}, error) {
-		result, err := svcExs.Create(context.TODO(), resourceServiceExport, metav1.CreateOptions{})
-		if errors.IsAlreadyExists(err) {
+	_, err := awaitUntilOrError(ctx, "create service export", func() (interface{}, error) {
+		result, err := svcExs.Create(ctx, resourceServiceExport, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
 			err = nil
 		}
 		return result, err
-	}, NoopCheckResult).(*unstructured.Unstructured)
+	}, NoopCheckResult)
+
+	return err
 }
 
-func (f *Framework) DeleteServiceExport(cluster ClusterIndex, name string) {
-	AwaitUntil("delete service export", func() (interface{}, error) {
-		return nil, DynClients[cluster].Resource(gvr).Namespace(f.Namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+func (f *Framework) DeleteServiceExport(ctx context.Context, cluster ClusterIndex, name string) {
+	AwaitUntil(ctx, "delete service export", func() (interface{}, error) {
+		return nil, DynClients[cluster].Resource(gvr).Namespace(f.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	}, NoopCheckResult)
 }
+
+// ServiceExportSynced and ServiceExportReady are set by the Lighthouse agent once the export has been synced to the
+// broker. Older versions of Lighthouse set Synced, newer ones Ready.
+const (
+	ServiceExportSynced mcsv1a1.ServiceExportConditionType = "Synced"
+	ServiceExportReady  mcsv1a1.ServiceExportConditionType = "Ready"
+)
+
+// ServiceExportConditionExpectation describes a ServiceExport condition to wait for.
+type ServiceExportConditionExpectation struct {
+	Type   mcsv1a1.ServiceExportConditionType
+	Status corev1.ConditionStatus
+	// Reason, if set, must equal the condition reason, e.g. "ServiceUnavailable" or "ConflictingPorts".
+	Reason string
+	// MessageContains, if set, must be contained in the condition message.
+	MessageContains string
+}
+
+// GetServiceExport returns the ServiceExport with the given name in the framework namespace on the given cluster.
+func (f *Framework) GetServiceExport(ctx context.Context, cluster ClusterIndex, name string) *mcsv1a1.ServiceExport {
+	serviceExport, err := f.GetServiceExportOrError(ctx, cluster, name)
+	Expect(err).NotTo(HaveOccurred())
+
+	return serviceExport
+}
+
+// GetServiceExportOrError is like GetServiceExport but returns an error instead of failing via Gomega.
+func (f *Framework) GetServiceExportOrError(ctx context.Context, cluster ClusterIndex, name string) (*mcsv1a1.ServiceExport, error) {
+	obj, err := DynClients[cluster].Resource(gvr).Namespace(f.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving ServiceExport %q on cluster %q", name, TestContext.ClusterIDs[cluster])
+	}
+
+	serviceExport := &mcsv1a1.ServiceExport{}
+
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, serviceExport)
+
+	return serviceExport, errors.Wrapf(err, "error converting ServiceExport %q", name)
+}
+
+// AwaitServiceExportConditions waits for the ServiceExport with the given name in the framework namespace on the given
+// cluster to have all the expected conditions. This can be used to verify that an export is rejected, e.g. with a
+// Valid condition set to False when its Service is missing or has an unsupported type, or a Conflict condition set to
+// True when its ports conflict with an export from another cluster. An expected Ready condition is also satisfied by a
+// Synced condition, for older versions of Lighthouse.
+func (f *Framework) AwaitServiceExportConditions(ctx context.Context, cluster ClusterIndex, name string,
+	expected ...ServiceExportConditionExpectation,
+) *mcsv1a1.ServiceExport {
+	serviceExport, err := f.AwaitServiceExportConditionsOrError(ctx, cluster, name, expected...)
+	Expect(err).NotTo(HaveOccurred())
+
+	return serviceExport
+}
+
+// AwaitServiceExportConditionsOrError is like AwaitServiceExportConditions but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitServiceExportConditionsOrError(ctx context.Context, cluster ClusterIndex, name string,
+	expected ...ServiceExportConditionExpectation,
+) (*mcsv1a1.ServiceExport, error) {
+	result, err := awaitUntilOrError(ctx, fmt.Sprintf("await ServiceExport %q conditions on cluster %q", name,
+		TestContext.ClusterIDs[cluster]), func() (interface{}, error) {
+		return f.GetServiceExportOrError(ctx, cluster, name)
+	}, func(result interface{}) (bool, string, error) {
+		serviceExport := result.(*mcsv1a1.ServiceExport)
+
+		for i := range expected {
+			if msg := expected[i].unmetBy(serviceExport.Status.Conditions); msg != "" {
+				return false, msg, nil
+			}
+		}
+
+		return true, "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*mcsv1a1.ServiceExport), nil
+}
+
+// AwaitServiceExportReady waits for the ServiceExport with the given name to be valid and synced to the broker.
+func (f *Framework) AwaitServiceExportReady(ctx context.Context, cluster ClusterIndex, name string) *mcsv1a1.ServiceExport {
+	serviceExport, err := f.AwaitServiceExportReadyOrError(ctx, cluster, name)
+	Expect(err).NotTo(HaveOccurred())
+
+	return serviceExport
+}
+
+// AwaitServiceExportReadyOrError is like AwaitServiceExportReady but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitServiceExportReadyOrError(ctx context.Context, cluster ClusterIndex, name string) (*mcsv1a1.ServiceExport, error) {
+	return f.AwaitServiceExportConditionsOrError(ctx, cluster, name,
+		ServiceExportConditionExpectation{Type: mcsv1a1.ServiceExportValid, Status: corev1.ConditionTrue},
+		ServiceExportConditionExpectation{Type: ServiceExportReady, Status: corev1.ConditionTrue})
+}
+
+// unmetBy returns why the given conditions don't meet the expectation, or an empty string if they do.
+func (e *ServiceExportConditionExpectation) unmetBy(conditions []mcsv1a1.ServiceExportCondition) string {
+	condition := findServiceExportCondition(conditions, e.Type)
+	if condition == nil && e.Type == ServiceExportReady {
+		condition = findServiceExportCondition(conditions, ServiceExportSynced)
+	}
+
+	if condition == nil {
+		return fmt.Sprintf("ServiceExport has no %q condition", e.Type)
+	}
+
+	reason := ptr.Deref(condition.Reason, "")
+	message := ptr.Deref(condition.Message, "")
+
+	if condition.Status != e.Status || (e.Reason != "" && reason != e.Reason) ||
+		(e.MessageContains != "" && !strings.Contains(message, e.MessageContains)) {
+		return fmt.Sprintf("ServiceExport %q condition has status %q, reason %q and message %q, expected status %q, reason %q"+
+			" and a message containing %q", condition.Type, condition.Status, reason, message, e.Status, e.Reason, e.MessageContains)
+	}
+
+	return ""
+}
+
+func findServiceExportCondition(conditions []mcsv1a1.ServiceExportCondition, conditionType mcsv1a1.ServiceExportConditionType,
+) *mcsv1a1.ServiceExportCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+
+	return nil
+}
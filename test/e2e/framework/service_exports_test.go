@@ -0,0 +1,109 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	mcsv1a1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+)
+
+var _ = DescribeTable("ServiceExportConditionExpectation unmetBy",
+	func(expected framework.ServiceExportConditionExpectation, conditions []mcsv1a1.ServiceExportCondition, unmet string) {
+		Expect(framework.UnmetBy(expected, conditions)).To(Equal(unmet))
+	},
+	Entry("a matching condition",
+		framework.ServiceExportConditionExpectation{Type: mcsv1a1.ServiceExportValid, Status: corev1.ConditionTrue},
+		[]mcsv1a1.ServiceExportCondition{
+			{Type: framework.ServiceExportReady, Status: corev1.ConditionFalse},
+			{Type: mcsv1a1.ServiceExportValid, Status: corev1.ConditionTrue},
+		}, ""),
+	Entry("a missing condition",
+		framework.ServiceExportConditionExpectation{Type: mcsv1a1.ServiceExportConflict, Status: corev1.ConditionTrue},
+		[]mcsv1a1.ServiceExportCondition{{Type: mcsv1a1.ServiceExportValid, Status: corev1.ConditionTrue}},
+		`ServiceExport has no "Conflict" condition`),
+	Entry("no conditions",
+		framework.ServiceExportConditionExpectation{Type: mcsv1a1.ServiceExportValid, Status: corev1.ConditionTrue},
+		nil, `ServiceExport has no "Valid" condition`),
+	Entry("a condition with another status",
+		framework.ServiceExportConditionExpectation{Type: mcsv1a1.ServiceExportValid, Status: corev1.ConditionTrue},
+		[]mcsv1a1.ServiceExportCondition{{
+			Type: mcsv1a1.ServiceExportValid, Status: corev1.ConditionFalse, Reason: ptr.To("ServiceUnavailable"),
+			Message: ptr.To("Service to be exported doesn't exist"),
+		}},
+		`ServiceExport "Valid" condition has status "False", reason "ServiceUnavailable" and message "Service to be exported`+
+			` doesn't exist", expected status "True", reason "" and a message containing ""`),
+	Entry("a Ready expectation met by a Ready condition, which takes precedence over Synced",
+		framework.ServiceExportConditionExpectation{Type: framework.ServiceExportReady, Status: corev1.ConditionTrue},
+		[]mcsv1a1.ServiceExportCondition{
+			{Type: framework.ServiceExportSynced, Status: corev1.ConditionFalse},
+			{Type: framework.ServiceExportReady, Status: corev1.ConditionTrue},
+		}, ""),
+	Entry("a Ready expectation met by a Synced condition from an older Lighthouse",
+		framework.ServiceExportConditionExpectation{Type: framework.ServiceExportReady, Status: corev1.ConditionTrue},
+		[]mcsv1a1.ServiceExportCondition{{Type: framework.ServiceExportSynced, Status: corev1.ConditionTrue}}, ""),
+	Entry("a Ready expectation unmet by a Synced condition",
+		framework.ServiceExportConditionExpectation{Type: framework.ServiceExportReady, Status: corev1.ConditionTrue},
+		[]mcsv1a1.ServiceExportCondition{{Type: framework.ServiceExportSynced, Status: corev1.ConditionFalse}},
+		`ServiceExport "Synced" condition has status "False", reason "" and message "", expected status "True", reason ""`+
+			` and a message containing ""`),
+	Entry("a Synced expectation, which isn't met by a Ready condition",
+		framework.ServiceExportConditionExpectation{Type: framework.ServiceExportSynced, Status: corev1.ConditionTrue},
+		[]mcsv1a1.ServiceExportCondition{{Type: framework.ServiceExportReady, Status: corev1.ConditionTrue}},
+		`ServiceExport has no "Synced" condition`),
+	Entry("a condition with the expected reason and message",
+		framework.ServiceExportConditionExpectation{
+			Type: mcsv1a1.ServiceExportConflict, Status: corev1.ConditionTrue, Reason: "ConflictingPorts",
+			MessageContains: "conflicting ports",
+		},
+		[]mcsv1a1.ServiceExportCondition{{
+			Type: mcsv1a1.ServiceExportConflict, Status: corev1.ConditionTrue, Reason: ptr.To("ConflictingPorts"),
+			Message: ptr.To("The service ports conflict between the constituent clusters: there are conflicting ports"),
+		}}, ""),
+	Entry("a condition with another reason",
+		framework.ServiceExportConditionExpectation{
+			Type: mcsv1a1.ServiceExportConflict, Status: corev1.ConditionTrue, Reason: "ConflictingPorts",
+		},
+		[]mcsv1a1.ServiceExportCondition{{
+			Type: mcsv1a1.ServiceExportConflict, Status: corev1.ConditionTrue, Reason: ptr.To("ConflictingType"),
+			Message: ptr.To("The service type conflicts"),
+		}},
+		`ServiceExport "Conflict" condition has status "True", reason "ConflictingType" and message "The service type conflicts",`+
+			` expected status "True", reason "ConflictingPorts" and a message containing ""`),
+	Entry("a condition whose message doesn't contain the expected text",
+		framework.ServiceExportConditionExpectation{
+			Type: mcsv1a1.ServiceExportValid, Status: corev1.ConditionFalse, MessageContains: "unsupported type",
+		},
+		[]mcsv1a1.ServiceExportCondition{{
+			Type: mcsv1a1.ServiceExportValid, Status: corev1.ConditionFalse, Reason: ptr.To("ServiceUnavailable"),
+			Message: ptr.To("Service to be exported doesn't exist"),
+		}},
+		`ServiceExport "Valid" condition has status "False", reason "ServiceUnavailable" and message "Service to be exported`+
+			` doesn't exist", expected status "False", reason "" and a message containing "unsupported type"`),
+	Entry("a condition without a reason nor a message, when they're expected",
+		framework.ServiceExportConditionExpectation{
+			Type: mcsv1a1.ServiceExportValid, Status: corev1.ConditionFalse, Reason: "UnsupportedServiceType",
+		},
+		[]mcsv1a1.ServiceExportCondition{{Type: mcsv1a1.ServiceExportValid, Status: corev1.ConditionFalse}},
+		`ServiceExport "Valid" condition has status "False", reason "" and message "", expected status "False",`+
+			` reason "UnsupportedServiceType" and a message containing ""`),
+)
@@ -0,0 +1,241 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	discovery "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	mcsv1a1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+)
+
+// labelSourceCluster is set by Lighthouse on the EndpointSlices of an exported service to the ID of the exporting cluster.
+const labelSourceCluster = "multicluster.kubernetes.io/source-cluster"
+
+var serviceImportGVR = schema.GroupVersionResource{
+	Group:    "multicluster.x-k8s.io",
+	Version:  "v1alpha1",
+	Resource: "serviceimports",
+}
+
+// AwaitServiceImport waits for the ServiceImport for the exported service with the given name to be present in the
+// framework namespace on the given cluster, with the given type and IPs. The IPs aren't checked if expectedIPs is nil,
+// e.g. for a ClusterSetIP import whose IP is allocated by Lighthouse.
+func (f *Framework) AwaitServiceImport(ctx context.Context, cluster ClusterIndex, name string, importType mcsv1a1.ServiceImportType,
+	expectedIPs []string,
+) *mcsv1a1.ServiceImport {
+	serviceImport, err := f.AwaitServiceImportOrError(ctx, cluster, name, importType, expectedIPs)
+	Expect(err).NotTo(HaveOccurred())
+
+	return serviceImport
+}
+
+// AwaitServiceImportOrError is like AwaitServiceImport but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitServiceImportOrError(ctx context.Context, cluster ClusterIndex, name string,
+	importType mcsv1a1.ServiceImportType, expectedIPs []string,
+) (*mcsv1a1.ServiceImport, error) {
+	expected := slices.Clone(expectedIPs)
+	sort.Strings(expected)
+
+	serviceImports := DynClients[cluster].Resource(serviceImportGVR).Namespace(f.Namespace)
+
+	result, err := awaitUntilOrError(ctx, fmt.Sprintf("await ServiceImport %q on cluster %q", name, TestContext.ClusterIDs[cluster]),
+		func() (interface{}, error) {
+			obj, err := serviceImports.Get(ctx, name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return nil, nil //nolint:nilnil // We want to repeat but let the checker known that nothing was found.
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			return toServiceImport(obj)
+		}, func(result interface{}) (bool, string, error) {
+			if result == nil {
+				return false, "ServiceImport not found", nil
+			}
+
+			serviceImport := result.(*mcsv1a1.ServiceImport)
+
+			if serviceImport.Spec.Type != importType {
+				return false, fmt.Sprintf("ServiceImport has type %q, expected %q", serviceImport.Spec.Type, importType), nil
+			}
+
+			ips := slices.Clone(serviceImport.Spec.IPs)
+			sort.Strings(ips)
+
+			if expectedIPs != nil && !slices.Equal(ips, expected) {
+				return false, fmt.Sprintf("ServiceImport has IPs %v, expected %v", ips, expected), nil
+			}
+
+			return true, "", nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*mcsv1a1.ServiceImport), nil
+}
+
+// AwaitServiceImportRemoved waits for the ServiceImport with the given name to be removed from the framework namespace on
+// the given cluster.
+func (f *Framework) AwaitServiceImportRemoved(ctx context.Context, cluster ClusterIndex, name string) {
+	Expect(f.AwaitServiceImportRemovedOrError(ctx, cluster, name)).To(Succeed())
+}
+
+// AwaitServiceImportRemovedOrError is like AwaitServiceImportRemoved but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitServiceImportRemovedOrError(ctx context.Context, cluster ClusterIndex, name string) error {
+	serviceImports := DynClients[cluster].Resource(serviceImportGVR).Namespace(f.Namespace)
+
+	_, err := awaitUntilOrError(ctx, fmt.Sprintf("await ServiceImport %q removed on cluster %q", name, TestContext.ClusterIDs[cluster]),
+		func() (interface{}, error) {
+			_, err := serviceImports.Get(ctx, name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+
+			return err == nil, err
+		}, func(result interface{}) (bool, string, error) {
+			if result.(bool) {
+				return false, "ServiceImport still exists", nil
+			}
+
+			return true, "", nil
+		})
+
+	return err
+}
+
+func toServiceImport(obj *unstructured.Unstructured) (*mcsv1a1.ServiceImport, error) {
+	serviceImport := &mcsv1a1.ServiceImport{}
+
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, serviceImport)
+
+	return serviceImport, errors.Wrapf(err, "error converting ServiceImport %q", obj.GetName())
+}
+
+// AwaitEndpointSlices waits for the EndpointSlices of the given service exported from sourceCluster to be present in the
+// framework namespace on the given cluster, with exactly the expected endpoint addresses, in any order. The addresses
+// aren't checked if expectedAddresses is nil, only that at least one EndpointSlice is present.
+func (f *Framework) AwaitEndpointSlices(ctx context.Context, cluster ClusterIndex, service string, sourceCluster ClusterIndex,
+	expectedAddresses []string,
+) []discovery.EndpointSlice {
+	endpointSlices, err := f.AwaitEndpointSlicesOrError(ctx, cluster, service, sourceCluster, expectedAddresses)
+	Expect(err).NotTo(HaveOccurred())
+
+	return endpointSlices
+}
+
+// AwaitEndpointSlicesOrError is like AwaitEndpointSlices but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitEndpointSlicesOrError(ctx context.Context, cluster ClusterIndex, service string, sourceCluster ClusterIndex,
+	expectedAddresses []string,
+) ([]discovery.EndpointSlice, error) {
+	expected := slices.Clone(expectedAddresses)
+	sort.Strings(expected)
+
+	result, err := awaitUntilOrError(ctx, fmt.Sprintf("await EndpointSlices for service %q from cluster %q on cluster %q", service,
+		TestContext.ClusterIDs[sourceCluster], TestContext.ClusterIDs[cluster]), func() (interface{}, error) {
+		return f.listEndpointSlices(ctx, cluster, service, sourceCluster)
+	}, func(result interface{}) (bool, string, error) {
+		endpointSlices := result.([]discovery.EndpointSlice)
+		if len(endpointSlices) == 0 {
+			return false, "no EndpointSlices found", nil
+		}
+
+		addresses := endpointAddresses(endpointSlices)
+
+		if expectedAddresses != nil && !slices.Equal(addresses, expected) {
+			return false, fmt.Sprintf("EndpointSlices have addresses %v, expected %v", addresses, expected), nil
+		}
+
+		return true, "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]discovery.EndpointSlice), nil
+}
+
+// AwaitEndpointSlicesRemoved waits for the EndpointSlices of the given service exported from sourceCluster to be removed
+// from the framework namespace on the given cluster.
+func (f *Framework) AwaitEndpointSlicesRemoved(ctx context.Context, cluster ClusterIndex, service string, sourceCluster ClusterIndex) {
+	Expect(f.AwaitEndpointSlicesRemovedOrError(ctx, cluster, service, sourceCluster)).To(Succeed())
+}
+
+// AwaitEndpointSlicesRemovedOrError is like AwaitEndpointSlicesRemoved but returns an error instead of failing via Gomega.
+func (f *Framework) AwaitEndpointSlicesRemovedOrError(ctx context.Context, cluster ClusterIndex, service string,
+	sourceCluster ClusterIndex,
+) error {
+	_, err := awaitUntilOrError(ctx, fmt.Sprintf("await EndpointSlices for service %q from cluster %q removed on cluster %q", service,
+		TestContext.ClusterIDs[sourceCluster], TestContext.ClusterIDs[cluster]), func() (interface{}, error) {
+		return f.listEndpointSlices(ctx, cluster, service, sourceCluster)
+	}, func(result interface{}) (bool, string, error) {
+		endpointSlices := result.([]discovery.EndpointSlice)
+		if len(endpointSlices) > 0 {
+			return false, fmt.Sprintf("%d EndpointSlice(s) still exist", len(endpointSlices)), nil
+		}
+
+		return true, "", nil
+	})
+
+	return err
+}
+
+func (f *Framework) listEndpointSlices(ctx context.Context, cluster ClusterIndex, service string, sourceCluster ClusterIndex,
+) ([]discovery.EndpointSlice, error) {
+	list, err := KubeClients[cluster].DiscoveryV1().EndpointSlices(f.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			mcsv1a1.LabelServiceName: service,
+			labelSourceCluster:       TestContext.ClusterIDs[sourceCluster],
+		}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+// endpointAddresses returns the sorted addresses of all the endpoints in the given EndpointSlices.
+func endpointAddresses(endpointSlices []discovery.EndpointSlice) []string {
+	addresses := []string{}
+
+	for i := range endpointSlices {
+		for j := range endpointSlices[i].Endpoints {
+			addresses = append(addresses, endpointSlices[i].Endpoints[j].Addresses...)
+		}
+	}
+
+	sort.Strings(addresses)
+
+	return addresses
+}
@@ -0,0 +1,228 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/framework/fake"
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	mcsv1a1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+)
+
+const testImportsNamespace = "e2e-tests-imports"
+
+var _ = Describe("ServiceImports", func() {
+	var (
+		env *fake.Environment
+		f   *framework.Framework
+	)
+
+	BeforeEach(func(ctx context.Context) {
+		env = fake.NewEnvironment(
+			fake.ClusterConfig{ID: "east", GatewayNodes: []string{"east-gw"}},
+			fake.ClusterConfig{ID: "west", GatewayNodes: []string{"west-gw"}},
+		)
+		DeferCleanup(env.Install())
+
+		_, err := fake.NewSimulator(env)
+		Expect(err).NotTo(HaveOccurred())
+
+		f = framework.NewBareFramework("imports")
+		f.Namespace = testImportsNamespace
+
+		_, err = env.Cluster(framework.ClusterA).KubeClient.CoreV1().Services(f.Namespace).Create(ctx, &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: f.Namespace},
+			Spec: v1.ServiceSpec{
+				ClusterIP: "100.90.1.7",
+				Ports:     []v1.ServicePort{{Name: "http", Port: 80, Protocol: v1.ProtocolTCP}},
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should fail to await a ServiceImport which doesn't exist", func(ctx context.Context) {
+		_, err := f.AwaitServiceImportOrError(ctx, framework.ClusterB, "nginx", mcsv1a1.ClusterSetIP, nil)
+		Expect(err).To(MatchError(ContainSubstring("ServiceImport not found")))
+	})
+
+	When("the service is exported", func() {
+		BeforeEach(func(ctx context.Context) {
+			Expect(f.CreateServiceExportOrError(ctx, framework.ClusterA, "nginx")).To(Succeed())
+		})
+
+		setImportIPs := func(ctx context.Context, ips ...interface{}) {
+			serviceImports := env.Cluster(framework.ClusterB).DynClient.Resource(fake.ServiceImportGVR).Namespace(f.Namespace)
+
+			serviceImport, err := serviceImports.Get(ctx, "nginx", metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unstructured.SetNestedSlice(serviceImport.Object, ips, "spec", "ips")).To(Succeed())
+
+			_, err = serviceImports.Update(ctx, serviceImport, metav1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("should return the ServiceImport on every cluster without checking its IPs if expectedIPs is nil", func(ctx context.Context) {
+			setImportIPs(ctx, "243.1.0.1")
+
+			for _, cluster := range []framework.ClusterIndex{framework.ClusterA, framework.ClusterB} {
+				serviceImport, err := f.AwaitServiceImportOrError(ctx, cluster, "nginx", mcsv1a1.ClusterSetIP, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(serviceImport.Status.Clusters).To(Equal([]mcsv1a1.ClusterStatus{{Cluster: "east"}}))
+			}
+		})
+
+		It("should require the ServiceImport to have no IPs if expectedIPs is empty", func(ctx context.Context) {
+			_, err := f.AwaitServiceImportOrError(ctx, framework.ClusterB, "nginx", mcsv1a1.ClusterSetIP, []string{})
+			Expect(err).NotTo(HaveOccurred())
+
+			setImportIPs(ctx, "243.1.0.1")
+
+			_, err = f.AwaitServiceImportOrError(ctx, framework.ClusterB, "nginx", mcsv1a1.ClusterSetIP, []string{})
+			Expect(err).To(MatchError(ContainSubstring("ServiceImport has IPs [243.1.0.1], expected []")))
+		})
+
+		It("should match the expected IPs in any order", func(ctx context.Context) {
+			setImportIPs(ctx, "243.1.0.2", "243.1.0.1")
+
+			serviceImport, err := f.AwaitServiceImportOrError(ctx, framework.ClusterB, "nginx", mcsv1a1.ClusterSetIP,
+				[]string{"243.1.0.1", "243.1.0.2"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceImport.Spec.IPs).To(ConsistOf("243.1.0.1", "243.1.0.2"))
+
+			_, err = f.AwaitServiceImportOrError(ctx, framework.ClusterB, "nginx", mcsv1a1.ClusterSetIP, []string{"243.1.0.1"})
+			Expect(err).To(MatchError(ContainSubstring("ServiceImport has IPs [243.1.0.1 243.1.0.2], expected [243.1.0.1]")))
+		})
+
+		It("should fail if the ServiceImport doesn't have the expected type", func(ctx context.Context) {
+			_, err := f.AwaitServiceImportOrError(ctx, framework.ClusterB, "nginx", mcsv1a1.Headless, nil)
+			Expect(err).To(MatchError(ContainSubstring(`ServiceImport has type "ClusterSetIP", expected "Headless"`)))
+		})
+
+		It("should await the ServiceImport removal once the service is unexported", func(ctx context.Context) {
+			Expect(f.AwaitServiceImportRemovedOrError(ctx, framework.ClusterB, "nginx")).To(
+				MatchError(ContainSubstring("ServiceImport still exists")))
+
+			f.DeleteServiceExport(ctx, framework.ClusterA, "nginx")
+
+			Expect(f.AwaitServiceImportRemovedOrError(ctx, framework.ClusterB, "nginx")).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("EndpointSlices", func() {
+	var f *framework.Framework
+
+	newEndpointSlice := func(name, service, sourceCluster string, addresses ...[]string) *discovery.EndpointSlice {
+		endpointSlice := &discovery.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testImportsNamespace,
+				Labels: map[string]string{
+					mcsv1a1.LabelServiceName:                    service,
+					"multicluster.kubernetes.io/source-cluster": sourceCluster,
+				},
+			},
+			AddressType: discovery.AddressTypeIPv4,
+		}
+
+		for i := range addresses {
+			endpointSlice.Endpoints = append(endpointSlice.Endpoints, discovery.Endpoint{Addresses: addresses[i]})
+		}
+
+		return endpointSlice
+	}
+
+	names := func(endpointSlices []discovery.EndpointSlice) []string {
+		names := []string{}
+		for i := range endpointSlices {
+			names = append(names, endpointSlices[i].Name)
+		}
+
+		return names
+	}
+
+	BeforeEach(func() {
+		env := fake.NewEnvironment(fake.ClusterConfig{ID: "east"}, fake.ClusterConfig{ID: "west"})
+		DeferCleanup(env.Install())
+
+		f = framework.NewBareFramework("imports")
+		f.Namespace = testImportsNamespace
+
+		Expect(env.Cluster(framework.ClusterA).KubeClient.Tracker().Add(
+			newEndpointSlice("nginx-west-1", "nginx", "west", []string{"10.2.0.8"}, []string{"10.2.0.7"}))).To(Succeed())
+		Expect(env.Cluster(framework.ClusterA).KubeClient.Tracker().Add(
+			newEndpointSlice("nginx-west-2", "nginx", "west", []string{"10.2.0.10"}))).To(Succeed())
+		Expect(env.Cluster(framework.ClusterA).KubeClient.Tracker().Add(
+			newEndpointSlice("nginx-east", "nginx", "east", []string{"10.1.0.5"}))).To(Succeed())
+		Expect(env.Cluster(framework.ClusterA).KubeClient.Tracker().Add(
+			newEndpointSlice("apache-west", "apache", "west", []string{"10.2.0.20"}))).To(Succeed())
+	})
+
+	It("should only return the EndpointSlices of the service from the source cluster", func(ctx context.Context) {
+		endpointSlices, err := f.AwaitEndpointSlicesOrError(ctx, framework.ClusterA, "nginx", framework.ClusterB,
+			[]string{"10.2.0.10", "10.2.0.7", "10.2.0.8"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(endpointSlices)).To(ConsistOf("nginx-west-1", "nginx-west-2"))
+
+		endpointSlices, err = f.AwaitEndpointSlicesOrError(ctx, framework.ClusterA, "nginx", framework.ClusterA, []string{"10.1.0.5"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(endpointSlices)).To(Equal([]string{"nginx-east"}))
+	})
+
+	It("should not check the addresses if expectedAddresses is nil", func(ctx context.Context) {
+		endpointSlices, err := f.AwaitEndpointSlicesOrError(ctx, framework.ClusterA, "apache", framework.ClusterB, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(endpointSlices)).To(Equal([]string{"apache-west"}))
+	})
+
+	It("should fail if the addresses differ from the expected ones", func(ctx context.Context) {
+		_, err := f.AwaitEndpointSlicesOrError(ctx, framework.ClusterA, "nginx", framework.ClusterB, []string{})
+		Expect(err).To(MatchError(ContainSubstring("EndpointSlices have addresses [10.2.0.10 10.2.0.7 10.2.0.8], expected []")))
+	})
+
+	It("should fail if there are no EndpointSlices, even if expectedAddresses is nil", func(ctx context.Context) {
+		_, err := f.AwaitEndpointSlicesOrError(ctx, framework.ClusterA, "apache", framework.ClusterA, nil)
+		Expect(err).To(MatchError(ContainSubstring("no EndpointSlices found")))
+	})
+
+	It("should await the removal of the EndpointSlices of the service from the source cluster", func(ctx context.Context) {
+		Expect(f.AwaitEndpointSlicesRemovedOrError(ctx, framework.ClusterA, "apache", framework.ClusterA)).To(Succeed())
+		Expect(f.AwaitEndpointSlicesRemovedOrError(ctx, framework.ClusterA, "nginx", framework.ClusterB)).To(
+			MatchError(ContainSubstring("2 EndpointSlice(s) still exist")))
+	})
+})
+
+var _ = DescribeTable("endpointAddresses",
+	func(endpointSlices []discovery.EndpointSlice, expected []string) {
+		Expect(framework.EndpointAddresses(endpointSlices)).To(Equal(expected))
+	},
+	Entry("no EndpointSlices", nil, []string{}),
+	Entry("EndpointSlices without endpoints", []discovery.EndpointSlice{{}, {Endpoints: []discovery.Endpoint{}}}, []string{}),
+	Entry("endpoints with several addresses, across EndpointSlices", []discovery.EndpointSlice{
+		{Endpoints: []discovery.Endpoint{{Addresses: []string{"10.2.0.8", "10.2.0.3"}}, {Addresses: []string{"10.2.0.12"}}}},
+		{Endpoints: []discovery.Endpoint{{Addresses: []string{"10.1.0.5"}}}},
+	}, []string{"10.1.0.5", "10.2.0.12", "10.2.0.3", "10.2.0.8"}),
+)
@@ -21,7 +21,9 @@ package framework
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -62,23 +64,98 @@ func (f *Framework) NewService(name, portName string, port int32, protocol corev
 	return &service
 }
 
-func (f *Framework) CreateTCPService(cluster ClusterIndex, selectorName string, port int32) *corev1.Service {
-	tcpService := f.NewService(fmt.Sprintf("test-svc-%s", selectorName), "tcp", port, corev1.ProtocolTCP,
-		map[string]string{TestAppLabel: selectorName}, false)
+func (f *Framework) CreateTCPService(ctx context.Context, cluster ClusterIndex, selectorName string, port int32) *corev1.Service {
+	service, err := f.CreateTCPServiceOrError(ctx, cluster, selectorName, port)
+	Expect(err).NotTo(HaveOccurred())
+
+	return service
+}
+
+// CreateTCPServiceOrError is like CreateTCPService but returns an error instead of failing via Gomega.
+func (f *Framework) CreateTCPServiceOrError(ctx context.Context, cluster ClusterIndex, selectorName string, port int32,
+) (*corev1.Service, error) {
+	return f.CreateTCPServiceForIPFamilyOrError(ctx, cluster, selectorName, port, "")
+}
+
+// CreateTCPServiceForIPFamilyOrError is like CreateTCPServiceOrError but creates a single-stack service of the given IP
+// family, or of the cluster's default family if empty.
+func (f *Framework) CreateTCPServiceForIPFamilyOrError(ctx context.Context, cluster ClusterIndex, selectorName string, port int32,
+	family corev1.IPFamily,
+) (*corev1.Service, error) {
+	return f.createTestAppServiceOrError(ctx, cluster, selectorName, port, corev1.ProtocolTCP, family, false)
+}
+
+// CreateUDPService creates a UDP service for the pods with the given test app label, of the given IP family, or of the
+// cluster's default family if empty.
+func (f *Framework) CreateUDPService(ctx context.Context, cluster ClusterIndex, selectorName string, port int32,
+	family corev1.IPFamily,
+) *corev1.Service {
+	service, err := f.CreateUDPServiceOrError(ctx, cluster, selectorName, port, family)
+	Expect(err).NotTo(HaveOccurred())
+
+	return service
+}
+
+// CreateUDPServiceOrError is like CreateUDPService but returns an error instead of failing via Gomega.
+func (f *Framework) CreateUDPServiceOrError(ctx context.Context, cluster ClusterIndex, selectorName string, port int32,
+	family corev1.IPFamily,
+) (*corev1.Service, error) {
+	return f.createTestAppServiceOrError(ctx, cluster, selectorName, port, corev1.ProtocolUDP, family, false)
+}
+
+// CreateHeadlessUDPServiceOrError is like CreateUDPServiceOrError but creates a headless service.
+func (f *Framework) CreateHeadlessUDPServiceOrError(ctx context.Context, cluster ClusterIndex, selectorName string, port int32,
+	family corev1.IPFamily,
+) (*corev1.Service, error) {
+	return f.createTestAppServiceOrError(ctx, cluster, selectorName, port, corev1.ProtocolUDP, family, true)
+}
+
+// CreateSCTPServiceOrError creates an SCTP service for the pods with the given test app label, of the given IP family,
+// or of the cluster's default family if empty.
+func (f *Framework) CreateSCTPServiceOrError(ctx context.Context, cluster ClusterIndex, selectorName string, port int32,
+	family corev1.IPFamily,
+) (*corev1.Service, error) {
+	return f.createTestAppServiceOrError(ctx, cluster, selectorName, port, corev1.ProtocolSCTP, family, false)
+}
+
+// CreateHeadlessSCTPServiceOrError is like CreateSCTPServiceOrError but creates a headless service.
+func (f *Framework) CreateHeadlessSCTPServiceOrError(ctx context.Context, cluster ClusterIndex, selectorName string, port int32,
+	family corev1.IPFamily,
+) (*corev1.Service, error) {
+	return f.createTestAppServiceOrError(ctx, cluster, selectorName, port, corev1.ProtocolSCTP, family, true)
+}
+
+// testAppServiceName returns the name of the service selecting the pods with the given TestAppLabel.
+func testAppServiceName(selectorName string) string {
+	return "test-svc-" + selectorName
+}
+
+func (f *Framework) createTestAppServiceOrError(ctx context.Context, cluster ClusterIndex, selectorName string, port int32,
+	protocol corev1.Protocol, family corev1.IPFamily, isHeadless bool,
+) (*corev1.Service, error) {
+	service := f.NewService(testAppServiceName(selectorName), strings.ToLower(string(protocol)), port, protocol,
+		map[string]string{TestAppLabel: selectorName}, isHeadless)
+
+	if family != "" {
+		singleStack := corev1.IPFamilyPolicySingleStack
+		service.Spec.IPFamilyPolicy = &singleStack
+		service.Spec.IPFamilies = []corev1.IPFamily{family}
+	}
+
 	sc := KubeClients[cluster].CoreV1().Services(f.Namespace)
 
-	return f.CreateService(sc, tcpService)
+	return f.CreateServiceOrError(ctx, sc, service)
 }
 
-func (f *Framework) CreateHeadlessTCPService(cluster ClusterIndex, selectorName string, port int32) *corev1.Service {
+func (f *Framework) CreateHeadlessTCPService(ctx context.Context, cluster ClusterIndex, selectorName string, port int32) *corev1.Service {
 	tcpService := f.NewService(fmt.Sprintf("test-svc-%s", selectorName), "tcp", port, corev1.ProtocolTCP,
 		map[string]string{TestAppLabel: selectorName}, true)
 	sc := KubeClients[cluster].CoreV1().Services(f.Namespace)
 
-	return f.CreateService(sc, tcpService)
+	return f.CreateService(ctx, sc, tcpService)
 }
 
-func (f *Framework) NewNginxService(cluster ClusterIndex) *corev1.Service {
+func (f *Framework) NewNginxService(ctx context.Context, cluster ClusterIndex) *corev1.Service {
 	var tcpPort int32 = 80
 	var metricsPort int32 = 8183
 	nginxService := corev1.Service{
@@ -118,35 +195,51 @@ func (f *Framework) NewNginxService(cluster ClusterIndex) *corev1.Service {
 
 	sc := KubeClients[cluster].CoreV1().Services(f.Namespace)
 
-	return f.CreateService(sc, &nginxService)
+	return f.CreateService(ctx, sc, &nginxService)
 }
 
-func (f *Framework) CreateTCPServiceWithoutSelector(cluster ClusterIndex, svcName, portName string, port int32) *corev1.Service {
+func (f *Framework) CreateTCPServiceWithoutSelector(ctx context.Context, cluster ClusterIndex, svcName, portName string,
+	port int32,
+) *corev1.Service {
 	serviceSpec := f.NewService(svcName, portName, port, corev1.ProtocolTCP, nil, false)
 	sc := KubeClients[cluster].CoreV1().Services(f.Namespace)
 
-	return f.CreateService(sc, serviceSpec)
+	return f.CreateService(ctx, sc, serviceSpec)
+}
+
+func (f *Framework) CreateService(ctx context.Context, sc typedv1.ServiceInterface, serviceSpec *corev1.Service) *corev1.Service {
+	service, err := f.CreateServiceOrError(ctx, sc, serviceSpec)
+	Expect(err).NotTo(HaveOccurred())
+
+	return service
 }
 
-func (f *Framework) CreateService(sc typedv1.ServiceInterface, serviceSpec *corev1.Service) *corev1.Service {
-	return AwaitUntil("create service", func() (interface{}, error) {
-		service, err := sc.Create(context.TODO(), serviceSpec, metav1.CreateOptions{})
+// CreateServiceOrError is like CreateService but returns an error instead of failing via Gomega.
+func (f *Framework) CreateServiceOrError(ctx context.Context, sc typedv1.ServiceInterface, serviceSpec *corev1.Service,
+) (*corev1.Service, error) {
+	result, err := awaitUntilOrError(ctx, "create service", func() (interface{}, error) {
+		service, err := sc.Create(ctx, serviceSpec, metav1.CreateOptions{})
 		if apierrors.IsAlreadyExists(err) {
-			err = sc.Delete(context.TODO(), serviceSpec.Name, metav1.DeleteOptions{})
+			err = sc.Delete(ctx, serviceSpec.Name, metav1.DeleteOptions{})
 			if err != nil {
 				return nil, err
 			}
 
-			service, err = sc.Create(context.TODO(), serviceSpec, metav1.CreateOptions{})
+			service, err = sc.Create(ctx, serviceSpec, metav1.CreateOptions{})
 		}
 
 		return service, err
-	}, NoopCheckResult).(*corev1.Service)
+	}, NoopCheckResult)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*corev1.Service), nil
 }
 
-func (f *Framework) DeleteService(cluster ClusterIndex, serviceName string) {
+func (f *Framework) DeleteService(ctx context.Context, cluster ClusterIndex, serviceName string) {
 	By(fmt.Sprintf("Deleting service %q on %q", serviceName, TestContext.ClusterIDs[cluster]))
-	AwaitUntil("delete service", func() (interface{}, error) {
-		return nil, KubeClients[cluster].CoreV1().Services(f.Namespace).Delete(context.TODO(), serviceName, metav1.DeleteOptions{})
+	AwaitUntil(ctx, "delete service", func() (interface{}, error) {
+		return nil, KubeClients[cluster].CoreV1().Services(f.Namespace).Delete(ctx, serviceName, metav1.DeleteOptions{})
 	}, NoopCheckResult)
 }
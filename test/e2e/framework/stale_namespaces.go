@@ -0,0 +1,103 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8serrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// StaleNamespace describes a namespace left behind by a previous run, e.g. one that was interrupted.
+type StaleNamespace struct {
+	ClusterID string
+	Name      string
+	Age       time.Duration
+}
+
+// ReapStaleNamespaces deletes, on every cluster, the namespaces created by the framework that are older than
+// TestContext.StaleNamespaceAge, and returns them. Nothing is done if StaleNamespaceAge is zero. In dry-run mode, per
+// TestContext.StaleNamespaceDryRun, the stale namespaces are only reported.
+func ReapStaleNamespaces(ctx context.Context) []StaleNamespace {
+	reaped, err := ReapStaleNamespacesOrError(ctx)
+	Expect(err).NotTo(HaveOccurred())
+
+	return reaped
+}
+
+// ReapStaleNamespacesOrError is like ReapStaleNamespaces but returns an error instead of failing via Gomega. The
+// namespaces reaped before an error occurred are also returned.
+func ReapStaleNamespacesOrError(ctx context.Context) ([]StaleNamespace, error) {
+	maxAge := TestContext.StaleNamespaceAge.Duration
+	if maxAge <= 0 {
+		return nil, nil
+	}
+
+	action := "Deleting"
+	if TestContext.StaleNamespaceDryRun {
+		action = "Dry run - not deleting"
+	}
+
+	// Selects the namespaces having the label, whatever its value.
+	selector := e2eFrameworkLabel
+	reaped := []StaleNamespace{}
+
+	var errs []error
+
+	for i, clientSet := range KubeClients {
+		clusterID := TestContext.ClusterIDs[i]
+
+		namespaces, err := clientSet.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error listing namespaces on cluster %q", clusterID))
+			continue
+		}
+
+		for j := range namespaces.Items {
+			ns := &namespaces.Items[j]
+
+			age := time.Since(ns.CreationTimestamp.Time)
+			if ns.DeletionTimestamp != nil || age < maxAge {
+				continue
+			}
+
+			By(fmt.Sprintf("%s stale namespace %q on cluster %q, created %v ago", action, ns.Name, clusterID, age.Round(time.Second)))
+
+			if !TestContext.StaleNamespaceDryRun {
+				err := deleteNamespace(ctx, clientSet, ns.Name)
+				if err != nil && !apierrors.IsNotFound(err) {
+					errs = append(errs, errors.Wrapf(err, "error deleting stale namespace %q on cluster %q", ns.Name, clusterID))
+					continue
+				}
+			}
+
+			reaped = append(reaped, StaleNamespace{ClusterID: clusterID, Name: ns.Name, Age: age})
+		}
+	}
+
+	By(fmt.Sprintf("Found %d stale namespace(s) older than %v", len(reaped), maxAge))
+
+	return reaped, k8serrors.NewAggregate(errs)
+}
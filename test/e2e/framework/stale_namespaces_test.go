@@ -0,0 +1,139 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/framework/fake"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("ReapStaleNamespaces", func() {
+	var env *fake.Environment
+
+	namespace := func(name string, age time.Duration, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Labels:            labels,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		}}
+	}
+
+	frameworkLabels := map[string]string{"e2e-framework": "dataplane"}
+
+	BeforeEach(func() {
+		terminating := namespace("e2e-tests-terminating", 3*time.Hour, frameworkLabels)
+		terminating.DeletionTimestamp = ptr.To(metav1.Now())
+		terminating.Finalizers = []string{"kubernetes"}
+
+		env = fake.NewEnvironment(
+			fake.ClusterConfig{ID: "east", KubeObjects: []runtime.Object{
+				namespace("e2e-tests-old", 2*time.Hour, frameworkLabels),
+				namespace("e2e-tests-recent", time.Minute, frameworkLabels),
+				namespace("old-unlabeled", 2*time.Hour, nil),
+				terminating,
+			}},
+			fake.ClusterConfig{ID: "west", KubeObjects: []runtime.Object{
+				namespace("e2e-tests-old-west", 5*time.Hour, map[string]string{"e2e-framework": ""}),
+			}},
+		)
+		DeferCleanup(env.Install())
+
+		framework.TestContext.StaleNamespaceAge = metav1.Duration{Duration: time.Hour}
+		framework.TestContext.StaleNamespaceDryRun = false
+	})
+
+	namespaceExists := func(ctx context.Context, cluster framework.ClusterIndex, name string) bool {
+		_, err := env.Cluster(cluster).KubeClient.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+
+		Expect(err).NotTo(HaveOccurred())
+
+		return true
+	}
+
+	names := func(reaped []framework.StaleNamespace) []string {
+		result := []string{}
+		for i := range reaped {
+			result = append(result, reaped[i].ClusterID+"/"+reaped[i].Name)
+		}
+
+		return result
+	}
+
+	It("should delete the framework's namespaces older than the stale age", func(ctx context.Context) {
+		reaped, err := framework.ReapStaleNamespacesOrError(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(reaped)).To(ConsistOf("east/e2e-tests-old", "west/e2e-tests-old-west"))
+		Expect(reaped[0].Age).To(BeNumerically(">=", time.Hour))
+
+		Expect(namespaceExists(ctx, framework.ClusterA, "e2e-tests-old")).To(BeFalse())
+		Expect(namespaceExists(ctx, framework.ClusterB, "e2e-tests-old-west")).To(BeFalse())
+	})
+
+	It("should keep the recent, terminating and unlabeled namespaces", func(ctx context.Context) {
+		Expect(framework.ReapStaleNamespacesOrError(ctx)).Error().NotTo(HaveOccurred())
+
+		Expect(namespaceExists(ctx, framework.ClusterA, "e2e-tests-recent")).To(BeTrue())
+		Expect(namespaceExists(ctx, framework.ClusterA, "e2e-tests-terminating")).To(BeTrue())
+		Expect(namespaceExists(ctx, framework.ClusterA, "old-unlabeled")).To(BeTrue())
+	})
+
+	It("should only report the stale namespaces in dry-run mode", func(ctx context.Context) {
+		framework.TestContext.StaleNamespaceDryRun = true
+
+		reaped, err := framework.ReapStaleNamespacesOrError(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(reaped)).To(ConsistOf("east/e2e-tests-old", "west/e2e-tests-old-west"))
+
+		Expect(namespaceExists(ctx, framework.ClusterA, "e2e-tests-old")).To(BeTrue())
+		Expect(namespaceExists(ctx, framework.ClusterB, "e2e-tests-old-west")).To(BeTrue())
+	})
+
+	It("should do nothing if the stale age isn't set", func(ctx context.Context) {
+		framework.TestContext.StaleNamespaceAge = metav1.Duration{}
+
+		Expect(framework.ReapStaleNamespacesOrError(ctx)).To(BeEmpty())
+		Expect(namespaceExists(ctx, framework.ClusterA, "e2e-tests-old")).To(BeTrue())
+	})
+
+	It("should return the namespaces reaped before an error", func(ctx context.Context) {
+		env.Cluster(framework.ClusterA).KubeClient.PrependReactor("delete", "namespaces",
+			func(_ testing.Action) (bool, runtime.Object, error) {
+				return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "e2e-tests-old", nil)
+			})
+
+		reaped, err := framework.ReapStaleNamespacesOrError(ctx)
+		Expect(err).To(MatchError(ContainSubstring(`error deleting stale namespace "e2e-tests-old" on cluster "east"`)))
+		Expect(names(reaped)).To(Equal([]string{"west/e2e-tests-old-west"}))
+	})
+})
@@ -0,0 +1,97 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+// SuiteState is the state discovered from the clusters by BeforeSuite. With parallel Ginkgo nodes, BeforeSuite only runs
+// on the first node, which shares the state with the others via SynchronizedBeforeSuite. State set by the functions
+// registered with AddBeforeSuite isn't included.
+type SuiteState struct {
+	ClusterIDs        []string             `json:"clusterIDs"`
+	GlobalnetEnabled  bool                 `json:"globalnetEnabled"`
+	NumNodesInCluster map[ClusterIndex]int `json:"numNodesInCluster,omitempty"`
+	Clusters          []ClusterInfo        `json:"clusters"`
+	UseSeccompProfile bool                 `json:"useSeccompProfile"`
+}
+
+// SaveSuiteState serializes the state discovered by BeforeSuite, to be passed to RestoreSuiteState on every Ginkgo node.
+func SaveSuiteState() []byte {
+	data, err := SaveSuiteStateOrError()
+	Expect(err).NotTo(HaveOccurred())
+
+	return data
+}
+
+// SaveSuiteStateOrError is like SaveSuiteState but returns an error instead of failing via Gomega.
+func SaveSuiteStateOrError() ([]byte, error) {
+	state := &SuiteState{
+		ClusterIDs:        TestContext.ClusterIDs,
+		GlobalnetEnabled:  TestContext.GlobalnetEnabled,
+		NumNodesInCluster: TestContext.NumNodesInCluster,
+		Clusters:          clusterInfos,
+		UseSeccompProfile: podSecurityContext != nil && podSecurityContext.SeccompProfile != nil,
+	}
+
+	data, err := json.Marshal(state)
+
+	return data, errors.Wrap(err, "error marshaling the suite state")
+}
+
+// RestoreSuiteState creates the kubernetes clients, if needed, and initializes the TestContext from the state serialized
+// by SaveSuiteState on the first Ginkgo node, without querying the clusters again.
+func RestoreSuiteState(data []byte) {
+	Expect(RestoreSuiteStateOrError(data)).To(Succeed())
+}
+
+// RestoreSuiteStateOrError is like RestoreSuiteState but returns an error instead of failing via Gomega.
+func RestoreSuiteStateOrError(data []byte) error {
+	state := &SuiteState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return errors.Wrap(err, "error unmarshaling the suite state")
+	}
+
+	if len(KubeClients) == 0 || len(KubeClients) != len(DynClients) {
+		if err := initClients(); err != nil {
+			return err
+		}
+	}
+
+	if len(state.ClusterIDs) != len(KubeClients) {
+		return errors.Errorf("the suite state has %d cluster IDs but %d clusters are configured", len(state.ClusterIDs),
+			len(KubeClients))
+	}
+
+	if err := addToScheme(); err != nil {
+		return err
+	}
+
+	TestContext.ClusterIDs = state.ClusterIDs
+	TestContext.GlobalnetEnabled = state.GlobalnetEnabled
+	TestContext.NumNodesInCluster = state.NumNodesInCluster
+	clusterInfos = state.Clusters
+	podSecurityContext = newPodSecurityContext(state.UseSeccompProfile)
+
+	return nil
+}
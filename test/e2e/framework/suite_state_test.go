@@ -0,0 +1,83 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/framework/fake"
+)
+
+var _ = Describe("Suite state", func() {
+	var data []byte
+
+	BeforeEach(func(ctx context.Context) {
+		DeferCleanup(fake.NewEnvironment(
+			fake.ClusterConfig{ID: "east", GatewayNodes: []string{"east-gw1", "east-gw2"}, Broker: true, GlobalCIDR: "242.0.0.0/16"},
+			fake.ClusterConfig{ID: "west", GatewayNodes: []string{"west-gw"}, NonGatewayNodes: []string{"west-worker"}},
+		).Install())
+		DeferCleanup(framework.SetClusterInfos, []framework.ClusterInfo(nil))
+
+		Expect(framework.BeforeSuiteOrError(ctx)).To(Succeed())
+		Expect(framework.DetectGlobalnetOrError(ctx)).To(Succeed())
+		Expect(framework.InitNumClusterNodes(ctx)).To(Succeed())
+
+		var err error
+
+		data, err = framework.SaveSuiteStateOrError()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should restore the discovered state on another node", func() {
+		f := framework.NewBareFramework("test")
+		clusters := f.Clusters()
+
+		// Another node only has the clients, set up from the same configuration.
+		framework.TestContext.ClusterIDs = nil
+		framework.TestContext.GlobalnetEnabled = false
+		framework.TestContext.NumNodesInCluster = nil
+		framework.SetClusterInfos(nil)
+
+		Expect(framework.RestoreSuiteStateOrError(data)).To(Succeed())
+
+		Expect(framework.TestContext.ClusterIDs).To(Equal([]string{"east", "west"}))
+		Expect(framework.TestContext.GlobalnetEnabled).To(BeTrue())
+		Expect(framework.TestContext.NumNodesInCluster).To(Equal(map[framework.ClusterIndex]int{
+			framework.ClusterA: 2,
+			framework.ClusterB: 2,
+		}))
+		Expect(f.Clusters()).To(Equal(clusters))
+		Expect(f.ClustersWithRoles(framework.BrokerRole)).To(Equal([]framework.ClusterIndex{framework.ClusterA}))
+
+		Expect(framework.SaveSuiteStateOrError()).To(Equal(data))
+	})
+
+	It("should return an error if the state doesn't match the configured clusters", func() {
+		DeferCleanup(fake.NewEnvironment(fake.ClusterConfig{ID: "east"}).Install())
+
+		Expect(framework.RestoreSuiteStateOrError(data)).To(MatchError(ContainSubstring("2 cluster IDs but 1 clusters")))
+	})
+
+	It("should return an error if the state can't be parsed", func() {
+		Expect(framework.RestoreSuiteStateOrError([]byte("{"))).To(MatchError(ContainSubstring("error unmarshaling the suite state")))
+	})
+})
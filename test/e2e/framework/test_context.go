@@ -25,30 +25,51 @@ import (
 	"time"
 
 	"github.com/onsi/ginkgo/v2/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
 )
 
 type contextArray []string
 
+// TestContextType holds the suite configuration. It can be loaded from a YAML or JSON file specified by the --config
+// flag, using the field names in the json tags. Fields tagged with "-" are discovered from the clusters.
 type TestContextType struct {
-	ReporterConfig      *types.ReporterConfig
-	SuiteConfig         *types.SuiteConfig
-	KubeConfigs         []string // KubeConfigs provides an alternative to KubeConfig + KubeContexts
-	KubeConfig          string
-	KubeContexts        contextArray
-	ClusterIDs          []string
-	NumNodesInCluster   map[ClusterIndex]int
-	SubmarinerNamespace string
-	ConnectionTimeout   uint
-	ConnectionAttempts  uint
-	OperationTimeout    uint
-	PacketSize          uint
-	GlobalnetEnabled    bool
-	ClientQPS           float32
-	ClientBurst         int
-	GroupVersion        *schema.GroupVersion
-	NettestImageURL     string
+	ReporterConfig      *types.ReporterConfig `json:"reporterConfig,omitempty"`
+	SuiteConfig         *types.SuiteConfig    `json:"suiteConfig,omitempty"`
+	KubeConfigs         []string              `json:"kubeConfigs,omitempty"` // Alternative to KubeConfig + KubeContexts
+	KubeConfig          string                `json:"kubeConfig,omitempty"`
+	KubeContexts        contextArray          `json:"kubeContexts,omitempty"`
+	ClusterIDs          []string              `json:"clusterIDs,omitempty"`
+	NumNodesInCluster   map[ClusterIndex]int  `json:"-"`
+	SubmarinerNamespace string                `json:"submarinerNamespace,omitempty"`
+	BrokerNamespace     string                `json:"brokerNamespace,omitempty"`
+	ConnectionTimeout   uint                  `json:"connectionTimeout,omitempty"`
+	ConnectionAttempts  uint                  `json:"connectionAttempts,omitempty"`
+	OperationTimeout    uint                  `json:"operationTimeout,omitempty"`
+	PacketSize          uint                  `json:"packetSize,omitempty"`
+	GlobalnetEnabled    bool                  `json:"-"`
+	ClientQPS           float32               `json:"clientQPS,omitempty"`
+	ClientBurst         int                   `json:"clientBurst,omitempty"`
+	GroupVersion        *schema.GroupVersion  `json:"groupVersion,omitempty"`
+	NettestImageURL     string                `json:"nettestImageURL,omitempty"`
+
+	DeleteNamespace          bool `json:"deleteNamespace"`
+	DeleteNamespaceOnFailure bool `json:"deleteNamespaceOnFailure"`
+	WaitForNamespaceDeletion bool `json:"waitForNamespaceDeletion"`
+
+	StaleNamespaceAge    metav1.Duration `json:"staleNamespaceAge,omitempty"`
+	StaleNamespaceDryRun bool            `json:"staleNamespaceDryRun,omitempty"`
+
+	ArtifactsDir string `json:"artifactsDir,omitempty"`
+	WatchEvents  bool   `json:"watchEvents"`
+
+	BenchmarkRepetitions         uint    `json:"benchmarkRepetitions,omitempty"`
+	BenchmarkBaseline            string  `json:"benchmarkBaseline,omitempty"`
+	BenchmarkRefreshBaseline     bool    `json:"benchmarkRefreshBaseline,omitempty"`
+	BenchmarkThroughputTolerance float64 `json:"benchmarkThroughputTolerance,omitempty"`
+	BenchmarkLatencyTolerance    float64 `json:"benchmarkLatencyTolerance,omitempty"`
+	BenchmarkFailOnRegression    bool    `json:"benchmarkFailOnRegression,omitempty"`
 }
 
 func (contexts *contextArray) String() string {
@@ -60,26 +81,68 @@ func (contexts *contextArray) Set(value string) error {
 	return nil
 }
 
-var TestContext = &TestContextType{
-	ClientQPS:       20,
-	ClientBurst:     50,
-	NettestImageURL: "quay.io/submariner/nettest:devel",
-}
+var (
+	TestContext = &TestContextType{
+		ClientQPS:       20,
+		ClientBurst:     50,
+		NettestImageURL: "quay.io/submariner/nettest:devel",
+	}
+
+	configFile string
+)
 
 func init() {
+	flag.StringVar(&configFile, "config", os.Getenv(configFileEnvVar),
+		"Path to a YAML or JSON file with the TestContext configuration. Environment variables and command-line flags take precedence.")
 	flag.StringVar(&TestContext.KubeConfig, "kubeconfig", os.Getenv("KUBECONFIG"),
 		"Path to kubeconfig containing embedded authinfo.")
 	flag.Var(&TestContext.KubeContexts, "dp-context", "kubeconfig context for dataplane clusters (use several times).")
 	flag.StringVar(&TestContext.SubmarinerNamespace, "submariner-namespace", "submariner",
 		"Namespace in which the submariner components are deployed.")
+	flag.StringVar(&TestContext.BrokerNamespace, "broker-namespace", "submariner-k8s-broker",
+		"Namespace of the broker, used to identify the cluster(s) hosting the broker.")
 	flag.UintVar(&TestContext.ConnectionTimeout, "connection-timeout", 18,
 		"The timeout in seconds per connection attempt when verifying communication between clusters.")
 	flag.UintVar(&TestContext.ConnectionAttempts, "connection-attempts", 7,
 		"The number of connection attempts when verifying communication between clusters.")
 	flag.UintVar(&TestContext.OperationTimeout, "operation-timeout", 190, "The general operation timeout in seconds.")
+	flag.BoolVar(&TestContext.DeleteNamespace, "delete-namespace", true,
+		"If true, the namespaces created by the tests are deleted after each test (unless delete-namespace-on-failure is false "+
+			"and the test failed).")
+	flag.BoolVar(&TestContext.DeleteNamespaceOnFailure, "delete-namespace-on-failure", true,
+		"If false, the namespaces created by a failed test are preserved for inspection.")
+	flag.BoolVar(&TestContext.WaitForNamespaceDeletion, "wait-for-namespace-deletion", false,
+		"If true, wait until the namespaces created by each test are fully deleted, within the framework's NamespaceDeletionTimeout.")
+	flag.DurationVar(&TestContext.StaleNamespaceAge.Duration, "stale-namespace-age", 0,
+		"If set, namespaces created by the framework that are older than this are deleted at the start of the suite. "+
+			"Beware of concurrent runs against the same clusters.")
+	flag.BoolVar(&TestContext.StaleNamespaceDryRun, "stale-namespace-dry-run", false,
+		"If true, stale namespaces are only reported, not deleted.")
+	flag.StringVar(&TestContext.ArtifactsDir, "artifacts-dir", "",
+		"If set, diagnostics about each failed test are collected in a subdirectory of this directory.")
+	flag.BoolVar(&TestContext.WatchEvents, "watch-events", true,
+		"If true, the events in the test and Submariner namespaces are logged while each test runs and attached to its report.")
+	flag.UintVar(&TestContext.BenchmarkRepetitions, "benchmark-repetitions", 0,
+		"The number of times each dataplane benchmark cell is measured. The benchmark is skipped if 0.")
+	flag.StringVar(&TestContext.BenchmarkBaseline, "benchmark-baseline", "",
+		"If set, the benchmark results are compared with the baseline for the same topology in this JSON file.")
+	flag.BoolVar(&TestContext.BenchmarkRefreshBaseline, "benchmark-refresh-baseline", false,
+		"If true, the baseline for the topology in the benchmark baseline file is updated with the results, instead of compared.")
+	flag.Float64Var(&TestContext.BenchmarkThroughputTolerance, "benchmark-throughput-tolerance", 0.1,
+		"The fraction by which a benchmark throughput may drop below the baseline before it's reported as a regression.")
+	flag.Float64Var(&TestContext.BenchmarkLatencyTolerance, "benchmark-latency-tolerance", 0.2,
+		"The fraction by which a benchmark latency may rise above the baseline before it's reported as a regression.")
+	flag.BoolVar(&TestContext.BenchmarkFailOnRegression, "benchmark-fail-on-regression", false,
+		"If true, benchmark regressions fail the benchmark, otherwise they're only reported as warnings.")
 }
 
+// ValidateFlags loads the configuration file, if any, applies the environment variable overrides and validates the
+// resulting TestContext. It exits on failure.
 func ValidateFlags(t *TestContextType) {
+	if err := loadTestContextConfig(t, configFile); err != nil {
+		klog.Fatalf("error loading the configuration: %v", err)
+	}
+
 	if t.KubeConfig == "" && len(t.KubeConfigs) == 0 {
 		klog.Fatalf("kubeconfig parameter or KUBECONFIG environment variable is required")
 	}
@@ -87,6 +150,10 @@ func ValidateFlags(t *TestContextType) {
 	if len(t.KubeContexts) < 1 && len(t.KubeConfigs) < 1 {
 		klog.Fatalf("at least one kubernetes context must be specified.")
 	}
+
+	if err := validateTestContext(t); err != nil {
+		klog.Fatalf("invalid configuration: %v", err)
+	}
 }
 
 func (t *TestContextType) OperationTimeoutToDuration() time.Duration {
@@ -0,0 +1,195 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gcustom"
+	"github.com/onsi/gomega/types"
+	"github.com/pkg/errors"
+)
+
+const throughputAttemptsPrefix = "[attempts]"
+
+// iperf3SummaryFilter is the jq filter summarizing the iperf3 JSON output in the termination log of a ThroughputClientPod,
+// for humans and consumers which used to read the iperf3 text output there. It mimics the "[SUM]" lines of the text
+// output, with rounded values.
+const iperf3SummaryFilter = `if .error then "iperf3: error - \(.error)" else .end |
+(.sum_sent | "[SUM]   0.00-\(.seconds * 100 | round / 100)  sec  \(.bytes / 1048576 | round) MBytes` +
+	`  \(.bits_per_second / 1e6 | round) Mbits/sec  \(.retransmits)  sender"),
+(.sum_received | "[SUM]   0.00-\(.seconds * 100 | round / 100)  sec  \(.bytes / 1048576 | round) MBytes` +
+	`  \(.bits_per_second / 1e6 | round) Mbits/sec  receiver") end`
+
+// ThroughputStream is the throughput measured on one of the parallel streams of a throughput test.
+type ThroughputStream struct {
+	SenderBitsPerSecond   float64
+	ReceiverBitsPerSecond float64
+	Retransmits           int
+}
+
+// ThroughputResult is the outcome of the iperf3 throughput test run by a ThroughputClientPod.
+type ThroughputResult struct {
+	SenderBitsPerSecond   float64
+	ReceiverBitsPerSecond float64
+	// Retransmits is the total number of TCP retransmits on the sender side.
+	Retransmits int
+	Duration    time.Duration
+	// Attempts is the number of iperf3 runs, including the successful one.
+	Attempts int
+	Streams  []ThroughputStream
+}
+
+// iperf3Summary is the part of a stream or total summary in the iperf3 JSON output used by ThroughputResult.
+type iperf3Summary struct {
+	Seconds       float64 `json:"seconds"`
+	BitsPerSecond float64 `json:"bits_per_second"`
+	Retransmits   int     `json:"retransmits"`
+}
+
+// iperf3Output is the part of the iperf3 JSON output used by ThroughputResult.
+type iperf3Output struct {
+	End struct {
+		Streams []struct {
+			Sender   iperf3Summary `json:"sender"`
+			Receiver iperf3Summary `json:"receiver"`
+		} `json:"streams"`
+		SumSent     iperf3Summary `json:"sum_sent"`
+		SumReceived iperf3Summary `json:"sum_received"`
+	} `json:"end"`
+	Error string `json:"error"`
+}
+
+// ThroughputResult returns the result of the throughput test run by this ThroughputClientPod, once it has finished.
+func (np *NetworkPod) ThroughputResult(ctx context.Context) *ThroughputResult {
+	result, err := np.ThroughputResultOrError(ctx)
+	Expect(err).NotTo(HaveOccurred())
+
+	return result
+}
+
+// ThroughputResultOrError is like ThroughputResult but returns an error instead of failing via Gomega.
+func (np *NetworkPod) ThroughputResultOrError(ctx context.Context) (*ThroughputResult, error) {
+	log, err := np.GetLogOrError(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseThroughputResult(log, np.TerminationMessage)
+}
+
+// parseThroughputResult parses the iperf3 JSON output, and the number of attempts from the termination message.
+func parseThroughputResult(output, terminationMessage string) (*ThroughputResult, error) {
+	iperf3 := &iperf3Output{}
+
+	if err := json.Unmarshal([]byte(output), iperf3); err != nil {
+		return nil, errors.Wrapf(err, "error parsing the iperf3 output %q", output)
+	}
+
+	if iperf3.Error != "" {
+		return nil, fmt.Errorf("the throughput test failed: %s", iperf3.Error)
+	}
+
+	result := &ThroughputResult{
+		SenderBitsPerSecond:   iperf3.End.SumSent.BitsPerSecond,
+		ReceiverBitsPerSecond: iperf3.End.SumReceived.BitsPerSecond,
+		Retransmits:           iperf3.End.SumSent.Retransmits,
+		Duration:              time.Duration(iperf3.End.SumSent.Seconds * float64(time.Second)),
+		Attempts:              1,
+		Streams:               make([]ThroughputStream, len(iperf3.End.Streams)),
+	}
+
+	for i := range iperf3.End.Streams {
+		result.Streams[i] = ThroughputStream{
+			SenderBitsPerSecond:   iperf3.End.Streams[i].Sender.BitsPerSecond,
+			ReceiverBitsPerSecond: iperf3.End.Streams[i].Receiver.BitsPerSecond,
+			Retransmits:           iperf3.End.Streams[i].Sender.Retransmits,
+		}
+	}
+
+	if index := strings.LastIndex(terminationMessage, throughputAttemptsPrefix); index >= 0 {
+		fields := strings.Fields(terminationMessage[index+len(throughputAttemptsPrefix):])
+		if len(fields) > 0 {
+			if attempts, err := strconv.Atoi(fields[0]); err == nil {
+				result.Attempts = attempts
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// String formats the result for logging, e.g. "sender 9.41 Gbits/sec, receiver 9.39 Gbits/sec, 12 retransmits".
+func (r *ThroughputResult) String() string {
+	return fmt.Sprintf("sender %s, receiver %s, %d retransmits, %v over %d stream(s), %d attempt(s)",
+		FormatBitsPerSecond(r.SenderBitsPerSecond), FormatBitsPerSecond(r.ReceiverBitsPerSecond), r.Retransmits, r.Duration,
+		len(r.Streams), r.Attempts)
+}
+
+// FormatBitsPerSecond formats a bandwidth with the largest unit which keeps it at least 1, e.g. "9.41 Gbits/sec".
+func FormatBitsPerSecond(bitsPerSecond float64) string {
+	units := []string{"bits/sec", "Kbits/sec", "Mbits/sec", "Gbits/sec", "Tbits/sec"}
+
+	unit := 0
+	for bitsPerSecond >= 1000 && unit < len(units)-1 {
+		bitsPerSecond /= 1000
+		unit++
+	}
+
+	return fmt.Sprintf("%.2f %s", bitsPerSecond, units[unit])
+}
+
+// HaveMinimumThroughput succeeds if the actual *ThroughputResult has a receiver bandwidth of at least the given bits per
+// second, e.g.
+//
+//	Expect(clientPod.ThroughputResult(ctx)).To(HaveMinimumThroughput(500e6))
+func HaveMinimumThroughput(bitsPerSecond float64) types.GomegaMatcher {
+	return gcustom.MakeMatcher(func(result *ThroughputResult) (bool, error) {
+		if result == nil {
+			return false, errors.New("the throughput result is nil")
+		}
+
+		return result.ReceiverBitsPerSecond >= bitsPerSecond, nil
+	}).WithTemplate("Expected throughput\n{{.FormattedActual}}\n{{.To}} be at least {{.Data}}", FormatBitsPerSecond(bitsPerSecond))
+}
+
+// HaveMinimumStreamThroughput succeeds if every stream of the actual *ThroughputResult has a receiver bandwidth of at
+// least the given bits per second, to detect streams starved by the others.
+func HaveMinimumStreamThroughput(bitsPerSecond float64) types.GomegaMatcher {
+	return gcustom.MakeMatcher(func(result *ThroughputResult) (bool, error) {
+		if result == nil {
+			return false, errors.New("the throughput result is nil")
+		}
+
+		for i := range result.Streams {
+			if result.Streams[i].ReceiverBitsPerSecond < bitsPerSecond {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}).WithTemplate("Expected every stream of throughput\n{{.FormattedActual}}\n{{.To}} be at least {{.Data}}",
+		FormatBitsPerSecond(bitsPerSecond))
+}
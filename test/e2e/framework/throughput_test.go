@@ -0,0 +1,90 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+)
+
+func readTestData(name string) string {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	Expect(err).NotTo(HaveOccurred())
+
+	return string(data)
+}
+
+var _ = Describe("parseThroughputResult", func() {
+	// The termination message of a ThroughputClientPod, with the iperf3 summary of testdata/iperf3.json.
+	const terminationMessage = "[going to retry]\n" +
+		"[attempts] 2\n" +
+		"[SUM]   0.00-10  sec  1090 MBytes  914 Mbits/sec  23  sender\n" +
+		"[SUM]   0.00-10  sec  1086 MBytes  911 Mbits/sec  receiver\n"
+
+	It("should parse the totals and the streams of the iperf3 JSON output", func() {
+		Expect(framework.ParseThroughputResult(readTestData("iperf3.json"), terminationMessage)).To(Equal(&framework.ThroughputResult{
+			SenderBitsPerSecond:   914347059,
+			ReceiverBitsPerSecond: 910862223.3,
+			Retransmits:           23,
+			Duration:              10000123 * time.Microsecond,
+			Attempts:              2,
+			Streams: []framework.ThroughputStream{
+				{SenderBitsPerSecond: 469756270.6, ReceiverBitsPerSecond: 468012510.2, Retransmits: 14},
+				{SenderBitsPerSecond: 444590788.4, ReceiverBitsPerSecond: 442850790.1, Retransmits: 9},
+			},
+		}))
+	})
+
+	DescribeTable("the number of attempts",
+		func(terminationMessage string, expected int) {
+			result, err := framework.ParseThroughputResult(readTestData("iperf3.json"), terminationMessage)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Attempts).To(Equal(expected))
+		},
+		Entry("first attempt", "[attempts] 1\n", 1),
+		Entry("after retries", terminationMessage, 2),
+		Entry("missing, from an older image", "", 1),
+		Entry("invalid", "[attempts] \n", 1),
+	)
+
+	It("should return the error reported by iperf3", func() {
+		_, err := framework.ParseThroughputResult(readTestData("iperf3-error.json"), "[attempts] 3\n")
+		Expect(err).To(MatchError(ContainSubstring("the throughput test failed: unable to connect to server")))
+	})
+
+	It("should return an error if the output isn't JSON", func() {
+		_, err := framework.ParseThroughputResult("iperf3: error - unable to connect to server: Connection refused\n", "")
+		Expect(err).To(MatchError(ContainSubstring("error parsing the iperf3 output")))
+	})
+})
+
+var _ = DescribeTable("FormatBitsPerSecond",
+	func(bitsPerSecond float64, expected string) {
+		Expect(framework.FormatBitsPerSecond(bitsPerSecond)).To(Equal(expected))
+	},
+	Entry("bits", 999.0, "999.00 bits/sec"),
+	Entry("megabits", 910862223.3, "910.86 Mbits/sec"),
+	Entry("gigabits", 9.41e9, "9.41 Gbits/sec"),
+	Entry("beyond the largest unit", 2.5e15, "2500.00 Tbits/sec"),
+)
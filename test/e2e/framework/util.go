@@ -66,6 +66,7 @@ func NewRequirement(key string, op selection.Operator, vals []string) labels.Req
 
 // FindOtherClusterIndex looks within the environment for another cluster
 // besides the one provided and returns its index or -1 if none other is found.
+// Framework.ForEachClusterPair is better suited to topologies with more than two clusters.
 func FindOtherClusterIndex(mainCluster int) int {
 	for idx := range TestContext.ClusterIDs {
 		if idx != mainCluster {
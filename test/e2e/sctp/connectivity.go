@@ -0,0 +1,107 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sctp implements an SCTP/IP connectivity test.
+package sctp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+)
+
+// EndpointType is the kind of address at which the connector pod reaches the listener pod.
+type EndpointType = framework.EndpointType
+
+const (
+	PodIP     = framework.PodIPEndpoint
+	ServiceIP = framework.ServiceIPEndpoint
+	// GlobalServiceIP is the Globalnet IP allocated to the exported service pointing to the listener pod.
+	GlobalServiceIP = framework.GlobalServiceIPEndpoint
+	// GlobalPodIP is the Globalnet IP allocated to the listener pod, as a backend of an exported headless service.
+	GlobalPodIP = framework.GlobalPodIPEndpoint
+)
+
+// ConnectivityTestParams describes a connectivity test between a connector pod and a listener pod.
+type ConnectivityTestParams = framework.ConnectivityTestParams
+
+// RunConnectivityTest verifies that a connector pod and a listener pod can exchange data over SCTP, in both directions.
+// The spec is skipped if the listener's node doesn't support SCTP.
+func RunConnectivityTest(ctx context.Context, p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod) {
+	listenerPod, connectorPod, err := RunConnectivityTestOrError(ctx, p)
+	skipIfNotSupported(err)
+	Expect(err).NotTo(HaveOccurred())
+
+	// Return the pods in case further verification is needed
+	return listenerPod, connectorPod
+}
+
+// RunConnectivityTestOrError is like RunConnectivityTest but returns an error instead of failing via Gomega, including
+// framework.ErrSCTPNotSupported instead of skipping. The pods are returned whenever they were created, even if the
+// verification failed, so they can be inspected further.
+func RunConnectivityTestOrError(ctx context.Context, p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod, error) {
+	return framework.RunConnectivityTestOrError(ctx, framework.SCTPConnectivity, p)
+}
+
+// RunNoConnectivityTest verifies that a connector pod and a listener pod can't exchange data over SCTP. The spec is
+// skipped if the listener's node doesn't support SCTP.
+func RunNoConnectivityTest(ctx context.Context, p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod) {
+	listenerPod, connectorPod, err := RunNoConnectivityTestOrError(ctx, p)
+	skipIfNotSupported(err)
+	Expect(err).NotTo(HaveOccurred())
+
+	// Return the pods in case further verification is needed
+	return listenerPod, connectorPod
+}
+
+// RunNoConnectivityTestOrError is like RunNoConnectivityTest but returns an error instead of failing via Gomega,
+// including framework.ErrSCTPNotSupported instead of skipping.
+func RunNoConnectivityTestOrError(ctx context.Context, p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod, error) {
+	return framework.RunNoConnectivityTestOrError(ctx, framework.SCTPConnectivity, p, verifyNoConnectivity)
+}
+
+func verifyNoConnectivity(listenerPod, connectorPod *framework.NetworkPod) error {
+	framework.By("Verifying that listener pod times out without receiving the connector's data")
+
+	if strings.Contains(listenerPod.TerminationMessage, connectorPod.Config.Data) || listenerPod.TerminationCode == 0 {
+		return fmt.Errorf("expected listener pod %q to time out but it exited with code %d",
+			listenerPod.Pod.Name, listenerPod.TerminationCode)
+	}
+
+	framework.By("Verifying that connector pod exits with zero code but times out")
+
+	if strings.Contains(connectorPod.TerminationMessage, listenerPod.Config.Data) || connectorPod.TerminationCode != 0 {
+		return fmt.Errorf("expected connector pod %q to time out but it exited with code %d",
+			connectorPod.Pod.Name, connectorPod.TerminationCode)
+	}
+
+	return nil
+}
+
+// skipf skips the current spec; the tests replace it since skipping from a spec can't be undone.
+var skipf = framework.Skipf
+
+func skipIfNotSupported(err error) {
+	if errors.Is(err, framework.ErrSCTPNotSupported) {
+		skipf("%v", err)
+	}
+}
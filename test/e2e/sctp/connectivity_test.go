@@ -0,0 +1,55 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sctp_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+	"github.com/submariner-io/shipyard/test/e2e/sctp"
+)
+
+var _ = Describe("skipIfNotSupported", func() {
+	var skipped []string
+
+	BeforeEach(func() {
+		skipped = nil
+		DeferCleanup(sctp.SetSkipFunction(func(format string, args ...interface{}) {
+			skipped = append(skipped, fmt.Sprintf(format, args...))
+		}))
+	})
+
+	It("should skip the spec if SCTP isn't supported", func() {
+		sctp.SkipIfNotSupported(errors.Wrapf(framework.ErrSCTPNotSupported, "on cluster %q", "east"))
+		Expect(skipped).To(Equal([]string{`on cluster "east": SCTP is not supported`}))
+	})
+
+	It("should not skip the spec on other errors", func() {
+		sctp.SkipIfNotSupported(errors.New("the listener pod failed"))
+		Expect(skipped).To(BeEmpty())
+	})
+
+	It("should not skip the spec without an error", func() {
+		sctp.SkipIfNotSupported(nil)
+		Expect(skipped).To(BeEmpty())
+	})
+})
@@ -20,130 +20,73 @@ limitations under the License.
 package tcp
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	. "github.com/onsi/gomega"
 	"github.com/submariner-io/shipyard/test/e2e/framework"
-	v1 "k8s.io/api/core/v1"
 )
 
-type EndpointType int
+// EndpointType is the kind of address at which the connector pod reaches the listener pod.
+type EndpointType = framework.EndpointType
 
 const (
-	PodIP EndpointType = iota
-	ServiceIP
+	PodIP     = framework.PodIPEndpoint
+	ServiceIP = framework.ServiceIPEndpoint
 	// TODO: Remove GlobalIP once all consumer code switches to GlobalServiceIP.
-	GlobalIP
-	GlobalPodIP
-	GlobalServiceIP = GlobalIP
+	GlobalIP = framework.GlobalServiceIPEndpoint
+	// GlobalPodIP is the Globalnet IP allocated to the listener pod, as a backend of an exported headless service.
+	GlobalPodIP = framework.GlobalPodIPEndpoint
+	// ClustersetServiceName is the clusterset DNS name of the exported service pointing to the listener pod.
+	ClustersetServiceName = framework.ClustersetServiceNameEndpoint
+	GlobalServiceIP       = GlobalIP
 )
 
-type ConnectivityTestParams struct {
-	Framework             *framework.Framework
-	Networking            framework.NetworkingType
-	ConnectionTimeout     uint
-	ConnectionAttempts    uint
-	FromCluster           framework.ClusterIndex
-	FromClusterScheduling framework.NetworkPodScheduling
-	ToCluster             framework.ClusterIndex
-	ToClusterScheduling   framework.NetworkPodScheduling
-	ToEndpointType        EndpointType
-}
-
-func RunConnectivityTest(p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod) {
-	if p.ConnectionTimeout == 0 {
-		p.ConnectionTimeout = framework.TestContext.ConnectionTimeout
-	}
-
-	if p.ConnectionAttempts == 0 {
-		p.ConnectionAttempts = framework.TestContext.ConnectionAttempts
-	}
-
-	listenerPod, connectorPod := createPods(&p)
-	listenerPod.CheckSuccessfulFinish()
-	connectorPod.CheckSuccessfulFinish()
-
-	framework.By("Verifying that the listener got the connector's data and the connector got the listener's data")
-	Expect(listenerPod.TerminationMessage).To(ContainSubstring(connectorPod.Config.Data))
-	Expect(connectorPod.TerminationMessage).To(ContainSubstring(listenerPod.Config.Data))
+// ConnectivityTestParams describes a connectivity test between a connector pod and a listener pod.
+type ConnectivityTestParams = framework.ConnectivityTestParams
 
-	if p.Networking == framework.PodNetworking {
-		framework.By("Verifying the output of listener pod which must contain the source IP")
-		Expect(listenerPod.TerminationMessage).To(ContainSubstring(connectorPod.Pod.Status.PodIP))
-	}
+func RunConnectivityTest(ctx context.Context, p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod) {
+	listenerPod, connectorPod, err := RunConnectivityTestOrError(ctx, p)
+	Expect(err).NotTo(HaveOccurred())
 
 	// Return the pods in case further verification is needed
 	return listenerPod, connectorPod
 }
 
-func RunNoConnectivityTest(p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod) {
-	if p.ConnectionTimeout == 0 {
-		p.ConnectionTimeout = 5
-	}
-
-	if p.ConnectionAttempts == 0 {
-		p.ConnectionAttempts = 1
-	}
-
-	listenerPod, connectorPod := createPods(&p)
-
-	framework.By("Verifying that listener pod exits with non-zero code and timed out message")
-	Expect(listenerPod.TerminationMessage).To(ContainSubstring("nc: timeout"))
-	Expect(listenerPod.TerminationCode).To(Equal(int32(1)))
+// RunConnectivityTestOrError is like RunConnectivityTest but returns an error instead of failing via Gomega. The pods are
+// returned whenever they were created, even if the verification failed, so they can be inspected further.
+func RunConnectivityTestOrError(ctx context.Context, p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod, error) {
+	return framework.RunConnectivityTestOrError(ctx, framework.TCPConnectivity, p)
+}
 
-	framework.By("Verifying that connector pod exists with zero code but times out")
-	Expect(connectorPod.TerminationMessage).To(ContainSubstring("Connection timed out"))
-	Expect(connectorPod.TerminationCode).To(Equal(int32(0)))
+func RunNoConnectivityTest(ctx context.Context, p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod) {
+	listenerPod, connectorPod, err := RunNoConnectivityTestOrError(ctx, p)
+	Expect(err).NotTo(HaveOccurred())
 
 	// Return the pods in case further verification is needed
 	return listenerPod, connectorPod
 }
 
-func createPods(p *ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod) {
-	framework.By(fmt.Sprintf("Creating a listener pod in cluster %q, which will wait for a handshake over TCP",
-		framework.TestContext.ClusterIDs[p.ToCluster]))
-
-	listenerPod := p.Framework.NewNetworkPod(&framework.NetworkPodConfig{
-		Type:               framework.ListenerPod,
-		Cluster:            p.ToCluster,
-		Scheduling:         p.ToClusterScheduling,
-		ConnectionTimeout:  p.ConnectionTimeout,
-		ConnectionAttempts: p.ConnectionAttempts,
-	})
-
-	remoteIP := listenerPod.Pod.Status.PodIP
-	var service *v1.Service
+// RunNoConnectivityTestOrError is like RunNoConnectivityTest but returns an error instead of failing via Gomega.
+func RunNoConnectivityTestOrError(ctx context.Context, p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod, error) {
+	return framework.RunNoConnectivityTestOrError(ctx, framework.TCPConnectivity, p, verifyNoConnectivity)
+}
 
-	if p.ToEndpointType == ServiceIP {
-		framework.By(fmt.Sprintf("Pointing a service ClusterIP to the listener pod in cluster %q",
-			framework.TestContext.ClusterIDs[p.ToCluster]))
+func verifyNoConnectivity(listenerPod, connectorPod *framework.NetworkPod) error {
+	framework.By("Verifying that listener pod exits with non-zero code and timed out message")
 
-		service = listenerPod.CreateService()
-		remoteIP = service.Spec.ClusterIP
+	if !strings.Contains(listenerPod.TerminationMessage, "nc: timeout") || listenerPod.TerminationCode != 1 {
+		return fmt.Errorf("expected listener pod %q to time out but it exited with code %d",
+			listenerPod.Pod.Name, listenerPod.TerminationCode)
 	}
 
-	framework.Logf("Will send traffic to IP: %v", remoteIP)
-
-	framework.By(fmt.Sprintf("Creating a connector pod in cluster %q, which will attempt the specific UUID handshake over TCP",
-		framework.TestContext.ClusterIDs[p.FromCluster]))
-
-	connectorPod := p.Framework.NewNetworkPod(&framework.NetworkPodConfig{
-		Type:               framework.ConnectorPod,
-		Cluster:            p.FromCluster,
-		Scheduling:         p.FromClusterScheduling,
-		RemoteIP:           remoteIP,
-		ConnectionTimeout:  p.ConnectionTimeout,
-		ConnectionAttempts: p.ConnectionAttempts,
-		Networking:         p.Networking,
-	})
-
-	framework.By(fmt.Sprintf("Waiting for the connector pod %q to exit, returning what connector sent", connectorPod.Pod.Name))
-	connectorPod.AwaitFinish()
-
-	framework.By(fmt.Sprintf("Waiting for the listener pod %q to exit, returning what listener sent", listenerPod.Pod.Name))
-	listenerPod.AwaitFinish()
+	framework.By("Verifying that connector pod exists with zero code but times out")
 
-	framework.Logf("Connector pod has IP: %s", connectorPod.Pod.Status.PodIP)
+	if !strings.Contains(connectorPod.TerminationMessage, "Connection timed out") || connectorPod.TerminationCode != 0 {
+		return fmt.Errorf("expected connector pod %q to time out but it exited with code %d",
+			connectorPod.Pod.Name, connectorPod.TerminationCode)
+	}
 
-	return listenerPod, connectorPod
+	return nil
 }
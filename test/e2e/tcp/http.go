@@ -0,0 +1,120 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+)
+
+// RunHTTPConnectivityTest verifies that a client pod gets a successful HTTP response, with the expected body, from a
+// server pod. The server is reached at its pod IP, its service ClusterIP, its exported service's clusterset name or its
+// Globalnet IPs, depending on ToEndpointType.
+func RunHTTPConnectivityTest(ctx context.Context, p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod,
+	*framework.HTTPProbeResult,
+) {
+	serverPod, clientPod, result, err := RunHTTPConnectivityTestOrError(ctx, p)
+	Expect(err).NotTo(HaveOccurred())
+
+	// Return the pods and the probe result in case further verification is needed
+	return serverPod, clientPod, result
+}
+
+// RunHTTPConnectivityTestOrError is like RunHTTPConnectivityTest but returns an error instead of failing via Gomega. The
+// pods are returned whenever they were created, even if the verification failed, so they can be inspected further.
+func RunHTTPConnectivityTestOrError(ctx context.Context, p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod,
+	*framework.HTTPProbeResult, error,
+) {
+	if p.ConnectionTimeout == 0 {
+		p.ConnectionTimeout = framework.TestContext.ConnectionTimeout
+	}
+
+	if p.ConnectionAttempts == 0 {
+		p.ConnectionAttempts = framework.TestContext.ConnectionAttempts
+	}
+
+	framework.By(fmt.Sprintf("Creating an HTTP server pod in cluster %q", framework.TestContext.ClusterIDs[p.ToCluster]))
+
+	serverPod, err := p.Framework.NewNetworkPodOrError(ctx, &framework.NetworkPodConfig{
+		Type:       framework.HTTPServerPod,
+		Cluster:    p.ToCluster,
+		Scheduling: p.ToClusterScheduling,
+		IPFamily:   p.IPFamily,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	remoteHost, err := serverPod.RemoteAddressOrError(ctx, p.ToEndpointType)
+	if err != nil {
+		return serverPod, nil, nil, err
+	}
+
+	framework.Logf("Will send HTTP requests to: %v", remoteHost)
+
+	framework.By(fmt.Sprintf("Creating an HTTP client pod in cluster %q", framework.TestContext.ClusterIDs[p.FromCluster]))
+
+	clientPod, err := p.Framework.NewNetworkPodOrError(ctx, &framework.NetworkPodConfig{
+		Type:               framework.HTTPClientPod,
+		Cluster:            p.FromCluster,
+		Scheduling:         p.FromClusterScheduling,
+		RemoteIP:           remoteHost,
+		ConnectionTimeout:  p.ConnectionTimeout,
+		ConnectionAttempts: p.ConnectionAttempts,
+		Networking:         p.Networking,
+		IPFamily:           p.IPFamily,
+	})
+	if err != nil {
+		return serverPod, nil, nil, err
+	}
+
+	framework.By(fmt.Sprintf("Waiting for the HTTP client pod %q to exit, returning the response", clientPod.Pod.Name))
+	clientPod.AwaitFinish(ctx)
+
+	if err := clientPod.CheckSuccessfulFinishOrError(); err != nil {
+		return serverPod, clientPod, nil, err
+	}
+
+	result, err := clientPod.HTTPProbeResult()
+	if err != nil {
+		return serverPod, clientPod, nil, err
+	}
+
+	framework.Logf("HTTP response %d, DNS lookup %v, connect %v, first byte %v, total %v", result.StatusCode, result.DNSLookup,
+		result.Connect, result.FirstByte, result.Total)
+
+	framework.By("Verifying that the HTTP client got a successful response from the server")
+
+	if result.StatusCode != http.StatusOK {
+		return serverPod, clientPod, result, fmt.Errorf("HTTP client pod %q got status code %d instead of %d: %s",
+			clientPod.Pod.Name, result.StatusCode, http.StatusOK, result.Error)
+	}
+
+	if !strings.Contains(result.Body, serverPod.Config.Data) {
+		return serverPod, clientPod, result, fmt.Errorf("HTTP client pod %q did not receive the server's data %q, got %q",
+			clientPod.Pod.Name, serverPod.Config.Data, result.Body)
+	}
+
+	return serverPod, clientPod, result, nil
+}
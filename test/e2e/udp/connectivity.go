@@ -0,0 +1,92 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package udp implements a UDP/IP connectivity test.
+package udp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/shipyard/test/e2e/framework"
+)
+
+// EndpointType is the kind of address at which the connector pod reaches the listener pod.
+type EndpointType = framework.EndpointType
+
+const (
+	PodIP     = framework.PodIPEndpoint
+	ServiceIP = framework.ServiceIPEndpoint
+	// GlobalServiceIP is the Globalnet IP allocated to the exported service pointing to the listener pod.
+	GlobalServiceIP = framework.GlobalServiceIPEndpoint
+	// GlobalPodIP is the Globalnet IP allocated to the listener pod, as a backend of an exported headless service.
+	GlobalPodIP = framework.GlobalPodIPEndpoint
+)
+
+// ConnectivityTestParams describes a connectivity test between a connector pod and a listener pod.
+type ConnectivityTestParams = framework.ConnectivityTestParams
+
+// RunConnectivityTest verifies that a connector pod and a listener pod can exchange data over UDP, in both directions.
+func RunConnectivityTest(ctx context.Context, p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod) {
+	listenerPod, connectorPod, err := RunConnectivityTestOrError(ctx, p)
+	Expect(err).NotTo(HaveOccurred())
+
+	// Return the pods in case further verification is needed
+	return listenerPod, connectorPod
+}
+
+// RunConnectivityTestOrError is like RunConnectivityTest but returns an error instead of failing via Gomega. The pods are
+// returned whenever they were created, even if the verification failed, so they can be inspected further.
+func RunConnectivityTestOrError(ctx context.Context, p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod, error) {
+	return framework.RunConnectivityTestOrError(ctx, framework.UDPConnectivity, p)
+}
+
+// RunNoConnectivityTest verifies that a connector pod and a listener pod can't exchange data over UDP.
+func RunNoConnectivityTest(ctx context.Context, p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod) {
+	listenerPod, connectorPod, err := RunNoConnectivityTestOrError(ctx, p)
+	Expect(err).NotTo(HaveOccurred())
+
+	// Return the pods in case further verification is needed
+	return listenerPod, connectorPod
+}
+
+// RunNoConnectivityTestOrError is like RunNoConnectivityTest but returns an error instead of failing via Gomega.
+func RunNoConnectivityTestOrError(ctx context.Context, p ConnectivityTestParams) (*framework.NetworkPod, *framework.NetworkPod, error) {
+	return framework.RunNoConnectivityTestOrError(ctx, framework.UDPConnectivity, p, verifyNoConnectivity)
+}
+
+func verifyNoConnectivity(listenerPod, connectorPod *framework.NetworkPod) error {
+	framework.By("Verifying that listener pod exits with non-zero code and timed out message")
+
+	if !strings.Contains(listenerPod.TerminationMessage, "nc: timeout") || listenerPod.TerminationCode != 1 {
+		return fmt.Errorf("expected listener pod %q to time out but it exited with code %d",
+			listenerPod.Pod.Name, listenerPod.TerminationCode)
+	}
+
+	// UDP doesn't report connection failures so the connector completes, without receiving anything.
+	framework.By("Verifying that connector pod exits with zero code without receiving the listener's data")
+
+	if strings.Contains(connectorPod.TerminationMessage, listenerPod.Config.Data) || connectorPod.TerminationCode != 0 {
+		return fmt.Errorf("expected connector pod %q not to receive any data but it exited with code %d",
+			connectorPod.Pod.Name, connectorPod.TerminationCode)
+	}
+
+	return nil
+}